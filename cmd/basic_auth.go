@@ -0,0 +1,24 @@
+package main
+
+import (
+	"mygram/config"
+	"mygram/pkg/helper"
+	"mygram/repository"
+	"mygram/service"
+)
+
+// basicAuthService is nil unless the users table or an htpasswd file can
+// check Basic credentials.
+func basicAuthService(cfg config.BasicAuthConfig, userRepo repository.UserQuery, guard service.LoginGuard, passwords helper.PasswordPolicy) (service.BasicAuthService, error) {
+	if !cfg.Users && cfg.HtpasswdFile == "" {
+		return nil, nil
+	}
+	htpasswd := map[string]string{}
+	if cfg.HtpasswdFile != "" {
+		var err error
+		if htpasswd, err = helper.LoadHtpasswd(cfg.HtpasswdFile); err != nil {
+			return nil, err
+		}
+	}
+	return service.NewBasicAuthService(userRepo, guard, cfg, htpasswd, passwords), nil
+}
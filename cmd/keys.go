@@ -0,0 +1,24 @@
+package main
+
+import (
+	"mygram/config"
+	"mygram/pkg/helper"
+)
+
+// keySet builds the token signing keys, falling back to the HS512 secret
+// when no asymmetric keys are configured.
+func keySet(cfg config.JWTConfig) (*helper.KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		return helper.NewHMACKeySet(cfg.Secret), nil
+	}
+
+	keys := []helper.SigningKey{}
+	for _, k := range cfg.Keys {
+		key, err := helper.LoadSigningKey(k.ID, k.PrivateKeyFile, k.ActiveFrom)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return helper.NewKeySet(keys, cfg.KeyOverlap)
+}
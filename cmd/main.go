@@ -1,12 +1,20 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
+	"mygram/config"
 	"mygram/handler"
 	"mygram/infrastructure"
+	"mygram/middleware"
 	"mygram/model"
 	"mygram/pkg"
 	"mygram/pkg/helper"
@@ -22,6 +30,13 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// shutdownTimeout bounds the wait for the requests in flight on shutdown,
+// variantsShutdownTimeout the one for the queued photo variants after that.
+const (
+	shutdownTimeout         = 30 * time.Second
+	variantsShutdownTimeout = 30 * time.Second
+)
+
 // @title			GO DTS USER API DUCUMENTATION
 // @version		2.0
 // @description	golong kominfo 006 api documentation
@@ -38,7 +53,22 @@ func main() {
 	// requirement technical:
 	// [x] middleware untuk recover ketika panic
 	// [x] mengecheck basic auth
-	server()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load(os.Getenv("MYGRAM_CONFIG"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	keys, err := keySet(cfg.JWT)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server(cfg, keys)
 }
 
 // Product:
@@ -46,24 +76,34 @@ func main() {
 // authentication bisa dilakukan dengan login
 // ketika user login, akan memunculkan JWT ketika success
 
-func server() {
+func server(cfg config.Config, keys *helper.KeySet) {
+	gin.SetMode(cfg.App.GinMode)
 	g := gin.Default()
 	g.Use(gin.Recovery())
+	// ClientIP drives the per IP login throttle, only trust known proxies
+	if err := g.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("invalid app.trusted_proxies: %v", err)
+	}
 
 	// /public => generate JWT public
 	g.GET("/public", func(ctx *gin.Context) {
+		jti, err := helper.GenerateRandomToken(16)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: "error generating public token"})
+			return
+		}
 		now := time.Now()
 
 		claim := model.StandardClaim{
-			Jti: fmt.Sprintf("%v", time.Now().UnixNano()),
+			Jti: jti,
 			Iss: "go-middleware",
 			Aud: "golang-006",
-			Sub: "public-token",
+			Sub: model.SUBJECT_PUBLIC_TOKEN,
 			Exp: uint64(now.Add(time.Hour).Unix()),
 			Iat: uint64(now.Unix()),
 			Nbf: uint64(now.Unix()),
 		}
-		token, err := helper.GenerateToken(claim)
+		token, err := helper.GenerateToken(claim, keys)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{
 				Message: "error generating public token",
@@ -75,36 +115,98 @@ func server() {
 	})
 
 	usersGroup := g.Group("/users")
-	// usersGroup.Use(middleware.CheckAuthBasic)
-	// usersGroup.Use(middleware.CheckAuthBearer)
 
 	// dependency injection
 	// dig by uber
 	// wire
 
 	// https://s8sg.medium.com/solid-principle-in-go-e1a624290346
-	gorm := infrastructure.NewGormPostgres()
+	gorm := infrastructure.NewGormPostgres(cfg.Database)
+
+	// sessions
+	refreshTokenRepo := repository.NewRefreshTokenQuery(gorm)
+	revocationRepo := repository.NewCachedTokenRevocationQuery(repository.NewTokenRevocationQuery(gorm), cfg.JWT.RevocationCacheTTL)
+	sessionSvc := service.NewSessionService(revocationRepo, refreshTokenRepo)
 	userRepo := repository.NewUserQuery(gorm)
+	apiKeyRepo := repository.NewAPIKeyQuery(gorm)
+	apiKeySvc := service.NewAPIKeyService(userRepo, apiKeyRepo)
 	// userRepoMongo := repository.NewUserQueryMongo()
-	userSvc := service.NewUserService(userRepo)
-	userHdl := handler.NewUserHandler(userSvc)
-	userRouter := router.NewUserRouter(usersGroup, userHdl)
+	loginGuard := service.NewLoginGuard(repository.NewLoginAttemptQuery(gorm), cfg.Login)
+	passwords := passwordPolicy(cfg.Password)
+	basicAuthSvc, err := basicAuthService(cfg.BasicAuth, userRepo, loginGuard, passwords)
+	if err != nil {
+		log.Fatal(err)
+	}
+	auth := middleware.NewAuthorization(cfg.JWT, keys, sessionSvc, apiKeySvc, basicAuthSvc, cfg.BasicAuth.Realm)
+	// groupAuth lets the groups listed in basic_auth.groups accept Basic auth
+	groupAuth := func(group string) middleware.Authorization {
+		if slices.Contains(cfg.BasicAuth.Groups, group) {
+			return auth.WithBasic()
+		}
+		return auth
+	}
+	userSvc := service.NewUserService(userRepo, refreshTokenRepo, sessionSvc, loginGuard, passwords, cfg.JWT, keys)
+	mailer, err := infrastructure.NewMailer(cfg.Mail)
+	if err != nil {
+		log.Fatal(err)
+	}
+	verificationSvc := service.NewEmailVerificationService(userRepo, repository.NewEmailVerificationQuery(gorm), mailer, cfg.Mail, cfg.JWT, keys)
+	passwordResetSvc := service.NewPasswordResetService(userRepo, repository.NewPasswordResetQuery(gorm), apiKeyRepo, sessionSvc, mailer, cfg.Mail, passwords)
+	twoFactorSvc := service.NewTwoFactorService(userRepo, repository.NewTwoFactorQuery(gorm), loginGuard, cfg.TwoFactor, cfg.JWT, keys)
+	userHdl := handler.NewUserHandler(userSvc, sessionSvc, verificationSvc, passwordResetSvc, twoFactorSvc)
+	userRouter := router.NewUserRouter(usersGroup, userHdl, auth)
+
+	// openid connect
+	oidcGroup := g.Group("/auth/oidc")
+	oidcSvc := service.NewOIDCService(userRepo, repository.NewOIDCQuery(gorm), infrastructure.NewOIDCProviders(cfg.OIDC), cfg.OIDC, passwords)
+	oidcHdl := handler.NewOIDCHandler(oidcSvc, userSvc, twoFactorSvc)
+	oidcRouter := router.NewOIDCRouter(oidcGroup, oidcHdl)
+
+	// api keys
+	apiKeyGroup := g.Group("/users/me/api-keys")
+	apiKeyHdl := handler.NewAPIKeyHandler(apiKeySvc)
+	apiKeyRouter := router.NewAPIKeyRouter(apiKeyGroup, apiKeyHdl, auth)
 
 	// photo
 	photoGroup := g.Group("/photos")
 
 	photoRepo := repository.NewPhotoQuery(gorm)
-	photoSvc := service.NewPhotosService(photoRepo)
-	photoHdl := handler.NewPhotoHandler(photoSvc)
-	photoRouter := router.NewPhotoRouter(photoGroup, photoHdl)
+	commentRepo := repository.NewCommentsQuery(gorm)
+	blobs, err := infrastructure.NewBlobStore(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fetcher := infrastructure.NewFetcher(cfg.Storage.MaxUploadBytes, cfg.Storage.Variants.FetchTimeout, cfg.Storage.Variants.AllowPrivateURLs)
+	variantSvc := service.NewVariantService(photoRepo, blobs, fetcher, cfg.Storage)
+	variantSvc.Start()
+	photoSvc := service.NewPhotosService(photoRepo, commentRepo, blobs, cfg.Storage, variantSvc)
+	photoHdl := handler.NewPhotoHandler(photoSvc, cfg.Storage.MaxUploadBytes)
+	photoRouter := router.NewPhotoRouter(photoGroup, photoHdl, groupAuth("photos"))
+
+	// blob
+	blobGroup := g.Group("/blobs")
+	blobHdl := handler.NewBlobHandler(service.NewBlobService(blobs, cfg.Storage.SignedURLTTL))
+	blobRouter := router.NewBlobRouter(blobGroup, blobHdl)
+
+	// like
+	likeGroup := g.Group("/photos")
+	likeRepo := repository.NewLikeQuery(gorm)
+	likeSvc := service.NewLikeService(photoRepo, likeRepo)
+	likeHdl := handler.NewLikeHandler(likeSvc)
+	likeRouter := router.NewLikeRouter(likeGroup, likeHdl, groupAuth("photos"))
+
+	// feed
+	feedGroup := g.Group("/feed")
+	feedSvc := service.NewFeedService(photoRepo)
+	feedHdl := handler.NewFeedHandler(feedSvc)
+	feedRouter := router.NewFeedRouter(feedGroup, feedHdl, groupAuth("photos"))
 
 	// comment
 	commentGroup := g.Group("/comments")
 
-	commentRepo := repository.NewCommentsQuery(gorm)
 	commentSvc := service.NewCommentsService(commentRepo)
 	commentHdl := handler.NewCommentHandler(commentSvc)
-	commentRouter := router.NewCommentsRouter(commentGroup, commentHdl)
+	commentRouter := router.NewCommentsRouter(commentGroup, commentHdl, groupAuth("comments"))
 
 	// social medias
 	socialmediaGroup := g.Group("/socialmedias")
@@ -112,15 +214,63 @@ func server() {
 	socialmediaRepo := repository.NewSocialMediasQuery(gorm)
 	socialmediaSvc := service.NewSocialMediasService(socialmediaRepo)
 	socialmediaHdl := handler.NewSocialMediasHandler(socialmediaSvc)
-	socialmediaRouter := router.NewSocialMediasRouter(socialmediaGroup, socialmediaHdl)
+	socialmediaRouter := router.NewSocialMediasRouter(socialmediaGroup, socialmediaHdl, groupAuth("socialmedias"))
+
+	// follows
+	followGroup := g.Group("/users")
+	followRepo := repository.NewFollowQuery(gorm)
+	followSvc := service.NewFollowService(userRepo, followRepo)
+	followHdl := handler.NewFollowHandler(followSvc)
+	followRouter := router.NewFollowRouter(followGroup, followHdl, auth)
+
+	// public profiles, a group of their own so the users auth does not apply
+	profileGroup := g.Group("/users")
+	profileSvc := service.NewProfileService(userRepo, photoRepo, socialmediaRepo, followRepo)
+	profileHdl := handler.NewProfileHandler(profileSvc)
+	profileRouter := router.NewProfileRouter(profileGroup, profileHdl)
 
 	// mount
 	userRouter.Mount()
+	apiKeyRouter.Mount()
+	oidcRouter.Mount()
 	photoRouter.Mount()
+	likeRouter.Mount()
+	blobRouter.Mount()
+	feedRouter.Mount()
 	commentRouter.Mount()
 	socialmediaRouter.Mount()
+	followRouter.Mount()
+	profileRouter.Mount()
+	// jwks => public keys to verify our tokens offline
+	jwksHdl := handler.NewJWKSHandler(keys)
+	g.GET("/.well-known/jwks.json", jwksHdl.GetJWKS)
+
 	// swagger
 	g.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	g.Run(":3000")
+	srv := &http.Server{Addr: cfg.App.Address, Handler: g}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		log.Println("listening on", cfg.App.Address)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("cannot shut down the server", err.Error())
+	}
+	// uploads done before the shutdown still get their variants, what is
+	// left is picked up by the catch-up pass of the next start
+	variantsCtx, cancelVariants := context.WithTimeout(context.Background(), variantsShutdownTimeout)
+	defer cancelVariants()
+	if err := variantSvc.Stop(variantsCtx); err != nil {
+		log.Println("cannot finish the photo variants", err.Error())
+	}
 }
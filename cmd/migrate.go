@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/migrations"
+)
+
+const migrateUsage = `usage: mygram migrate <command>
+
+commands:
+  up              apply every pending migration
+  down [n]        revert the last n applied migrations (default 1)
+  status          list migrations and when they were applied
+  create <name>   write an empty up/down pair into -dir`
+
+// migrate handles `mygram migrate up|down|status|create`.
+func migrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := flags.String("dir", "migrations/sql", "migration directory used by create")
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, migrateUsage) }
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return errors.New("missing migrate command")
+	}
+
+	// create only touches the filesystem, no need for a database
+	if flags.Arg(0) == "create" {
+		if flags.NArg() < 2 {
+			return errors.New("missing migration name")
+		}
+		up, down, err := migrations.Create(*dir, flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Println("created", up)
+		fmt.Println("created", down)
+		return nil
+	}
+
+	cfg, err := config.Load(os.Getenv("MYGRAM_CONFIG"))
+	if err != nil {
+		return err
+	}
+	sqlDB, err := infrastructure.NewGormPostgres(cfg.Database).GetConnection().DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrations.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch flags.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if flags.NArg() > 1 {
+			steps, err = strconv.Atoi(flags.Arg(1))
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", flags.Arg(1))
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, appliedAt)
+		}
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command %q", flags.Arg(0))
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"mygram/config"
+	"mygram/pkg/helper"
+)
+
+func passwordPolicy(cfg config.PasswordConfig) helper.PasswordPolicy {
+	return helper.PasswordPolicy{
+		MinLength:     cfg.MinLength,
+		MaxBytes:      cfg.MaxBytes,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+		RejectCommon:  cfg.RejectCommon,
+		Cost:          cfg.BcryptCost,
+	}
+}
@@ -0,0 +1,638 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+const (
+	ENV_PREFIX = "MYGRAM"
+)
+
+var oidcProviderName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// BasicAuthGroups are the route groups Basic auth can be enabled on.
+var BasicAuthGroups = []string{"photos", "comments", "socialmedias"}
+
+// VariantFormats are the formats photo variants can be encoded to.
+var VariantFormats = []string{"jpeg", "png", "webp"}
+
+type Config struct {
+	App       AppConfig       `mapstructure:"app"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	Login     LoginConfig     `mapstructure:"login"`
+	Mail      MailConfig      `mapstructure:"mail"`
+	Password  PasswordConfig  `mapstructure:"password"`
+	TwoFactor TwoFactorConfig `mapstructure:"two_factor"`
+	OIDC      OIDCConfig      `mapstructure:"oidc"`
+	BasicAuth BasicAuthConfig `mapstructure:"basic_auth"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+}
+
+type AppConfig struct {
+	Address string `mapstructure:"address"`
+	GinMode string `mapstructure:"gin_mode"`
+	// TrustedProxies may set X-Forwarded-For, empty trusts nobody.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+type DatabaseConfig struct {
+	Host            string        `mapstructure:"host"`
+	Port            int           `mapstructure:"port"`
+	User            string        `mapstructure:"user"`
+	Password        string        `mapstructure:"password"`
+	Name            string        `mapstructure:"name"`
+	SSLMode         string        `mapstructure:"sslmode"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+}
+
+type JWTConfig struct {
+	Secret     string        `mapstructure:"secret"`
+	Issuer     string        `mapstructure:"issuer"`
+	Audience   string        `mapstructure:"audience"`
+	AccessTTL  time.Duration `mapstructure:"access_ttl"`
+	RefreshTTL time.Duration `mapstructure:"refresh_ttl"`
+	// RevocationCacheTTL is how long a revocation lookup is cached in memory,
+	// i.e. how long a logout on another instance may take to be enforced.
+	RevocationCacheTTL time.Duration `mapstructure:"revocation_cache_ttl"`
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat.
+	Leeway time.Duration `mapstructure:"leeway"`
+	// Keys switches signing from the HS512 secret to RS256/EdDSA keys.
+	// KeyOverlap is how long a replaced key is still accepted.
+	Keys       []JWTKeyConfig `mapstructure:"keys"`
+	KeyOverlap time.Duration  `mapstructure:"key_overlap"`
+}
+
+type JWTKeyConfig struct {
+	ID             string    `mapstructure:"id"`
+	PrivateKeyFile string    `mapstructure:"private_key_file"`
+	ActiveFrom     time.Time `mapstructure:"active_from"`
+}
+
+// LoginConfig throttles password guessing. Once an account or an IP reached
+// its free attempts, every further failure locks it for BaseLockout, doubled
+// on each failure up to MaxLockout. Failures older than FailureWindow are
+// forgotten.
+type LoginConfig struct {
+	MaxAccountAttempts int           `mapstructure:"max_account_attempts"`
+	MaxIPAttempts      int           `mapstructure:"max_ip_attempts"`
+	BaseLockout        time.Duration `mapstructure:"base_lockout"`
+	MaxLockout         time.Duration `mapstructure:"max_lockout"`
+	FailureWindow      time.Duration `mapstructure:"failure_window"`
+}
+
+// MailConfig picks how emails are delivered: "smtp", "file" (one .eml per
+// message in Dir) or "log" for local development.
+type MailConfig struct {
+	Driver string     `mapstructure:"driver"`
+	From   string     `mapstructure:"from"`
+	Dir    string     `mapstructure:"dir"`
+	SMTP   SMTPConfig `mapstructure:"smtp"`
+	// VerificationURL and PasswordResetURL are the links mailed to users,
+	// {token} is replaced by the token.
+	VerificationURL  string        `mapstructure:"verification_url"`
+	VerificationTTL  time.Duration `mapstructure:"verification_ttl"`
+	PasswordResetURL string        `mapstructure:"password_reset_url"`
+	PasswordResetTTL time.Duration `mapstructure:"password_reset_ttl"`
+	// ResendGap is the minimum time between two mails of the same kind to
+	// the same user.
+	ResendGap time.Duration `mapstructure:"resend_gap"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// PasswordConfig is the policy new passwords must follow. BcryptCost can be
+// raised at any time, existing hashes are upgraded on the next login.
+type PasswordConfig struct {
+	MinLength     int  `mapstructure:"min_length"`
+	MaxBytes      int  `mapstructure:"max_bytes"`
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
+	RejectCommon  bool `mapstructure:"reject_common"`
+	BcryptCost    int  `mapstructure:"bcrypt_cost"`
+}
+
+// TwoFactorConfig tunes TOTP two-factor login. Issuer is the account name
+// shown in authenticator apps, Skew the number of 30s steps a code may be
+// late or early, ChallengeTTL how long the second step of a login may take.
+type TwoFactorConfig struct {
+	Issuer        string        `mapstructure:"issuer"`
+	Skew          int           `mapstructure:"skew"`
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`
+	RecoveryCodes int           `mapstructure:"recovery_codes"`
+}
+
+// OIDCConfig lists the OpenID Connect providers users can log in with.
+// StateTTL is how long a login may stay at the provider.
+type OIDCConfig struct {
+	StateTTL  time.Duration        `mapstructure:"state_ttl"`
+	Providers []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig is one provider, Name is the path segment of its login
+// and callback routes and RedirectURL must point to that callback. Scopes
+// default to openid, email and profile.
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// BasicAuthConfig accepts HTTP Basic credentials on the route groups in
+// Groups, next to access tokens and API keys. The username is the account
+// email. Passwords are checked against the users table when Users is set
+// and against HtpasswdFile for service accounts, whose entries win over the
+// account password. Every entry needs its users row and accounts with
+// two-factor login are refused either way.
+type BasicAuthConfig struct {
+	Realm        string   `mapstructure:"realm"`
+	Users        bool     `mapstructure:"users"`
+	HtpasswdFile string   `mapstructure:"htpasswd_file"`
+	Groups       []string `mapstructure:"groups"`
+}
+
+// StorageConfig picks where uploaded images are kept: "local" (files in
+// Local.Dir) or "s3" (any S3-compatible service). PublicURL is the base of
+// the URLs saved on photos, by default the /blobs route of this server,
+// which streams local objects and redirects to a URL signed for SignedURLTTL
+// for S3 ones.
+type StorageConfig struct {
+	Driver         string             `mapstructure:"driver"`
+	PublicURL      string             `mapstructure:"public_url"`
+	MaxUploadBytes int64              `mapstructure:"max_upload_bytes"`
+	AllowedTypes   []string           `mapstructure:"allowed_types"`
+	SignedURLTTL   time.Duration      `mapstructure:"signed_url_ttl"`
+	Local          LocalStorageConfig `mapstructure:"local"`
+	S3             S3StorageConfig    `mapstructure:"s3"`
+	Variants       VariantsConfig     `mapstructure:"variants"`
+}
+
+// VariantsConfig sets the resized copies made of every photo in the
+// background, one per width narrower than the photo and per format.
+// Photos registered by URL are downloaded first, from public addresses
+// only unless AllowPrivateURLs is set for local development.
+type VariantsConfig struct {
+	Widths           []int         `mapstructure:"widths"`
+	Formats          []string      `mapstructure:"formats"`
+	JPEGQuality      int           `mapstructure:"jpeg_quality"`
+	Workers          int           `mapstructure:"workers"`
+	QueueSize        int           `mapstructure:"queue_size"`
+	MaxPixels        int           `mapstructure:"max_pixels"`
+	FetchTimeout     time.Duration `mapstructure:"fetch_timeout"`
+	AllowPrivateURLs bool          `mapstructure:"allow_private_urls"`
+	// CatchUpInterval is how often the photos whose variants are missing
+	// or were made from another URL are queued again
+	CatchUpInterval time.Duration `mapstructure:"catch_up_interval"`
+}
+
+type LocalStorageConfig struct {
+	Dir string `mapstructure:"dir"`
+}
+
+// S3StorageConfig addresses buckets as <endpoint>/<bucket> when PathStyle
+// is set, as MinIO expects, and as <bucket>.<endpoint host> otherwise.
+type S3StorageConfig struct {
+	Endpoint        string `mapstructure:"endpoint"`
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	PathStyle       bool   `mapstructure:"path_style"`
+}
+
+// ValidationError lists every missing or invalid key found while loading
+// the configuration, so they can all be fixed in one go.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(e.Errors, "; "))
+}
+
+// DSN builds the postgres connection string used by gorm.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(d.Host), d.Port, dsnValue(d.User), dsnValue(d.Password), dsnValue(d.Name), dsnValue(d.SSLMode))
+}
+
+// dsnValue quotes a value of a key=value connection string the way libpq
+// reads it, so a space or a quote can not end it and add another key.
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r\v\f'\\") {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// Load reads the configuration from the environment (MYGRAM_DATABASE_HOST,
+// MYGRAM_JWT_SECRET, ...) and, when path is not empty, from a YAML or TOML
+// file. Environment variables always win over the file.
+func Load(path string) (Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	v.SetEnvPrefix(ENV_PREFIX)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return Config{}, fmt.Errorf("cannot read config file %s: %w", path, err)
+		}
+	}
+
+	cfg := Config{}
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	))); err != nil {
+		return Config{}, fmt.Errorf("cannot decode config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	// every key needs a default so viper picks it up from the environment
+	v.SetDefault("app.address", ":3000")
+	v.SetDefault("app.gin_mode", "debug")
+	v.SetDefault("app.trusted_proxies", []string{})
+
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.user", "postgres")
+	v.SetDefault("database.password", "")
+	v.SetDefault("database.name", "mygram")
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.max_open_conns", 25)
+	v.SetDefault("database.max_idle_conns", 5)
+	v.SetDefault("database.conn_max_lifetime", 30*time.Minute)
+
+	v.SetDefault("jwt.secret", "")
+	v.SetDefault("jwt.issuer", "project")
+	v.SetDefault("jwt.audience", "mygram")
+	v.SetDefault("jwt.access_ttl", time.Hour)
+	v.SetDefault("jwt.refresh_ttl", 30*24*time.Hour)
+	v.SetDefault("jwt.revocation_cache_ttl", 30*time.Second)
+	v.SetDefault("jwt.leeway", 30*time.Second)
+	v.SetDefault("jwt.key_overlap", 2*time.Hour)
+
+	v.SetDefault("login.max_account_attempts", 5)
+	v.SetDefault("login.max_ip_attempts", 20)
+	v.SetDefault("login.base_lockout", 30*time.Second)
+	v.SetDefault("login.max_lockout", time.Hour)
+	v.SetDefault("login.failure_window", 15*time.Minute)
+
+	v.SetDefault("password.min_length", 8)
+	v.SetDefault("password.max_bytes", 72)
+	v.SetDefault("password.require_upper", false)
+	v.SetDefault("password.require_lower", false)
+	v.SetDefault("password.require_digit", false)
+	v.SetDefault("password.require_symbol", false)
+	v.SetDefault("password.reject_common", true)
+	v.SetDefault("password.bcrypt_cost", 10)
+
+	v.SetDefault("mail.driver", "log")
+	v.SetDefault("mail.from", "mygram <no-reply@localhost>")
+	v.SetDefault("mail.dir", "mails")
+	v.SetDefault("mail.smtp.host", "")
+	v.SetDefault("mail.smtp.port", 587)
+	v.SetDefault("mail.smtp.username", "")
+	v.SetDefault("mail.smtp.password", "")
+	v.SetDefault("mail.verification_url", "http://localhost:3000/verify-email?token={token}")
+	v.SetDefault("mail.verification_ttl", 24*time.Hour)
+	v.SetDefault("mail.password_reset_url", "http://localhost:3000/reset-password?token={token}")
+	v.SetDefault("mail.password_reset_ttl", time.Hour)
+	v.SetDefault("mail.resend_gap", time.Minute)
+
+	v.SetDefault("two_factor.issuer", "mygram")
+	v.SetDefault("two_factor.skew", 1)
+	v.SetDefault("two_factor.challenge_ttl", 5*time.Minute)
+	v.SetDefault("two_factor.recovery_codes", 10)
+
+	v.SetDefault("oidc.state_ttl", 10*time.Minute)
+
+	v.SetDefault("basic_auth.realm", "mygram")
+	v.SetDefault("basic_auth.users", false)
+	v.SetDefault("basic_auth.htpasswd_file", "")
+	v.SetDefault("basic_auth.groups", []string{})
+
+	v.SetDefault("storage.driver", "local")
+	v.SetDefault("storage.public_url", "http://localhost:3000/blobs")
+	v.SetDefault("storage.max_upload_bytes", 10<<20)
+	v.SetDefault("storage.allowed_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+	v.SetDefault("storage.signed_url_ttl", 15*time.Minute)
+	v.SetDefault("storage.local.dir", "uploads")
+	v.SetDefault("storage.s3.endpoint", "")
+	v.SetDefault("storage.s3.region", "us-east-1")
+	v.SetDefault("storage.s3.bucket", "")
+	v.SetDefault("storage.s3.access_key_id", "")
+	v.SetDefault("storage.s3.secret_access_key", "")
+	v.SetDefault("storage.s3.path_style", false)
+	v.SetDefault("storage.variants.widths", []int{320, 640, 1280})
+	v.SetDefault("storage.variants.formats", []string{"jpeg"})
+	v.SetDefault("storage.variants.jpeg_quality", 82)
+	v.SetDefault("storage.variants.workers", 2)
+	v.SetDefault("storage.variants.queue_size", 100)
+	v.SetDefault("storage.variants.max_pixels", 50_000_000)
+	v.SetDefault("storage.variants.fetch_timeout", 30*time.Second)
+	v.SetDefault("storage.variants.allow_private_urls", false)
+	v.SetDefault("storage.variants.catch_up_interval", 10*time.Minute)
+}
+
+func (c Config) Validate() error {
+	errs := []string{}
+
+	if c.App.Address == "" {
+		errs = append(errs, "app.address is required")
+	}
+	switch c.App.GinMode {
+	case "debug", "release", "test":
+	default:
+		errs = append(errs, fmt.Sprintf("app.gin_mode must be one of debug, release, test (got %q)", c.App.GinMode))
+	}
+
+	if c.Database.Host == "" {
+		errs = append(errs, "database.host is required")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("database.port must be between 1 and 65535 (got %d)", c.Database.Port))
+	}
+	if c.Database.User == "" {
+		errs = append(errs, "database.user is required")
+	}
+	if c.Database.Password == "" {
+		errs = append(errs, "database.password is required")
+	}
+	if c.Database.Name == "" {
+		errs = append(errs, "database.name is required")
+	}
+	if c.Database.MaxOpenConns < 0 {
+		errs = append(errs, "database.max_open_conns must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		errs = append(errs, "database.max_idle_conns must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, "database.max_idle_conns must not exceed database.max_open_conns")
+	}
+
+	if len(c.JWT.Keys) == 0 {
+		if c.JWT.Secret == "" {
+			errs = append(errs, "jwt.secret is required when jwt.keys is empty")
+		} else if len(c.JWT.Secret) < 32 {
+			errs = append(errs, "jwt.secret must be at least 32 characters")
+		}
+	}
+	keyIDs := map[string]bool{}
+	for i, key := range c.JWT.Keys {
+		if key.ID == "" {
+			errs = append(errs, fmt.Sprintf("jwt.keys[%d].id is required", i))
+		} else if keyIDs[key.ID] {
+			errs = append(errs, fmt.Sprintf("jwt.keys[%d].id %q is duplicated", i, key.ID))
+		}
+		keyIDs[key.ID] = true
+		if key.PrivateKeyFile == "" {
+			errs = append(errs, fmt.Sprintf("jwt.keys[%d].private_key_file is required", i))
+		}
+	}
+	if len(c.JWT.Keys) > 0 && c.JWT.KeyOverlap < c.JWT.AccessTTL {
+		errs = append(errs, "jwt.key_overlap must be at least jwt.access_ttl so rotated tokens stay valid")
+	}
+	if c.JWT.Issuer == "" {
+		errs = append(errs, "jwt.issuer is required")
+	}
+	if c.JWT.Audience == "" {
+		errs = append(errs, "jwt.audience is required")
+	}
+	if c.JWT.AccessTTL <= 0 {
+		errs = append(errs, "jwt.access_ttl must be a positive duration")
+	}
+	if c.JWT.RefreshTTL <= c.JWT.AccessTTL {
+		errs = append(errs, "jwt.refresh_ttl must be longer than jwt.access_ttl")
+	}
+	if c.JWT.RevocationCacheTTL <= 0 {
+		errs = append(errs, "jwt.revocation_cache_ttl must be a positive duration")
+	}
+	if c.JWT.Leeway < 0 || c.JWT.Leeway > 5*time.Minute {
+		errs = append(errs, "jwt.leeway must be between 0 and 5m")
+	}
+
+	if c.Login.MaxAccountAttempts <= 0 {
+		errs = append(errs, "login.max_account_attempts must be positive")
+	}
+	if c.Login.MaxIPAttempts < c.Login.MaxAccountAttempts {
+		errs = append(errs, "login.max_ip_attempts must be at least login.max_account_attempts")
+	}
+	if c.Login.BaseLockout <= 0 {
+		errs = append(errs, "login.base_lockout must be a positive duration")
+	}
+	if c.Login.MaxLockout < c.Login.BaseLockout {
+		errs = append(errs, "login.max_lockout must be at least login.base_lockout")
+	}
+	if c.Login.FailureWindow <= 0 {
+		errs = append(errs, "login.failure_window must be a positive duration")
+	}
+
+	if c.Password.MinLength < 1 {
+		errs = append(errs, "password.min_length must be positive")
+	}
+	// bcrypt ignores everything after 72 bytes
+	if c.Password.MaxBytes < c.Password.MinLength || c.Password.MaxBytes > 72 {
+		errs = append(errs, "password.max_bytes must be between password.min_length and 72")
+	}
+	if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+		errs = append(errs, fmt.Sprintf("password.bcrypt_cost must be between 4 and 31 (got %d)", c.Password.BcryptCost))
+	}
+
+	switch c.Mail.Driver {
+	case "log":
+	case "file":
+		if c.Mail.Dir == "" {
+			errs = append(errs, "mail.dir is required when mail.driver is file")
+		}
+	case "smtp":
+		if c.Mail.SMTP.Host == "" {
+			errs = append(errs, "mail.smtp.host is required when mail.driver is smtp")
+		}
+		if c.Mail.SMTP.Port <= 0 || c.Mail.SMTP.Port > 65535 {
+			errs = append(errs, fmt.Sprintf("mail.smtp.port must be between 1 and 65535 (got %d)", c.Mail.SMTP.Port))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("mail.driver must be one of smtp, file, log (got %q)", c.Mail.Driver))
+	}
+	if c.Mail.From == "" {
+		errs = append(errs, "mail.from is required")
+	}
+	if !strings.Contains(c.Mail.VerificationURL, "{token}") {
+		errs = append(errs, "mail.verification_url must contain {token}")
+	}
+	if c.Mail.VerificationTTL <= 0 {
+		errs = append(errs, "mail.verification_ttl must be a positive duration")
+	}
+	if !strings.Contains(c.Mail.PasswordResetURL, "{token}") {
+		errs = append(errs, "mail.password_reset_url must contain {token}")
+	}
+	if c.Mail.PasswordResetTTL <= 0 {
+		errs = append(errs, "mail.password_reset_ttl must be a positive duration")
+	}
+	if c.Mail.ResendGap < 0 {
+		errs = append(errs, "mail.resend_gap must not be negative")
+	}
+
+	// the issuer is the label prefix of the otpauth URI
+	if c.TwoFactor.Issuer == "" || strings.Contains(c.TwoFactor.Issuer, ":") {
+		errs = append(errs, "two_factor.issuer is required and must not contain ':'")
+	}
+	if c.TwoFactor.Skew < 0 || c.TwoFactor.Skew > 2 {
+		errs = append(errs, fmt.Sprintf("two_factor.skew must be between 0 and 2 (got %d)", c.TwoFactor.Skew))
+	}
+	if c.TwoFactor.ChallengeTTL <= 0 || c.TwoFactor.ChallengeTTL > 15*time.Minute {
+		errs = append(errs, "two_factor.challenge_ttl must be between 0 and 15m")
+	}
+	if c.TwoFactor.RecoveryCodes < 1 || c.TwoFactor.RecoveryCodes > 20 {
+		errs = append(errs, fmt.Sprintf("two_factor.recovery_codes must be between 1 and 20 (got %d)", c.TwoFactor.RecoveryCodes))
+	}
+
+	if c.OIDC.StateTTL <= 0 {
+		errs = append(errs, "oidc.state_ttl must be a positive duration")
+	}
+	providerNames := map[string]bool{}
+	for i, provider := range c.OIDC.Providers {
+		if !oidcProviderName.MatchString(provider.Name) {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].name must be lowercase letters, digits or '-'", i))
+		} else if providerNames[provider.Name] {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].name %q is duplicated", i, provider.Name))
+		}
+		providerNames[provider.Name] = true
+		if issuer, err := url.Parse(provider.Issuer); err != nil || issuer.Host == "" || (issuer.Scheme != "https" && !isLoopback(issuer.Hostname())) {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].issuer must be an https URL", i))
+		}
+		if provider.ClientID == "" {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].client_id is required", i))
+		}
+		if redirect, err := url.Parse(provider.RedirectURL); err != nil || !redirect.IsAbs() {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].redirect_url must be an absolute URL", i))
+		}
+		if len(provider.Scopes) > 0 && !slices.Contains(provider.Scopes, "openid") {
+			errs = append(errs, fmt.Sprintf("oidc.providers[%d].scopes must contain openid", i))
+		}
+	}
+
+	// the realm is sent as a quoted string in WWW-Authenticate
+	if c.BasicAuth.Realm == "" || strings.ContainsAny(c.BasicAuth.Realm, "\"\\") {
+		errs = append(errs, "basic_auth.realm is required and must not contain quotes or backslashes")
+	}
+	for _, group := range c.BasicAuth.Groups {
+		if !slices.Contains(BasicAuthGroups, group) {
+			errs = append(errs, fmt.Sprintf("basic_auth.groups must only contain %s (got %q)", strings.Join(BasicAuthGroups, ", "), group))
+		}
+	}
+	if len(c.BasicAuth.Groups) > 0 && !c.BasicAuth.Users && c.BasicAuth.HtpasswdFile == "" {
+		errs = append(errs, "basic_auth.users or basic_auth.htpasswd_file is required when basic_auth.groups is set")
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.Local.Dir == "" {
+			errs = append(errs, "storage.local.dir is required when storage.driver is local")
+		}
+	case "s3":
+		if endpoint, err := url.Parse(c.Storage.S3.Endpoint); err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+			errs = append(errs, "storage.s3.endpoint must be an http or https URL when storage.driver is s3")
+		}
+		if c.Storage.S3.Region == "" {
+			errs = append(errs, "storage.s3.region is required when storage.driver is s3")
+		}
+		if c.Storage.S3.Bucket == "" {
+			errs = append(errs, "storage.s3.bucket is required when storage.driver is s3")
+		}
+		if c.Storage.S3.AccessKeyID == "" || c.Storage.S3.SecretAccessKey == "" {
+			errs = append(errs, "storage.s3.access_key_id and storage.s3.secret_access_key are required when storage.driver is s3")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("storage.driver must be one of local, s3 (got %q)", c.Storage.Driver))
+	}
+	if publicURL, err := url.Parse(c.Storage.PublicURL); err != nil || !publicURL.IsAbs() {
+		errs = append(errs, "storage.public_url must be an absolute URL")
+	}
+	if c.Storage.MaxUploadBytes <= 0 {
+		errs = append(errs, "storage.max_upload_bytes must be positive")
+	}
+	if len(c.Storage.AllowedTypes) == 0 {
+		errs = append(errs, "storage.allowed_types must not be empty")
+	}
+	// S3 refuses presigned URLs valid for more than a week
+	if c.Storage.SignedURLTTL <= 0 || c.Storage.SignedURLTTL > 7*24*time.Hour {
+		errs = append(errs, "storage.signed_url_ttl must be between 0 and 168h")
+	}
+	for _, width := range c.Storage.Variants.Widths {
+		if width < 16 || width > 4096 {
+			errs = append(errs, fmt.Sprintf("storage.variants.widths must be between 16 and 4096 (got %d)", width))
+		}
+	}
+	for _, format := range c.Storage.Variants.Formats {
+		if !slices.Contains(VariantFormats, format) {
+			errs = append(errs, fmt.Sprintf("storage.variants.formats must only contain %s (got %q)", strings.Join(VariantFormats, ", "), format))
+		}
+	}
+	if c.Storage.Variants.JPEGQuality < 1 || c.Storage.Variants.JPEGQuality > 100 {
+		errs = append(errs, fmt.Sprintf("storage.variants.jpeg_quality must be between 1 and 100 (got %d)", c.Storage.Variants.JPEGQuality))
+	}
+	if c.Storage.Variants.Workers < 1 {
+		errs = append(errs, "storage.variants.workers must be positive")
+	}
+	if c.Storage.Variants.QueueSize < 1 {
+		errs = append(errs, "storage.variants.queue_size must be positive")
+	}
+	if c.Storage.Variants.MaxPixels < 1 {
+		errs = append(errs, "storage.variants.max_pixels must be positive")
+	}
+	if c.Storage.Variants.FetchTimeout <= 0 {
+		errs = append(errs, "storage.variants.fetch_timeout must be a positive duration")
+	}
+	if c.Storage.Variants.CatchUpInterval <= 0 {
+		errs = append(errs, "storage.variants.catch_up_interval must be a positive duration")
+	}
+
+	if len(errs) > 0 {
+		return ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// isLoopback allows plain http issuers for providers running locally.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("error missing required keys", func(t *testing.T) {
+		t.Setenv("MYGRAM_DATABASE_PASSWORD", "")
+		t.Setenv("MYGRAM_JWT_SECRET", "")
+
+		_, err := Load("")
+		assert.NotNil(t, err)
+
+		validationErr, ok := err.(ValidationError)
+		assert.True(t, ok)
+		assert.Contains(t, validationErr.Errors, "database.password is required")
+		assert.Contains(t, validationErr.Errors, "jwt.secret is required when jwt.keys is empty")
+	})
+
+	t.Run("success load from env", func(t *testing.T) {
+		t.Setenv("MYGRAM_DATABASE_PASSWORD", "secret")
+		t.Setenv("MYGRAM_DATABASE_PORT", "5433")
+		t.Setenv("MYGRAM_JWT_SECRET", "a-very-long-secret-used-for-testing-only")
+		t.Setenv("MYGRAM_JWT_ACCESS_TTL", "15m")
+		t.Setenv("MYGRAM_APP_ADDRESS", ":8080")
+
+		cfg, err := Load("")
+		assert.Nil(t, err)
+		assert.Equal(t, ":8080", cfg.App.Address)
+		assert.Equal(t, 5433, cfg.Database.Port)
+		assert.Equal(t, 15*time.Minute, cfg.JWT.AccessTTL)
+		assert.Equal(t, "host=localhost port=5433 user=postgres password=secret dbname=mygram sslmode=disable", cfg.Database.DSN())
+	})
+}
+
+func TestDSN(t *testing.T) {
+	t.Run("success values with spaces and quotes are quoted", func(t *testing.T) {
+		db := DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", Password: `it's a \ x sslmode=disable`, Name: "mygram", SSLMode: "require"}
+		dsn := db.DSN()
+		assert.Equal(t, `host=localhost port=5432 user=postgres password='it\'s a \\ x sslmode=disable' dbname=mygram sslmode=require`, dsn)
+
+		parsed, err := pgconn.ParseConfig(dsn)
+		assert.Nil(t, err)
+		assert.Equal(t, db.Password, parsed.Password)
+		assert.Equal(t, "mygram", parsed.Database)
+		assert.NotNil(t, parsed.TLSConfig)
+	})
+
+	t.Run("success empty password", func(t *testing.T) {
+		dsn := DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "mygram", SSLMode: "disable"}.DSN()
+		assert.Contains(t, dsn, "password='' dbname=mygram")
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("success load signing keys from yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte(`
+database:
+  password: secret
+jwt:
+  keys:
+    - id: "2024-01"
+      private_key_file: keys/2024-01.pem
+      active_from: 2024-01-01T00:00:00Z
+`), 0o600)
+		assert.Nil(t, err)
+
+		cfg, err := Load(path)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(cfg.JWT.Keys))
+		assert.Equal(t, "2024-01", cfg.JWT.Keys[0].ID)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), cfg.JWT.Keys[0].ActiveFrom.UTC())
+	})
+	t.Run("error basic auth on unknown group without credentials", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte(`
+database:
+  password: secret
+jwt:
+  secret: a-very-long-secret-used-for-testing-only
+basic_auth:
+  groups: [photos, users]
+`), 0o600)
+		assert.Nil(t, err)
+
+		_, err = Load(path)
+		validationErr, ok := err.(ValidationError)
+		assert.True(t, ok)
+		assert.Equal(t, []string{
+			`basic_auth.groups must only contain photos, comments, socialmedias (got "users")`,
+			"basic_auth.users or basic_auth.htpasswd_file is required when basic_auth.groups is set",
+		}, validationErr.Errors)
+	})
+
+	t.Run("error s3 storage without bucket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte(`
+database:
+  password: secret
+jwt:
+  secret: a-very-long-secret-used-for-testing-only
+storage:
+  driver: s3
+  s3:
+    endpoint: http://localhost:9000
+    access_key_id: minio
+    secret_access_key: minio-secret
+`), 0o600)
+		assert.Nil(t, err)
+
+		_, err = Load(path)
+		validationErr, ok := err.(ValidationError)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"storage.s3.bucket is required when storage.driver is s3"}, validationErr.Errors)
+	})
+
+	t.Run("error unknown variant format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte(`
+database:
+  password: secret
+jwt:
+  secret: a-very-long-secret-used-for-testing-only
+storage:
+  variants:
+    widths: [320, 8]
+    formats: [jpeg, webp, avif]
+    catch_up_interval: 0s
+`), 0o600)
+		assert.Nil(t, err)
+
+		_, err = Load(path)
+		validationErr, ok := err.(ValidationError)
+		assert.True(t, ok)
+		assert.Equal(t, []string{
+			"storage.variants.widths must be between 16 and 4096 (got 8)",
+			`storage.variants.formats must only contain jpeg, png, webp (got "avif")`,
+			"storage.variants.catch_up_interval must be a positive duration",
+		}, validationErr.Errors)
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mygram/middleware"
+	"mygram/model"
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler interface {
+	CreateAPIKey(ctx *gin.Context)
+	GetAPIKeys(ctx *gin.Context)
+	RevokeAPIKey(ctx *gin.Context)
+}
+
+type apiKeyHandlerImpl struct {
+	svc service.APIKeyService
+}
+
+func NewAPIKeyHandler(svc service.APIKeyService) APIKeyHandler {
+	return &apiKeyHandlerImpl{svc: svc}
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create API key
+//	@Description	will create an API key acting as the current user within the given scopes, the key is only returned this once. Send it as "Authorization: ApiKey <key>"
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"bearer token"
+//	@Param			body			body		model.CreateAPIKeyRequest	true	"name, scopes and optional expiry"
+//	@Success		201				{object}	model.CreatedAPIKey
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/api-keys [post]
+func (a *apiKeyHandlerImpl) CreateAPIKey(ctx *gin.Context) {
+	var req model.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body", Errors: []string{err.Error()}})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	key, err := a.svc.Create(ctx, principal.UserID, req)
+	if errors.Is(err, service.ErrTooManyAPIKeys) {
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// GetAPIKeys godoc
+//
+//	@Summary		List API keys
+//	@Description	will list the API keys of the current user that were not revoked
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	[]model.APIKey
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/api-keys [get]
+func (a *apiKeyHandlerImpl) GetAPIKeys(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	keys, err := a.svc.List(ctx, principal.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary		Revoke API key
+//	@Description	will revoke an API key of the current user, it stops working right away
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"API key ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/api-keys/{id} [delete]
+func (a *apiKeyHandlerImpl) RevokeAPIKey(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("keyId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err = a.svc.Revoke(ctx, principal.UserID, id)
+	if errors.Is(err, service.ErrAPIKeyNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]string{"message": "api key revoked"})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlobHandler interface {
+	GetBlob(ctx *gin.Context)
+}
+
+type blobHandlerImpl struct {
+	svc service.BlobService
+}
+
+func NewBlobHandler(svc service.BlobService) BlobHandler {
+	return &blobHandlerImpl{svc: svc}
+}
+
+// GetBlob godoc
+//
+//	@Summary		Show an uploaded image
+//	@Description	will stream the image, or redirect to a short lived signed URL when the store supports them
+//	@Tags			photos
+//	@Produce		image/jpeg,image/png,image/gif,image/webp
+//	@Param			key	path	string	true	"key of the image"
+//	@Success		200
+//	@Success		302
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/blobs/{key} [get]
+func (b *blobHandlerImpl) GetBlob(ctx *gin.Context) {
+	blob, err := b.svc.Open(ctx, strings.TrimPrefix(ctx.Param("key"), "/"))
+	if errors.Is(err, service.ErrBlobNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if blob.RedirectURL != "" {
+		// the signed URL expires, do not let the redirect outlive it
+		ctx.Header("Cache-Control", "no-store")
+		ctx.Redirect(http.StatusFound, blob.RedirectURL)
+		return
+	}
+	defer blob.Content.Close()
+	// keys are random and never reused, so the content never changes
+	ctx.DataFromReader(http.StatusOK, blob.Size, blob.ContentType, blob.Content, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
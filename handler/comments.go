@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -44,20 +45,17 @@ func (c *commentHandlerImpl) UpdateComment(ctx *gin.Context) {
 	}
 
 	// Get user from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
 		return
 	}
 
-	userId, ok := user.(float64)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
+	// Call service to update photo
+	updatedComment, err := c.svc.UpdateComment(ctx, data, commentID, principal)
+	if errors.Is(err, service.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: err.Error()})
 		return
 	}
-
-	// Call service to update photo
-	updatedComment, err := c.svc.UpdateComment(ctx, data, commentID, int(userId))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -117,20 +115,13 @@ func (c *commentHandlerImpl) CreateComment(ctx *gin.Context) {
 	// }
 
 	// Get user info from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
-		return
-	}
-
-	userId, ok := user.(float64)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
 		return
 	}
 
 	// Call service to create comment
-	comment, err := c.svc.CreateComment(ctx, commentCreate, int(userId))
+	comment, err := c.svc.CreateComment(ctx, commentCreate, int(principal.UserID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -162,30 +153,21 @@ func (c *commentHandlerImpl) DeleteComment(ctx *gin.Context) {
 	}
 
 	// Get user info from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
 		return
 	}
 
-	userId, ok := user.(float64)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
-		return
-	}
-
-	// Check if the comment belongs to the user
-	if commentID != int(userId) {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "invalid request: comment does not belong to the user"})
+	// Call service to delete comment
+	err = c.svc.DeleteComment(ctx, commentID, principal)
+	if errors.Is(err, service.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: err.Error()})
 		return
 	}
-
-	// Call service to delete comment
-	err = c.svc.DeleteComment(ctx, commentID, int(userId))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, principal.UserID)
 }
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"mygram/middleware"
+	"mygram/model"
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler interface {
+	GetFeed(ctx *gin.Context)
+}
+
+type feedHandlerImpl struct {
+	svc service.FeedService
+}
+
+func NewFeedHandler(svc service.FeedService) FeedHandler {
+	return &feedHandlerImpl{svc: svc}
+}
+
+// GetFeed godoc
+//
+//	@Summary		Show the home feed
+//	@Description	will return the photos of the current user and of the users they follow, newest first. Pass next_cursor as cursor to get the next page
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			cursor			query		string	false	"next_cursor of the previous page"
+//	@Param			limit			query		int		false	"photos per page, at most 100"
+//	@Success		200				{object}	model.Feed
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/feed [get]
+func (f *feedHandlerImpl) GetFeed(ctx *gin.Context) {
+	var req model.FeedRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return
+	}
+	req, err := req.Normalize()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	feed, err := f.svc.GetFeed(ctx, principal.UserID, req)
+	if errors.Is(err, model.ErrInvalidFeedCursor) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, feed)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mygram/middleware"
+	"mygram/model"
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler interface {
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+	GetFollowers(ctx *gin.Context)
+	GetFollowing(ctx *gin.Context)
+}
+
+type followHandlerImpl struct {
+	svc service.FollowService
+}
+
+func NewFollowHandler(svc service.FollowService) FollowHandler {
+	return &followHandlerImpl{svc: svc}
+}
+
+// Follow godoc
+//
+//	@Summary		Follow a user
+//	@Description	will make the current user follow the given user
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			userId			path		int		true	"User ID"
+//	@Success		201				{object}	model.Follow
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/{userId}/follow [post]
+func (f *followHandlerImpl) Follow(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	follow, err := f.svc.Follow(ctx, principal.UserID, id)
+	switch {
+	case errors.Is(err, service.ErrFollowSelf):
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrFollowUserNotFound):
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAlreadyFollowing):
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+	default:
+		ctx.JSON(http.StatusCreated, follow)
+	}
+}
+
+// Unfollow godoc
+//
+//	@Summary		Unfollow a user
+//	@Description	will make the current user stop following the given user
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			userId			path		int		true	"User ID"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/{userId}/follow [delete]
+func (f *followHandlerImpl) Unfollow(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err = f.svc.Unfollow(ctx, principal.UserID, id)
+	if errors.Is(err, service.ErrNotFollowing) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{"message": "unfollowed"})
+}
+
+// GetFollowers godoc
+//
+//	@Summary		List the followers of a user
+//	@Description	will return a page of the users following the given user, latest first
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			userId		path		int	true	"User ID"
+//	@Param			page		query		int	false	"page, starting at 1"
+//	@Param			page_size	query		int	false	"users per page, at most 100"
+//	@Success		200			{object}	model.FollowList
+//	@Failure		400			{object}	pkg.ErrorResponse
+//	@Failure		404			{object}	pkg.ErrorResponse
+//	@Failure		500			{object}	pkg.ErrorResponse
+//	@Router			/users/{userId}/followers [get]
+func (f *followHandlerImpl) GetFollowers(ctx *gin.Context) {
+	f.list(ctx, f.svc.Followers)
+}
+
+// GetFollowing godoc
+//
+//	@Summary		List the users a user follows
+//	@Description	will return a page of the users the given user follows, latest first
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			userId		path		int	true	"User ID"
+//	@Param			page		query		int	false	"page, starting at 1"
+//	@Param			page_size	query		int	false	"users per page, at most 100"
+//	@Success		200			{object}	model.FollowList
+//	@Failure		400			{object}	pkg.ErrorResponse
+//	@Failure		404			{object}	pkg.ErrorResponse
+//	@Failure		500			{object}	pkg.ErrorResponse
+//	@Router			/users/{userId}/following [get]
+func (f *followHandlerImpl) GetFollowing(ctx *gin.Context) {
+	f.list(ctx, f.svc.Following)
+}
+
+func (f *followHandlerImpl) list(ctx *gin.Context, get func(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error)) {
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	page, ok := bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	list, err := get(ctx, id, page)
+	if errors.Is(err, service.ErrFollowUserNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"mygram/model"
+	"mygram/pkg/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler interface {
+	GetJWKS(ctx *gin.Context)
+}
+
+type jwksHandlerImpl struct {
+	keys *helper.KeySet
+}
+
+func NewJWKSHandler(keys *helper.KeySet) JWKSHandler {
+	return &jwksHandlerImpl{keys: keys}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Show token verification keys
+//	@Description	will list the public keys access tokens are signed with, empty when tokens are signed with a shared secret
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	model.JSONWebKeySet
+//	@Router			/.well-known/jwks.json [get]
+func (j *jwksHandlerImpl) GetJWKS(ctx *gin.Context) {
+	jwks := model.JSONWebKeySet{Keys: []model.JSONWebKey{}}
+	for _, key := range j.keys.PublicKeys(time.Now()) {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+
+	// verifiers may cache the document, but not past a rotation
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
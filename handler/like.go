@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mygram/middleware"
+	"mygram/model"
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikeHandler interface {
+	Like(ctx *gin.Context)
+	Unlike(ctx *gin.Context)
+	GetLikes(ctx *gin.Context)
+}
+
+type likeHandlerImpl struct {
+	svc service.LikeService
+}
+
+func NewLikeHandler(svc service.LikeService) LikeHandler {
+	return &likeHandlerImpl{svc: svc}
+}
+
+// Like godoc
+//
+//	@Summary		Like a photo
+//	@Description	will make the current user like the given photo, liking it again changes nothing
+//	@Tags			likes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			id				path		int		true	"Photo ID"
+//	@Success		200				{object}	model.PhotoLikeStatus
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/like [put]
+func (l *likeHandlerImpl) Like(ctx *gin.Context) {
+	l.toggle(ctx, l.svc.Like)
+}
+
+// Unlike godoc
+//
+//	@Summary		Unlike a photo
+//	@Description	will remove the like of the current user from the given photo, if there is one
+//	@Tags			likes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			id				path		int		true	"Photo ID"
+//	@Success		200				{object}	model.PhotoLikeStatus
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/like [delete]
+func (l *likeHandlerImpl) Unlike(ctx *gin.Context) {
+	l.toggle(ctx, l.svc.Unlike)
+}
+
+// GetLikes godoc
+//
+//	@Summary		List the likers of a photo
+//	@Description	will return a page of the users who liked the given photo, latest first
+//	@Tags			likes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			id				path		int		true	"Photo ID"
+//	@Param			page			query		int		false	"page, starting at 1"
+//	@Param			page_size		query		int		false	"users per page, at most 100"
+//	@Success		200				{object}	model.PhotoLikeList
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/likes [get]
+func (l *likeHandlerImpl) GetLikes(ctx *gin.Context) {
+	photoID, err := strconv.Atoi(ctx.Param("photoId"))
+	if photoID < 1 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	page, ok := bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	list, err := l.svc.Likers(ctx, photoID, page)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+func (l *likeHandlerImpl) toggle(ctx *gin.Context, set func(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error)) {
+	photoID, err := strconv.Atoi(ctx.Param("photoId"))
+	if photoID < 1 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	status, err := set(ctx, principal.UserID, photoID)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDC_STATE_COOKIE ties the callback to the browser that started the
+// login, so nobody can log a victim into their own account.
+const OIDC_STATE_COOKIE = "mygram_oidc_state"
+
+type OIDCHandler interface {
+	Login(ctx *gin.Context)
+	Callback(ctx *gin.Context)
+}
+
+type oidcHandlerImpl struct {
+	svc       service.OIDCService
+	users     service.UserService
+	twoFactor service.TwoFactorService
+}
+
+func NewOIDCHandler(svc service.OIDCService, users service.UserService, twoFactor service.TwoFactorService) OIDCHandler {
+	return &oidcHandlerImpl{svc: svc, users: users, twoFactor: twoFactor}
+}
+
+// Login godoc
+//
+//	@Summary		Login with an OpenID Connect provider
+//	@Description	will redirect the browser to the provider, which sends it back to the callback
+//	@Tags			auth
+//	@Param			provider	path	string	true	"provider name"
+//	@Success		302
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/auth/oidc/{provider}/login [get]
+func (o *oidcHandlerImpl) Login(ctx *gin.Context) {
+	authURL, state, err := o.svc.Begin(ctx, ctx.Param("provider"))
+	if errors.Is(err, service.ErrUnknownOIDCProvider) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// lax, the provider sends the browser back with a top level GET
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(OIDC_STATE_COOKIE, state, 0, "/auth/oidc", "", isHTTPS(ctx), true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+//
+//	@Summary		OpenID Connect callback
+//	@Description	will finish the login started by /auth/oidc/{provider}/login and return a token pair, or a challenge token when two-factor is on. The provider account is linked to the local account with the same verified email, or a new account is created
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string	true	"provider name"
+//	@Param			code		query		string	true	"authorization code"
+//	@Param			state		query		string	true	"state of the login"
+//	@Success		200			{object}	map[string]string
+//	@Failure		400			{object}	pkg.ErrorResponse
+//	@Failure		401			{object}	pkg.ErrorResponse
+//	@Failure		403			{object}	pkg.ErrorResponse
+//	@Failure		404			{object}	pkg.ErrorResponse
+//	@Failure		409			{object}	pkg.ErrorResponse
+//	@Failure		500			{object}	pkg.ErrorResponse
+//	@Router			/auth/oidc/{provider}/callback [get]
+func (o *oidcHandlerImpl) Callback(ctx *gin.Context) {
+	state := ctx.Query("state")
+	cookie, err := ctx.Cookie(OIDC_STATE_COOKIE)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: service.ErrInvalidOIDCState.Error()})
+		return
+	}
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(OIDC_STATE_COOKIE, "", -1, "/auth/oidc", "", isHTTPS(ctx), true)
+
+	// the user cancelled or the provider refused
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{
+			Message: "login was not completed",
+			Errors:  []string{providerErr, ctx.Query("error_description")},
+		})
+		return
+	}
+
+	user, err := o.svc.Complete(ctx, ctx.Param("provider"), state, ctx.Query("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOIDCProvider):
+			ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrInvalidOIDCState):
+			ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrOIDCLoginFailed):
+			ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Code: pkg.ERR_CODE_INVALID_CREDENTIALS, Message: err.Error()})
+		case errors.Is(err, service.ErrOIDCEmailNotVerified):
+			ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrOIDCAccountNotVerified):
+			ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		}
+		return
+	}
+
+	respondLogin(ctx, o.users, o.twoFactor, user)
+}
+
+// isHTTPS marks cookies secure behind TLS, directly or at a trusted proxy.
+func isHTTPS(ctx *gin.Context) bool {
+	return ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -14,18 +15,26 @@ import (
 
 type PhotoHandler interface {
 	GetAllPhotos(ctx *gin.Context)
+	GetPhoto(ctx *gin.Context)
+	GetPhotoComments(ctx *gin.Context)
 	UpdatePhoto(ctx *gin.Context)
 	DeletePhoto(ctx *gin.Context)
 	CreatePhoto(ctx *gin.Context)
 }
 
+// MULTIPART_OVERHEAD is accepted on top of the image size for the other
+// fields and the multipart framing.
+const MULTIPART_OVERHEAD = 64 << 10
+
 type photoHandlerImpl struct {
-	svc service.PhotosService
+	svc            service.PhotosService
+	maxUploadBytes int64
 }
 
-func NewPhotoHandler(svc service.PhotosService) PhotoHandler {
+func NewPhotoHandler(svc service.PhotosService, maxUploadBytes int64) PhotoHandler {
 	return &photoHandlerImpl{
-		svc: svc,
+		svc:            svc,
+		maxUploadBytes: maxUploadBytes,
 	}
 }
 
@@ -44,20 +53,21 @@ func (p *photoHandlerImpl) UpdatePhoto(ctx *gin.Context) {
 	}
 
 	// Get user from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
 		return
 	}
 
-	userId, ok := user.(float64)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
+	// Call service to update photo
+	updatedPhoto, err := p.svc.UpdatePhoto(ctx, data, photoID, principal)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: err.Error()})
 		return
 	}
-
-	// Call service to update photo
-	updatedPhoto, err := p.svc.UpdatePhoto(ctx, data, photoID, int(userId))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -80,7 +90,12 @@ func (p *photoHandlerImpl) UpdatePhoto(ctx *gin.Context) {
 //	@Failure		500	{object}	pkg.ErrorResponse
 //	@Router			/users [get]
 func (p *photoHandlerImpl) GetAllPhotos(ctx *gin.Context) {
-	photos, err := p.svc.GetAllPhotos(ctx)
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	photos, err := p.svc.GetAllPhotos(ctx, principal.UserID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -88,20 +103,118 @@ func (p *photoHandlerImpl) GetAllPhotos(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, photos)
 }
 
-// ShowUsersById godoc
+// GetPhoto godoc
 //
-//	@Summary		Show users detail
-//	@Description	will fetch 3rd party server to get users data to get detail user
-//	@Tags			users
+//	@Summary		Show a photo
+//	@Description	will return the photo with the given id, with its owner, variants, likes, the metadata read from the uploaded image and the first page of comments, oldest first. The comments are left out for api keys without the comments:read scope
+//	@Tags			photos
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int	true	"User ID"
-//	@Success		200	{object}	model.User
-//	@Failure		400	{object}	pkg.ErrorResponse
-//	@Failure		404	{object}	pkg.ErrorResponse
-//	@Failure		500	{object}	pkg.ErrorResponse
-//	@Router			/users/{id} [get]
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			id				path		int		true	"Photo ID"
+//	@Success		200				{object}	model.PhotoDetail
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos/{id} [get]
+func (p *photoHandlerImpl) GetPhoto(ctx *gin.Context) {
+	photoID, err := strconv.Atoi(ctx.Param("photoId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "ID must be a number"})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	photo, err := p.svc.GetPhoto(ctx, photoID, principal)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, photo)
+}
+
+// GetPhotoComments godoc
+//
+//	@Summary		List the comments of a photo
+//	@Description	will return a page of the comments of the given photo, oldest first. Pass next_cursor as cursor to get the next page
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token or api key"
+//	@Param			id				path		int		true	"Photo ID"
+//	@Param			cursor			query		string	false	"next_cursor of the previous page"
+//	@Param			limit			query		int		false	"comments per page, at most 100"
+//	@Success		200				{object}	model.PhotoComments
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/comments [get]
+func (p *photoHandlerImpl) GetPhotoComments(ctx *gin.Context) {
+	photoID, err := strconv.Atoi(ctx.Param("photoId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "ID must be a number"})
+		return
+	}
+	var req model.CommentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return
+	}
+	req, err = req.Normalize()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return
+	}
+
+	comments, err := p.svc.GetPhotoComments(ctx, photoID, req)
+	switch {
+	case errors.Is(err, model.ErrInvalidCommentCursor):
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+	case errors.Is(err, service.ErrPhotoNotFound):
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+	default:
+		ctx.JSON(http.StatusOK, comments)
+	}
+}
+
+// CreatePhoto godoc
+//
+//	@Summary		Create a photo
+//	@Description	will create a photo from an external url sent as JSON, or upload the image sent as multipart form in the "image" field. Uploads are checked by content and limited in size, their GPS position is removed unless keep_location is set
+//	@Tags			photos
+//	@Accept			json,mpfd
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token or api key"
+//	@Param			request			body		model.CreatePhoto	false	"photo with an external url"
+//	@Param			title			formData	string				false	"title of an uploaded photo"
+//	@Param			caption			formData	string				false	"caption of an uploaded photo"
+//	@Param			keep_location	formData	bool				false	"keep the GPS position in the uploaded image"
+//	@Param			image			formData	file				false	"image to upload"
+//	@Success		200				{object}	model.Photo
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		413				{object}	pkg.ErrorResponse
+//	@Failure		415				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/photos [post]
 func (p *photoHandlerImpl) CreatePhoto(ctx *gin.Context) {
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		p.uploadPhoto(ctx)
+		return
+	}
+
 	photoCreate := model.CreatePhoto{}
 
 	err := ctx.ShouldBindJSON(&photoCreate)
@@ -117,20 +230,13 @@ func (p *photoHandlerImpl) CreatePhoto(ctx *gin.Context) {
 	}
 
 	// Get user info from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
-		return
-	}
-
-	userId, ok := user.(float64)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
 		return
 	}
 
 	// Call service to create photo
-	photo, err := p.svc.CreatePhoto(ctx, photoCreate, int(userId))
+	photo, err := p.svc.CreatePhoto(ctx, photoCreate, int(principal.UserID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -139,6 +245,49 @@ func (p *photoHandlerImpl) CreatePhoto(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, photo)
 }
 
+func (p *photoHandlerImpl) uploadPhoto(ctx *gin.Context) {
+	// bigger bodies fail while parsing instead of filling the disk
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, p.maxUploadBytes+MULTIPART_OVERHEAD)
+
+	upload := model.UploadPhoto{}
+	if err := ctx.ShouldBind(&upload); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, pkg.ErrorResponse{Message: service.ErrImageTooLarge.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+	if err := upload.PhotoValidate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body", Errors: []string{err.Error()}})
+		return
+	}
+	image, header, err := ctx.Request.FormFile("image")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body", Errors: []string{"image is required"}})
+		return
+	}
+	defer image.Close()
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	photo, err := p.svc.UploadPhoto(ctx, upload, image, header.Size, int(principal.UserID))
+	switch {
+	case errors.Is(err, service.ErrImageTooLarge):
+		ctx.JSON(http.StatusRequestEntityTooLarge, pkg.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrUnsupportedImageType):
+		ctx.JSON(http.StatusUnsupportedMediaType, pkg.ErrorResponse{Message: err.Error()})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+	default:
+		ctx.JSON(http.StatusOK, photo)
+	}
+}
+
 // DeleteUsersById godoc
 //
 //		@Summary		Delete user by selected id
@@ -162,30 +311,25 @@ func (p *photoHandlerImpl) DeletePhoto(ctx *gin.Context) {
 	}
 
 	// Get user info from context
-	user, ok := ctx.Get(middleware.CLAIM_USER_ID)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "user information not found in context"})
 		return
 	}
 
-	userId, ok := user.(float64)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user ID in context"})
+	// Call service to delete photo
+	err = p.svc.DeletePhoto(ctx, photoID, principal)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
-
-	// Check if the photo belongs to the user
-	if photoID != int(userId) {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "invalid request: photo does not belong to the user"})
+	if errors.Is(err, service.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: err.Error()})
 		return
 	}
-
-	// Call service to delete photo
-	err = p.svc.DeletePhoto(ctx, photoID, int(userId))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, principal.UserID)
 }
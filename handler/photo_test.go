@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mygram/middleware"
+	"mygram/model"
+	"mygram/service"
+	"mygram/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUploadPhoto(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newBody := func(title string, image []byte) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		form := multipart.NewWriter(body)
+		form.WriteField("title", title)
+		if image != nil {
+			part, _ := form.CreateFormFile("image", "photo.png")
+			part.Write(image)
+		}
+		form.Close()
+		return body, form.FormDataContentType()
+	}
+
+	testCases := []struct {
+		desc   string
+		title  string
+		image  []byte
+		err    error
+		status int
+	}{
+		{desc: "success", title: "title", image: []byte("png"), status: http.StatusOK},
+		{desc: "error unsupported type", title: "title", image: []byte("svg"), err: service.ErrUnsupportedImageType, status: http.StatusUnsupportedMediaType},
+		{desc: "error body over the limit", title: "title", image: bytes.Repeat([]byte{1}, 2*MULTIPART_OVERHEAD), status: http.StatusRequestEntityTooLarge},
+		{desc: "error missing image", title: "title", status: http.StatusBadRequest},
+		{desc: "error missing title", image: []byte("png"), status: http.StatusBadRequest},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svcMock := mocks.NewPhotosService(t)
+			if tC.status == http.StatusOK || tC.err != nil {
+				svcMock.On("UploadPhoto", mock.Anything, model.UploadPhoto{Title: tC.title}, mock.Anything, int64(len(tC.image)), 1).
+					Return(&model.Photo{ID: 1}, tC.err)
+			}
+
+			g := gin.New()
+			g.POST("/photos", func(ctx *gin.Context) {
+				ctx.Set(middleware.CLAIM_PRINCIPAL, model.Principal{UserID: 1})
+			}, NewPhotoHandler(svcMock, 16).CreatePhoto)
+			body, contentType := newBody(tC.title, tC.image)
+			req := httptest.NewRequest(http.MethodPost, "/photos", body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, req)
+			assert.Equal(t, tC.status, rec.Code)
+		})
+	}
+}
+
+func TestGetPhoto(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	session := model.Principal{UserID: 1}
+	photosOnlyKey := model.Principal{UserID: 1, APIKeyID: 5, Scopes: []string{model.SCOPE_PHOTOS_READ}}
+	testCases := []struct {
+		desc      string
+		path      string
+		principal model.Principal
+		detail    *model.PhotoDetail
+		err       error
+		status    int
+		body      string
+	}{
+		{
+			desc:      "success",
+			path:      "/photos/10",
+			principal: session,
+			detail:    &model.PhotoDetail{ID: 10, Comments: &model.PhotoComments{Comments: []model.PhotoComment{}, NextCursor: "next"}},
+			status:    http.StatusOK,
+			body:      `"comments":{"comments":[],"next_cursor":"next"}`,
+		},
+		{
+			desc:      "success photos only api key gets no comments",
+			path:      "/photos/10",
+			principal: photosOnlyKey,
+			detail:    &model.PhotoDetail{ID: 10},
+			status:    http.StatusOK,
+		},
+		{desc: "error not found", path: "/photos/10", principal: session, err: service.ErrPhotoNotFound, status: http.StatusNotFound},
+		{desc: "error invalid id", path: "/photos/abc", principal: session, status: http.StatusBadRequest},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svcMock := mocks.NewPhotosService(t)
+			if tC.status != http.StatusBadRequest {
+				svcMock.On("GetPhoto", mock.Anything, 10, tC.principal).Return(tC.detail, tC.err)
+			}
+
+			g := gin.New()
+			g.GET("/photos/:photoId", func(ctx *gin.Context) {
+				ctx.Set(middleware.CLAIM_PRINCIPAL, tC.principal)
+			}, NewPhotoHandler(svcMock, 16).GetPhoto)
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tC.path, nil))
+			assert.Equal(t, tC.status, rec.Code)
+			if tC.status != http.StatusOK {
+				return
+			}
+			if tC.body != "" {
+				assert.Contains(t, rec.Body.String(), tC.body)
+			} else {
+				assert.NotContains(t, rec.Body.String(), `"comments"`)
+			}
+		})
+	}
+}
+
+func TestGetPhotoComments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc   string
+		query  string
+		req    *model.CommentsRequest
+		page   model.PhotoComments
+		err    error
+		status int
+		body   string
+	}{
+		{
+			desc:   "success default limit",
+			req:    &model.CommentsRequest{Limit: model.DEFAULT_PAGE_SIZE},
+			page:   model.PhotoComments{Comments: []model.PhotoComment{{ID: 1, Message: "hi"}}, NextCursor: "next"},
+			status: http.StatusOK,
+			body:   `"next_cursor":"next"`,
+		},
+		{
+			desc:   "success limit and cursor",
+			query:  "?limit=5&cursor=abc",
+			req:    &model.CommentsRequest{Cursor: "abc", Limit: 5},
+			page:   model.PhotoComments{Comments: []model.PhotoComment{}},
+			status: http.StatusOK,
+			body:   `{"comments":[]}`,
+		},
+		{desc: "error invalid cursor", query: "?cursor=abc", req: &model.CommentsRequest{Cursor: "abc", Limit: model.DEFAULT_PAGE_SIZE}, err: model.ErrInvalidCommentCursor, status: http.StatusBadRequest},
+		{desc: "error not found", req: &model.CommentsRequest{Limit: model.DEFAULT_PAGE_SIZE}, err: service.ErrPhotoNotFound, status: http.StatusNotFound},
+		{desc: "error limit too large", query: "?limit=101", status: http.StatusBadRequest},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svcMock := mocks.NewPhotosService(t)
+			if tC.req != nil {
+				svcMock.On("GetPhotoComments", mock.Anything, 10, *tC.req).Return(tC.page, tC.err)
+			}
+
+			g := gin.New()
+			g.GET("/photos/:photoId/comments", NewPhotoHandler(svcMock, 16).GetPhotoComments)
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/10/comments"+tC.query, nil))
+			assert.Equal(t, tC.status, rec.Code)
+			if tC.body != "" {
+				assert.Contains(t, rec.Body.String(), tC.body)
+			}
+		})
+	}
+}
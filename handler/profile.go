@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mygram/model"
+	"mygram/pkg"
+	"mygram/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler interface {
+	GetProfile(ctx *gin.Context)
+	GetProfileByUsername(ctx *gin.Context)
+}
+
+type profileHandlerImpl struct {
+	svc service.ProfileService
+}
+
+func NewProfileHandler(svc service.ProfileService) ProfileHandler {
+	return &profileHandlerImpl{svc: svc}
+}
+
+// GetProfile godoc
+//
+//	@Summary		Show the public profile of a user
+//	@Description	will return the public fields of the user, a page of their photos newest first and all their social medias
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			userId		path		int	true	"User ID"
+//	@Param			page		query		int	false	"page, starting at 1"
+//	@Param			page_size	query		int	false	"photos per page, at most 100"
+//	@Success		200			{object}	model.PublicProfile
+//	@Failure		400			{object}	pkg.ErrorResponse
+//	@Failure		404			{object}	pkg.ErrorResponse
+//	@Failure		500			{object}	pkg.ErrorResponse
+//	@Router			/users/{userId}/profile [get]
+func (p *profileHandlerImpl) GetProfile(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	page, ok := bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := p.svc.GetPublicProfile(ctx, id, page)
+	respondProfile(ctx, profile, err)
+}
+
+// GetProfileByUsername godoc
+//
+//	@Summary		Show the public profile of a user by username
+//	@Description	will return the public fields of the user, a page of their photos newest first and all their social medias
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			page		query		int		false	"page, starting at 1"
+//	@Param			page_size	query		int		false	"photos per page, at most 100"
+//	@Success		200			{object}	model.PublicProfile
+//	@Failure		400			{object}	pkg.ErrorResponse
+//	@Failure		404			{object}	pkg.ErrorResponse
+//	@Failure		500			{object}	pkg.ErrorResponse
+//	@Router			/users/by-username/{username}/profile [get]
+func (p *profileHandlerImpl) GetProfileByUsername(ctx *gin.Context) {
+	page, ok := bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := p.svc.GetPublicProfileByUsername(ctx, ctx.Param("username"), page)
+	respondProfile(ctx, profile, err)
+}
+
+// bindPage reads page and page_size, answering 400 when they are invalid.
+func bindPage(ctx *gin.Context) (model.PageRequest, bool) {
+	var page model.PageRequest
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return model.PageRequest{}, false
+	}
+	page, err := page.Normalize()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid query", Errors: []string{err.Error()}})
+		return model.PageRequest{}, false
+	}
+	return page, true
+}
+
+func respondProfile(ctx *gin.Context, profile model.PublicProfile, err error) {
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if profile.User.ID == 0 {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: "user not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
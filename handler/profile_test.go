@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"mygram/model"
+	"mygram/service/mocks"
+)
+
+func TestGetProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc    string
+		target  string
+		profile *model.PublicProfile
+		page    model.PageRequest
+		status  int
+	}{
+		{desc: "success default page", target: "/users/1/profile", profile: &model.PublicProfile{User: model.PublicUser{ID: 1}}, page: model.PageRequest{Page: 1, PageSize: model.DEFAULT_PAGE_SIZE}, status: http.StatusOK},
+		{desc: "success second page", target: "/users/1/profile?page=2&page_size=5", profile: &model.PublicProfile{User: model.PublicUser{ID: 1}}, page: model.PageRequest{Page: 2, PageSize: 5}, status: http.StatusOK},
+		{desc: "error user not found", target: "/users/1/profile", profile: &model.PublicProfile{}, page: model.PageRequest{Page: 1, PageSize: model.DEFAULT_PAGE_SIZE}, status: http.StatusNotFound},
+		{desc: "error page size too large", target: "/users/1/profile?page_size=1000", status: http.StatusBadRequest},
+		{desc: "error page not a number", target: "/users/1/profile?page=first", status: http.StatusBadRequest},
+		{desc: "error invalid id", target: "/users/abc/profile", status: http.StatusBadRequest},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svcMock := mocks.NewProfileService(t)
+			if tC.profile != nil {
+				svcMock.On("GetPublicProfile", mock.Anything, uint64(1), tC.page).Return(*tC.profile, nil)
+			}
+
+			g := gin.New()
+			g.GET("/users/:userId/profile", NewProfileHandler(svcMock).GetProfile)
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tC.target, nil))
+			assert.Equal(t, tC.status, rec.Code)
+		})
+	}
+}
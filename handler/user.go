@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
 
 	"mygram/middleware"
 	"mygram/model"
 	"mygram/pkg"
+	"mygram/pkg/helper"
 	"mygram/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,22 +22,61 @@ type UserHandler interface {
 	GetUsersById(ctx *gin.Context)
 	DeleteUsersById(ctx *gin.Context)
 	UpdateUsersById(ctx *gin.Context)
+	UpdateUserRole(ctx *gin.Context)
+	GetMe(ctx *gin.Context)
+	UpdateMe(ctx *gin.Context)
+	DeleteMe(ctx *gin.Context)
 
 	// activity
 	UserSignUp(ctx *gin.Context)
 	UserSignIn(ctx *gin.Context)
+	RefreshToken(ctx *gin.Context)
+	VerifyEmail(ctx *gin.Context)
+	ResendVerificationEmail(ctx *gin.Context)
+	ForgotPassword(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	ChangePassword(ctx *gin.Context)
+	TwoFactorLogin(ctx *gin.Context)
+	EnrollTwoFactor(ctx *gin.Context)
+	ConfirmTwoFactor(ctx *gin.Context)
+	DisableTwoFactor(ctx *gin.Context)
+	Logout(ctx *gin.Context)
+	LogoutAll(ctx *gin.Context)
 }
 
 type userHandlerImpl struct {
-	svc service.UserService
+	svc           service.UserService
+	session       service.SessionService
+	verification  service.EmailVerificationService
+	passwordReset service.PasswordResetService
+	twoFactor     service.TwoFactorService
 }
 
-func NewUserHandler(svc service.UserService) UserHandler {
+func NewUserHandler(svc service.UserService, session service.SessionService, verification service.EmailVerificationService, passwordReset service.PasswordResetService, twoFactor service.TwoFactorService) UserHandler {
 	return &userHandlerImpl{
-		svc: svc,
+		svc:           svc,
+		session:       session,
+		verification:  verification,
+		passwordReset: passwordReset,
+		twoFactor:     twoFactor,
 	}
 }
 
+// UpdateUsersById godoc
+//
+//	@Summary		Update user by selected id
+//	@Description	will update the username, email and age of the user, only the user itself or a user manager may do so
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"User ID"
+//	@Param			body			body		model.User	true	"new user details"
+//	@Success		200				{object}	model.User
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		403				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/{id} [put]
 func (u *userHandlerImpl) UpdateUsersById(ctx *gin.Context) {
 	// Get user ID from path parameter
 	idStr := ctx.Param("userId")
@@ -43,15 +86,51 @@ func (u *userHandlerImpl) UpdateUsersById(ctx *gin.Context) {
 		return
 	}
 
+	// only the account itself or a user manager may change it
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+	if id != principal.UserID && !principal.HasPermission(model.PERMISSION_USERS_MANAGE) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: "invalid user request"})
+		return
+	}
+
+	u.updateUser(ctx, id)
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Update the current user
+//	@Description	will update the username, email and age of the user of the token, a new email has to be verified again
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			body			body		model.User	true	"new user details"
+//	@Success		200				{object}	model.User
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me [put]
+func (u *userHandlerImpl) UpdateMe(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+	u.updateUser(ctx, principal.UserID)
+}
+
+func (u *userHandlerImpl) updateUser(ctx *gin.Context, id uint64) {
 	// Get updated user details from request body
 	var updatedUser model.User
-	if err := ctx.BindJSON(&updatedUser); err != nil {
+	if err := ctx.ShouldBindJSON(&updatedUser); err != nil {
 		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
 		return
 	}
 
 	// Call service to update user
-	updatedUser, err = u.svc.UpdateUserByID(ctx, id, updatedUser)
+	updatedUser, err := u.svc.UpdateUserByID(ctx, id, updatedUser)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -61,6 +140,37 @@ func (u *userHandlerImpl) UpdateUsersById(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, updatedUser)
 }
 
+// GetMe godoc
+//
+//	@Summary		Show the current user
+//	@Description	will return the user of the token with the number of photos, comments and social medias they posted
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	model.UserProfile
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me [get]
+func (u *userHandlerImpl) GetMe(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := u.svc.GetProfile(ctx, principal.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if profile.ID == 0 {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: "user not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
+
 // ShowUsers godoc
 //
 //	@Summary		Show users list
@@ -97,12 +207,12 @@ func (u *userHandlerImpl) GetUsers(ctx *gin.Context) {
 //	@Router			/users/{id} [get]
 func (u *userHandlerImpl) GetUsersById(ctx *gin.Context) {
 	// get id user
-	id, err := strconv.Atoi(ctx.Param("userId"))
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
 	if id == 0 || err != nil {
 		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
 		return
 	}
-	user, err := u.svc.GetUsersById(ctx, uint64(id))
+	user, err := u.svc.GetUsersById(ctx, id)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -128,21 +238,53 @@ func (u *userHandlerImpl) UserSignUp(ctx *gin.Context) {
 	}
 
 	user, err := u.svc.SignUp(ctx, userSignUp)
+	var policyErr *helper.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid password", Errors: policyErr.Errors})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
+	// the account exists anyway, a lost mail can be sent again
+	if err := u.verification.SendVerification(ctx, user); err != nil {
+		log.Println("cannot send verification mail", err.Error())
+	}
+
 	token, err := u.svc.GenerateUserAccessToken(ctx, user)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	refreshToken, err := u.svc.GenerateUserRefreshToken(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
 	}
 
 	ctx.JSON(http.StatusOK, map[string]any{
-		"token": token,
+		"token":         token,
+		"refresh_token": refreshToken,
 	})
 }
 
+// UserSignIn godoc
+//
+//	@Summary		Login
+//	@Description	will exchange email and password for an access token and a refresh token, repeated failures lock the account and the client IP for a while. With two-factor on a challenge_token for /users/login/2fa is returned instead
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.UserSignIn	true	"credentials"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Failure		401		{object}	pkg.ErrorResponse
+//	@Failure		429		{object}	pkg.ErrorResponse
+//	@Failure		500		{object}	pkg.ErrorResponse
+//	@Router			/users/login [post]
 func (u *userHandlerImpl) UserSignIn(ctx *gin.Context) {
 	// Binding request body
 	var userSignIn model.UserSignIn
@@ -151,21 +293,95 @@ func (u *userHandlerImpl) UserSignIn(ctx *gin.Context) {
 		return
 	}
 
-	// Get user by username from service
-	user, err := u.svc.GetUsersByUsername(ctx, userSignIn.Email)
+	// Check credentials, throttled per account and per IP
+	user, err := u.svc.SignIn(ctx, userSignIn, ctx.ClientIP())
 	if err != nil {
+		var locked *service.LoginLockedError
+		if errors.As(err, &locked) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, pkg.ErrorResponse{Code: pkg.ERR_CODE_LOGIN_LOCKED, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Code: pkg.ERR_CODE_INVALID_CREDENTIALS, Message: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	// Validate request body
-	if err := userSignIn.Authenticate(user.Password); err != nil {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+	respondLogin(ctx, u.svc, u.twoFactor, user)
+}
+
+// TwoFactorLogin godoc
+//
+//	@Summary		Login second step
+//	@Description	will exchange the challenge token of /users/login and a TOTP or recovery code for an access token and a refresh token, wrong codes count as failed logins
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.TwoFactorLoginRequest	true	"challenge token and code"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Failure		401		{object}	pkg.ErrorResponse
+//	@Failure		429		{object}	pkg.ErrorResponse
+//	@Failure		500		{object}	pkg.ErrorResponse
+//	@Router			/users/login/2fa [post]
+func (u *userHandlerImpl) TwoFactorLogin(ctx *gin.Context) {
+	var req model.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	user, err := u.twoFactor.Login(ctx, req, ctx.ClientIP())
+	if err != nil {
+		var locked *service.LoginLockedError
+		if errors.As(err, &locked) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, pkg.ErrorResponse{Code: pkg.ERR_CODE_LOGIN_LOCKED, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidChallengeToken) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Code: pkg.ERR_CODE_INVALID_CREDENTIALS, Message: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	respondTokens(ctx, u.svc, user)
+}
+
+// respondLogin finishes a first login step, users with two-factor on get a
+// challenge for /users/login/2fa instead of a session.
+func respondLogin(ctx *gin.Context, svc service.UserService, twoFactor service.TwoFactorService, user model.User) {
+	if user.TwoFactorEnabledAt != nil {
+		challenge, err := twoFactor.Challenge(ctx, user)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, map[string]any{
+			"two_factor_required": true,
+			"challenge_token":     challenge,
+		})
 		return
 	}
+	respondTokens(ctx, svc, user)
+}
 
+// respondTokens starts a new session for user.
+func respondTokens(ctx *gin.Context, svc service.UserService, user model.User) {
 	// Generate access token
-	token, err := u.svc.GenerateUserAccessToken(ctx, user)
+	token, err := svc.GenerateUserAccessToken(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Generate refresh token
+	refreshToken, err := svc.GenerateUserRefreshToken(ctx, user)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -173,7 +389,349 @@ func (u *userHandlerImpl) UserSignIn(ctx *gin.Context) {
 
 	// Respond with access token
 	ctx.JSON(http.StatusOK, map[string]interface{}{
-		"token": token,
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
+// EnrollTwoFactor godoc
+//
+//	@Summary		Start two-factor enrollment
+//	@Description	will create a TOTP secret for the current user, two-factor is only turned on by /users/me/2fa/confirm
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	model.TOTPEnrollment
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/2fa/enroll [post]
+func (u *userHandlerImpl) EnrollTwoFactor(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	enrollment, err := u.twoFactor.Enroll(ctx, principal.UserID)
+	if errors.Is(err, service.ErrTwoFactorAlreadyEnabled) {
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTwoFactor godoc
+//
+//	@Summary		Turn two-factor on
+//	@Description	will turn two-factor on with a first code of the enrolled secret and return the recovery codes, they are only shown once
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string							true	"bearer token"
+//	@Param			body			body		model.ConfirmTwoFactorRequest	true	"code of the authenticator app"
+//	@Success		200				{object}	map[string][]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/2fa/confirm [post]
+func (u *userHandlerImpl) ConfirmTwoFactor(ctx *gin.Context) {
+	var req model.ConfirmTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := u.twoFactor.Confirm(ctx, principal.UserID, req.Code)
+	if errors.Is(err, service.ErrTwoFactorAlreadyEnabled) {
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrTwoFactorNotEnrolled) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{"recovery_codes": codes})
+}
+
+// DisableTwoFactor godoc
+//
+//	@Summary		Turn two-factor off
+//	@Description	will turn two-factor off for the current user, the password and a TOTP or recovery code are required
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string							true	"bearer token"
+//	@Param			body			body		model.DisableTwoFactorRequest	true	"password and code"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/2fa/disable [post]
+func (u *userHandlerImpl) DisableTwoFactor(ctx *gin.Context) {
+	var req model.DisableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err := u.twoFactor.Disable(ctx, principal.UserID, req)
+	if errors.Is(err, service.ErrTwoFactorNotEnabled) {
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrWrongPassword) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]string{"message": "two-factor authentication disabled"})
+}
+
+// RefreshToken godoc
+//
+//	@Summary		Rotate refresh token
+//	@Description	will exchange a refresh token for a new access token and refresh token, the old refresh token can not be used again
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.RefreshTokenRequest	true	"refresh token"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Failure		401		{object}	pkg.ErrorResponse
+//	@Failure		500		{object}	pkg.ErrorResponse
+//	@Router			/users/token/refresh [post]
+func (u *userHandlerImpl) RefreshToken(ctx *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	token, refreshToken, err := u.svc.RefreshUserToken(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "unauthorized", Errors: []string{err.Error()}})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify email address
+//	@Description	will mark the email of the user as verified, each token can only be used once
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.VerifyEmailRequest	true	"token from the verification mail"
+//	@Success		200		{object}	model.User
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Failure		500		{object}	pkg.ErrorResponse
+//	@Router			/users/verify-email [post]
+func (u *userHandlerImpl) VerifyEmail(ctx *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	user, err := u.verification.Verify(ctx, req.Token)
+	if errors.Is(err, service.ErrInvalidVerificationToken) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: service.ErrInvalidVerificationToken.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ResendVerificationEmail godoc
+//
+//	@Summary		Resend verification mail
+//	@Description	will send a new verification mail to the current user, older tokens stop working
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		202				{object}	map[string]string
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		409				{object}	pkg.ErrorResponse
+//	@Failure		429				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/verify-email/resend [post]
+func (u *userHandlerImpl) ResendVerificationEmail(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err := u.verification.Resend(ctx, principal.UserID)
+	if errors.Is(err, service.ErrEmailAlreadyVerified) {
+		ctx.JSON(http.StatusConflict, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrVerificationResendTooSoon) {
+		ctx.JSON(http.StatusTooManyRequests, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, map[string]string{"message": "verification mail sent"})
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Ask for a password reset
+//	@Description	will mail a reset link when the email belongs to an account, the response is the same either way
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.ForgotPasswordRequest	true	"email of the account"
+//	@Success		202		{object}	map[string]string
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Router			/users/password/forgot [post]
+func (u *userHandlerImpl) ForgotPassword(ctx *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	u.passwordReset.Forgot(ctx.Request.Context(), req.Email)
+	ctx.JSON(http.StatusAccepted, map[string]string{"message": "if the email belongs to an account, a reset link has been sent"})
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	will set a new password with the token from the reset mail, log out every session and revoke the api keys
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.ResetPasswordRequest	true	"reset token and new password"
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	pkg.ErrorResponse
+//	@Failure		500		{object}	pkg.ErrorResponse
+//	@Router			/users/password/reset [post]
+func (u *userHandlerImpl) ResetPassword(ctx *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+	err := u.passwordReset.Reset(ctx, req.Token, req.Password)
+	var policyErr *helper.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid password", Errors: policyErr.Errors})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]string{"message": "password has been reset, please log in again"})
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change password
+//	@Description	will replace the password of the current user, every other session is logged out and a new token pair is returned
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"bearer token"
+//	@Param			body			body		model.ChangePasswordRequest	true	"current and new password"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me/password [put]
+func (u *userHandlerImpl) ChangePassword(ctx *gin.Context) {
+	var req model.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err := u.svc.ChangePassword(ctx, principal.UserID, req)
+	var policyErr *helper.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid password", Errors: policyErr.Errors})
+		return
+	}
+	if errors.Is(err, service.ErrWrongPassword) || errors.Is(err, service.ErrSamePassword) {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// every session was logged out, this one included
+	user, err := u.svc.GetUsersById(ctx, principal.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	token, err := u.svc.GenerateUserAccessToken(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	refreshToken, err := u.svc.GenerateUserRefreshToken(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"token":         token,
+		"refresh_token": refreshToken,
 	})
 }
 
@@ -193,29 +751,93 @@ func (u *userHandlerImpl) UserSignIn(ctx *gin.Context) {
 //		@Router			/users/{id} [delete]
 func (u *userHandlerImpl) DeleteUsersById(ctx *gin.Context) {
 	// get id user
-	id, err := strconv.Atoi(ctx.Param("userId"))
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
 	if id == 0 || err != nil {
 		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
 		return
 	}
 
 	// check user id session from context
-	userId, ok := ctx.Get(middleware.CLAIM_USER_ID)
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "invalid user session"})
 		return
 	}
-	userIdInt, ok := userId.(float64)
+	if id != principal.UserID && !principal.HasPermission(model.PERMISSION_USERS_DELETE) {
+		ctx.JSON(http.StatusForbidden, pkg.ErrorResponse{Code: pkg.ERR_CODE_PERMISSION_DENIED, Message: "invalid user request"})
+		return
+	}
+
+	u.deleteUser(ctx, id)
+}
+
+// DeleteMe godoc
+//
+//	@Summary		Delete the current user
+//	@Description	will delete the user of the token and log out all of its sessions
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	model.User
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/me [delete]
+func (u *userHandlerImpl) DeleteMe(ctx *gin.Context) {
+	principal, ok := middleware.GetPrincipal(ctx)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid user id session"})
 		return
 	}
-	if id != int(userIdInt) {
-		ctx.JSON(http.StatusUnauthorized, pkg.ErrorResponse{Message: "invalid user request"})
+	u.deleteUser(ctx, principal.UserID)
+}
+
+func (u *userHandlerImpl) deleteUser(ctx *gin.Context, id uint64) {
+	user, err := u.svc.DeleteUsersById(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if user.ID == 0 {
+		ctx.JSON(http.StatusNotFound, pkg.ErrorResponse{Message: "user not found"})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+// UpdateUserRole godoc
+//
+//	@Summary		Change the role of a user
+//	@Description	will replace the role and extra permissions of the user, the user has to log in again
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"bearer token"
+//	@Param			id				path		int						true	"User ID"
+//	@Param			body			body		model.UpdateUserRole	true	"role and permissions"
+//	@Success		200				{object}	model.User
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		403				{object}	pkg.ErrorResponse
+//	@Failure		404				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/{id}/role [put]
+func (u *userHandlerImpl) UpdateUserRole(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := model.UpdateUserRole{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body", Errors: []string{err.Error()}})
 		return
 	}
 
-	user, err := u.svc.DeleteUsersById(ctx, uint64(id))
+	user, err := u.svc.UpdateUserRole(ctx, id, req)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
@@ -226,3 +848,67 @@ func (u *userHandlerImpl) DeleteUsersById(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, user)
 }
+
+// Logout godoc
+//
+//	@Summary		Logout current session
+//	@Description	will revoke the access token used for this request and, when given, its refresh token
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			body			body		model.LogoutRequest	false	"refresh token to revoke"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/logout [post]
+func (u *userHandlerImpl) Logout(ctx *gin.Context) {
+	var req model.LogoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid request body"})
+			return
+		}
+	}
+
+	// check token session from context
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	err := u.session.Revoke(ctx, principal.TokenID, principal.UserID, principal.ExpiresAt, req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{"message": "logged out"})
+}
+
+// LogoutAll godoc
+//
+//	@Summary		Logout all sessions
+//	@Description	will revoke every access and refresh token issued to the current user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	map[string]string
+//	@Failure		400				{object}	pkg.ErrorResponse
+//	@Failure		401				{object}	pkg.ErrorResponse
+//	@Failure		500				{object}	pkg.ErrorResponse
+//	@Router			/users/logout-all [post]
+func (u *userHandlerImpl) LogoutAll(ctx *gin.Context) {
+	// check user id session from context
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return
+	}
+
+	if err := u.session.RevokeAll(ctx, principal.UserID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{"message": "logged out from all sessions"})
+}
@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	"mygram/middleware"
 	"mygram/model"
 	"mygram/service/mocks"
 )
@@ -48,7 +51,7 @@ func TestUserSignUp(t *testing.T) {
 	t.Run("error sign up service", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 
-		req := httptest.NewRequest(http.MethodPost, "/users/sign-up", bytes.NewBuffer([]byte(`{"username":"username","password":"abc12345"}`)))
+		req := httptest.NewRequest(http.MethodPost, "/users/sign-up", bytes.NewBuffer([]byte(`{"username":"username","email":"user@mail.com","password":"abc12345","age":"2000-01-01T00:00:00Z"}`)))
 		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 		// gin context mock
@@ -57,7 +60,7 @@ func TestUserSignUp(t *testing.T) {
 
 		svcMock := mocks.NewUserService(t)
 		svcMock.
-			On("SignUp", g, model.UserSignUp{Username: "username", Password: "abc12345"}).
+			On("SignUp", g, model.UserSignUp{Username: "username", Email: "user@mail.com", Password: "abc12345", DoB: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}).
 			Return(model.User{}, errors.New("some error"))
 
 		usrHdl := userHandlerImpl{svc: svcMock}
@@ -66,3 +69,91 @@ func TestUserSignUp(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Result().StatusCode)
 	})
 }
+
+func TestForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// known and unknown emails must get the same answer
+	var bodies []string
+	for _, email := range []string{"user@mail.com", "unknown@mail.com"} {
+		req := httptest.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewBuffer([]byte(`{"email":"`+email+`"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		g, _ := gin.CreateTestContext(rec)
+		g.Request = req
+
+		resetMock := mocks.NewPasswordResetService(t)
+		resetMock.On("Forgot", req.Context(), email).Return()
+
+		usrHdl := userHandlerImpl{passwordReset: resetMock}
+		usrHdl.ForgotPassword(g)
+
+		assert.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
+		bodies = append(bodies, rec.Body.String())
+	}
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestUsersMe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// newRouter mounts the user routes behind a stub auth logging in user 1
+	newRouter := func(svcMock *mocks.UserService, principal model.Principal) *gin.Engine {
+		g := gin.New()
+		usrHdl := userHandlerImpl{svc: svcMock}
+		users := g.Group("/users", func(ctx *gin.Context) {
+			ctx.Set(middleware.CLAIM_PRINCIPAL, principal)
+		})
+		users.GET("/me", usrHdl.GetMe)
+		users.PUT("/me", usrHdl.UpdateMe)
+		users.GET("/:userId", usrHdl.GetUsersById)
+		users.PUT("/:userId", usrHdl.UpdateUsersById)
+		return g
+	}
+	user := model.Principal{UserID: 1, Role: model.ROLE_USER}
+
+	t.Run("success get me with stats", func(t *testing.T) {
+		svcMock := mocks.NewUserService(t)
+		svcMock.On("GetProfile", mock.Anything, uint64(1)).Return(model.UserProfile{
+			User:      model.User{ID: 1, Username: "user"},
+			UserStats: model.UserStats{PhotoCount: 3, CommentCount: 5, SocialMediaCount: 1},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		newRouter(svcMock, user).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/me", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"id":1`)
+		assert.Contains(t, rec.Body.String(), `"photo_count":3,"comment_count":5,"social_media_count":1`)
+	})
+
+	t.Run("success update me", func(t *testing.T) {
+		svcMock := mocks.NewUserService(t)
+		svcMock.On("UpdateUserByID", mock.Anything, uint64(1), model.User{Username: "renamed"}).Return(model.User{ID: 1, Username: "renamed"}, nil)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/me", bytes.NewBufferString(`{"username":"renamed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		newRouter(svcMock, user).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("error update another user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBufferString(`{"username":"renamed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		newRouter(mocks.NewUserService(t), user).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("success manager updates another user", func(t *testing.T) {
+		svcMock := mocks.NewUserService(t)
+		svcMock.On("UpdateUserByID", mock.Anything, uint64(2), model.User{Username: "renamed"}).Return(model.User{ID: 2, Username: "renamed"}, nil)
+
+		admin := model.Principal{UserID: 1, Role: model.ROLE_ADMIN, Permissions: model.RolePermissions[model.ROLE_ADMIN]}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBufferString(`{"username":"renamed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		newRouter(svcMock, admin).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"mygram/config"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrSignedURLUnsupported is returned by stores whose objects can only
+	// be read through Get.
+	ErrSignedURLUnsupported = errors.New("signed urls are not supported by this store")
+)
+
+// blobKeyPattern keeps keys safe as file paths and URL paths alike.
+var blobKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*(/[a-z0-9][a-z0-9_.-]*)*$`)
+
+type BlobInfo struct {
+	ContentType string
+	Size        int64
+}
+
+type BlobStore interface {
+	// Put stores size bytes of r under key, replacing what was there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrBlobNotFound for unknown keys, close the reader when done.
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// Delete does nothing for unknown keys.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL anyone can read the object from until ttl
+	// passed, or ErrSignedURLUnsupported.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// NewBlobStore builds the store selected by cfg.Driver.
+func NewBlobStore(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalBlobStore(cfg.Local.Dir)
+	case "s3":
+		return NewS3BlobStore(cfg.S3), nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+}
+
+// ValidBlobKey tells if key can be handed to a BlobStore. Keys are
+// lowercase slash separated segments without dot segments.
+func ValidBlobKey(key string) bool {
+	if !blobKeyPattern.MatchString(key) {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.Trim(segment, ".") == "" {
+			return false
+		}
+	}
+	return true
+}
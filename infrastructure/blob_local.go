@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+type localBlobStoreImpl struct {
+	dir string
+}
+
+// NewLocalBlobStore keeps objects as files below dir, which is created when
+// missing. The content type is derived from the extension of the key.
+func NewLocalBlobStore(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("cannot create storage dir %s: %w", dir, err)
+	}
+	return &localBlobStoreImpl{dir: dir}, nil
+}
+
+func (l *localBlobStoreImpl) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !ValidBlobKey(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	name := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+
+	// readers never see a half written file
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("blob %s: wrote %d bytes, expected %d", key, written, size)
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (l *localBlobStoreImpl) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	if !ValidBlobKey(key) {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	file, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, BlobInfo{}, err
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return file, BlobInfo{ContentType: contentType, Size: stat.Size()}, nil
+}
+
+func (l *localBlobStoreImpl) Delete(ctx context.Context, key string) error {
+	if !ValidBlobKey(key) {
+		return nil
+	}
+	if err := os.Remove(l.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *localBlobStoreImpl) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "", ErrSignedURLUnsupported
+}
+
+func (l *localBlobStoreImpl) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
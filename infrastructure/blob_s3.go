@@ -0,0 +1,269 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mygram/config"
+)
+
+const (
+	S3_ALGORITHM = "AWS4-HMAC-SHA256"
+	// S3_UNSIGNED_PAYLOAD lets uploads stream instead of being hashed first,
+	// the body is still covered by TLS on https endpoints.
+	S3_UNSIGNED_PAYLOAD   = "UNSIGNED-PAYLOAD"
+	S3_MAX_SIGNED_URL_TTL = 7 * 24 * time.Hour
+
+	s3TimeFormat = "20060102T150405Z"
+	s3DateFormat = "20060102"
+)
+
+// s3BlobStoreImpl talks to the S3 REST API directly, requests are signed
+// with AWS Signature Version 4.
+type s3BlobStoreImpl struct {
+	cfg    config.S3StorageConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3BlobStore(cfg config.S3StorageConfig) BlobStore {
+	return &s3BlobStoreImpl{cfg: cfg, client: &http.Client{}, now: time.Now}
+}
+
+func (s *s3BlobStoreImpl) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3BlobStoreImpl) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	return resp.Body, BlobInfo{ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+}
+
+func (s *s3BlobStoreImpl) Delete(ctx context.Context, key string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err == ErrBlobNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3BlobStoreImpl) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if ttl < time.Second || ttl > S3_MAX_SIGNED_URL_TTL {
+		return "", fmt.Errorf("signed url ttl must be between 1s and %s", S3_MAX_SIGNED_URL_TTL)
+	}
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	return s.presign(http.MethodGet, u, ttl, s.now()), nil
+}
+
+// do signs and sends req, answers other than 2xx become errors and 404
+// becomes ErrBlobNotFound.
+func (s *s3BlobStoreImpl) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, S3_UNSIGNED_PAYLOAD, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlobNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, body)
+}
+
+func (s *s3BlobStoreImpl) objectURL(key string) (*url.URL, error) {
+	if !ValidBlobKey(key) {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+	u, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(u.Path, "/")
+	if s.cfg.PathStyle {
+		u.Path = base + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	return u, nil
+}
+
+// sign adds the Authorization header, covering the host and every header
+// already set on req.
+func (s *s3BlobStoreImpl) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	scope := s.scope(t)
+	signature := s.signature(t, scope, strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n"))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		S3_ALGORITHM, s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// presign returns u with the query parameters that authorize method on it
+// until ttl passed, only the host header is signed.
+func (s *s3BlobStoreImpl) presign(method string, u *url.URL, ttl time.Duration, t time.Time) string {
+	t = t.UTC()
+	scope := s.scope(t)
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", S3_ALGORITHM)
+	query.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", t.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(ttl/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	signature := s.signature(t, scope, strings.Join([]string{
+		method,
+		s3EscapePath(u.Path),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		S3_UNSIGNED_PAYLOAD,
+	}, "\n"))
+
+	signed := *u
+	signed.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return signed.String()
+}
+
+func (s *s3BlobStoreImpl) scope(t time.Time) string {
+	return t.Format(s3DateFormat) + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *s3BlobStoreImpl) signature(t time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := S3_ALGORITHM + "\n" + t.Format(s3TimeFormat) + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + s.cfg.SecretAccessKey)
+	for _, part := range []string{t.Format(s3DateFormat), s.cfg.Region, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	return hex.EncodeToString(key)
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func s3EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return s3Escape(p, false)
+}
+
+// s3Escape percent-encodes everything but the RFC 3986 unreserved
+// characters, and '/' unless escapeSlash.
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !escapeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
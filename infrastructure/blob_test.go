@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure/s3test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStores(t *testing.T) {
+	server := s3test.NewServer("mygram")
+	defer server.Close()
+
+	local, err := NewLocalBlobStore(t.TempDir())
+	assert.Nil(t, err)
+	stores := map[string]BlobStore{
+		"local": local,
+		"s3": NewS3BlobStore(config.S3StorageConfig{
+			Endpoint:        server.URL,
+			Region:          s3test.REGION,
+			Bucket:          "mygram",
+			AccessKeyID:     s3test.ACCESS_KEY_ID,
+			SecretAccessKey: s3test.SECRET_ACCESS_KEY,
+			PathStyle:       true,
+		}),
+	}
+	for name, store := range stores {
+		t.Run("success round trip "+name, func(t *testing.T) {
+			ctx := context.Background()
+			err := store.Put(ctx, "photos/1/a.png", strings.NewReader("image"), 5, "image/png")
+			assert.Nil(t, err)
+
+			r, info, err := store.Get(ctx, "photos/1/a.png")
+			assert.Nil(t, err)
+			data, _ := io.ReadAll(r)
+			r.Close()
+			assert.Equal(t, "image", string(data))
+			assert.Equal(t, BlobInfo{ContentType: "image/png", Size: 5}, info)
+
+			assert.Nil(t, store.Delete(ctx, "photos/1/a.png"))
+			assert.Nil(t, store.Delete(ctx, "photos/1/a.png"))
+			_, _, err = store.Get(ctx, "photos/1/a.png")
+			assert.ErrorIs(t, err, ErrBlobNotFound)
+		})
+
+		t.Run("error short body "+name, func(t *testing.T) {
+			err := store.Put(context.Background(), "photos/1/b.png", strings.NewReader("ima"), 5, "image/png")
+			assert.NotNil(t, err)
+		})
+	}
+
+	t.Run("success s3 signed url", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Nil(t, stores["s3"].Put(ctx, "photos/1/c.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+
+		signed, err := stores["s3"].SignedURL(ctx, "photos/1/c.jpg", time.Minute)
+		assert.Nil(t, err)
+		resp, err := http.Get(signed)
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "jpeg", string(data))
+
+		// tampering with the key breaks the signature
+		resp, err = http.Get(strings.Replace(signed, "c.jpg", "d.jpg", 1))
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("error s3 wrong secret", func(t *testing.T) {
+		store := NewS3BlobStore(config.S3StorageConfig{Endpoint: server.URL, Region: s3test.REGION, Bucket: "mygram", AccessKeyID: s3test.ACCESS_KEY_ID, SecretAccessKey: "wrong", PathStyle: true})
+		err := store.Put(context.Background(), "photos/1/e.png", strings.NewReader("image"), 5, "image/png")
+		assert.ErrorContains(t, err, "403")
+	})
+
+	t.Run("error local signed url", func(t *testing.T) {
+		_, err := local.SignedURL(context.Background(), "photos/1/a.png", time.Minute)
+		assert.ErrorIs(t, err, ErrSignedURLUnsupported)
+	})
+}
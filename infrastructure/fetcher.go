@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrFetchForbiddenAddress = errors.New("address is not public")
+	ErrFetchTooLarge         = errors.New("response is too large")
+)
+
+// Fetcher downloads files from user supplied URLs.
+type Fetcher interface {
+	// Fetch returns the body of a GET on rawURL, at most maxBytes long.
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+type fetcherImpl struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewFetcher refuses loopback, private and link-local addresses unless
+// allowPrivate, so users can not make the server reach internal services.
+// The check runs on every connection, after DNS resolution and redirects.
+func NewFetcher(maxBytes int64, timeout time.Duration, allowPrivate bool) Fetcher {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrFetchForbiddenAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return &fetcherImpl{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return checkFetchURL(req.URL)
+			},
+		},
+		maxBytes: maxBytes,
+	}
+}
+
+func (f *fetcherImpl) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFetchURL(u); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", u.Redacted(), resp.Status)
+	}
+	if resp.ContentLength > f.maxBytes {
+		return nil, ErrFetchTooLarge
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.maxBytes {
+		return nil, ErrFetchTooLarge
+	}
+	return body, nil
+}
+
+func checkFetchURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
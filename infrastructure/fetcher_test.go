@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			w.Write([]byte(strings.Repeat("a", 20)))
+		case "/redirect":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		default:
+			w.Write([]byte("image"))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("success private allowed", func(t *testing.T) {
+		body, err := NewFetcher(10, time.Second, true).Fetch(context.Background(), server.URL+"/image.png")
+		assert.Nil(t, err)
+		assert.Equal(t, "image", string(body))
+	})
+
+	t.Run("error loopback", func(t *testing.T) {
+		_, err := NewFetcher(10, time.Second, false).Fetch(context.Background(), server.URL+"/image.png")
+		assert.ErrorIs(t, err, ErrFetchForbiddenAddress)
+	})
+
+	t.Run("error too large", func(t *testing.T) {
+		_, err := NewFetcher(10, time.Second, true).Fetch(context.Background(), server.URL+"/large")
+		assert.ErrorIs(t, err, ErrFetchTooLarge)
+	})
+
+	t.Run("error redirect to other scheme", func(t *testing.T) {
+		_, err := NewFetcher(10, time.Second, true).Fetch(context.Background(), server.URL+"/redirect")
+		assert.ErrorContains(t, err, "unsupported url scheme")
+	})
+
+	t.Run("success public addresses", func(t *testing.T) {
+		for ip, public := range map[string]bool{"93.184.216.34": true, "10.0.0.1": false, "169.254.169.254": false, "::1": false, "fd00::1": false, "0.0.0.0": false} {
+			assert.Equal(t, public, isPublicIP(net.ParseIP(ip)), ip)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"mygram/config"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, m Mail) error
+}
+
+// NewMailer builds the mailer selected by cfg.Driver.
+func NewMailer(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTP, cfg.From), nil
+	case "file":
+		return NewFileMailer(cfg.Dir, cfg.From), nil
+	case "log":
+		return NewLogMailer(cfg.From), nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+}
+
+type smtpMailerImpl struct {
+	cfg  config.SMTPConfig
+	from string
+}
+
+func NewSMTPMailer(cfg config.SMTPConfig, from string) Mailer {
+	return &smtpMailerImpl{cfg: cfg, from: from}
+}
+
+func (s *smtpMailerImpl) Send(ctx context.Context, m Mail) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", s.from, err)
+	}
+	msg, err := buildMessage(s.from, m)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	// net/smtp has no context support, give up on our side when it is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, from.Address, []string{m.To}, msg)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fileMailerImpl struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer writes every mail to dir as an .eml file, for local
+// development and tests.
+func NewFileMailer(dir, from string) Mailer {
+	return &fileMailerImpl{dir: dir, from: from}
+}
+
+func (f *fileMailerImpl) Send(ctx context.Context, m Mail) error {
+	msg, err := buildMessage(f.from, m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(m.To))
+	return os.WriteFile(filepath.Join(f.dir, name), msg, 0o600)
+}
+
+type logMailerImpl struct {
+	from string
+}
+
+// NewLogMailer prints mails to the application log instead of sending them.
+func NewLogMailer(from string) Mailer {
+	return &logMailerImpl{from: from}
+}
+
+func (l *logMailerImpl) Send(ctx context.Context, m Mail) error {
+	msg, err := buildMessage(l.from, m)
+	if err != nil {
+		return err
+	}
+	log.Printf("mail to %s:\n%s", m.To, msg)
+	return nil
+}
+
+func buildMessage(from string, m Mail) ([]byte, error) {
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", m.To, err)
+	}
+	// reject header injection through the subject
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return nil, fmt.Errorf("invalid subject %q", m.Subject)
+	}
+
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
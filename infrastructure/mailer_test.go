@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer(t *testing.T) {
+	t.Run("success write eml", func(t *testing.T) {
+		dir := t.TempDir()
+		mailer := NewFileMailer(dir, "mygram <no-reply@localhost>")
+
+		err := mailer.Send(context.Background(), Mail{To: "user@mail.com", Subject: "Verify your email address", Body: "hello\nworld"})
+		assert.Nil(t, err)
+
+		files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+		assert.Equal(t, 1, len(files))
+		content, _ := os.ReadFile(files[0])
+		assert.Contains(t, string(content), "To: <user@mail.com>\r\n")
+		assert.Contains(t, string(content), "hello\r\nworld")
+	})
+
+	t.Run("error header injection", func(t *testing.T) {
+		mailer := NewFileMailer(t.TempDir(), "mygram <no-reply@localhost>")
+		err := mailer.Send(context.Background(), Mail{To: "user@mail.com", Subject: "hi\r\nBcc: victim@mail.com"})
+		assert.NotNil(t, err)
+	})
+}
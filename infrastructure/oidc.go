@@ -0,0 +1,344 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/pkg/helper"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// OIDC_LEEWAY is the clock skew allowed on ID token timestamps.
+	OIDC_LEEWAY = time.Minute
+	// OIDC_KEYS_REFRESH_GAP limits how often an unknown kid refetches the
+	// provider keys.
+	OIDC_KEYS_REFRESH_GAP = time.Minute
+)
+
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// OIDCProvider runs the authorization code flow with PKCE against one
+// OpenID Connect provider.
+type OIDCProvider interface {
+	// AuthCodeURL is where the browser is sent to log in, codeChallenge is
+	// the S256 PKCE challenge.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange trades the code for tokens and returns the claims of the ID
+	// token once its signature, issuer, audience and expiry were checked.
+	Exchange(ctx context.Context, code, codeVerifier string) (model.OIDCClaims, error)
+}
+
+// NewOIDCProviders builds every configured provider by name.
+func NewOIDCProviders(cfg config.OIDCConfig) map[string]OIDCProvider {
+	providers := map[string]OIDCProvider{}
+	for _, provider := range cfg.Providers {
+		providers[provider.Name] = NewOIDCProvider(provider, http.DefaultClient)
+	}
+	return providers
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProviderImpl struct {
+	cfg    config.OIDCProviderConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider reads the provider metadata lazily, on first use.
+func NewOIDCProvider(cfg config.OIDCProviderConfig, client *http.Client) OIDCProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = defaultOIDCScopes
+	}
+	return &oidcProviderImpl{cfg: cfg, client: client, now: time.Now}
+}
+
+func (o *oidcProviderImpl) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", o.cfg.ClientID)
+	query.Set("redirect_uri", o.cfg.RedirectURL)
+	query.Set("scope", strings.Join(o.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+func (o *oidcProviderImpl) Exchange(ctx context.Context, code, codeVerifier string) (model.OIDCClaims, error) {
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return model.OIDCClaims{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", o.cfg.RedirectURL)
+	form.Set("client_id", o.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return model.OIDCClaims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.cfg.ClientID), url.QueryEscape(o.cfg.ClientSecret))
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return model.OIDCClaims{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer res.Body.Close()
+	body := struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&body); err != nil {
+		return model.OIDCClaims{}, fmt.Errorf("invalid token response (status %d): %w", res.StatusCode, err)
+	}
+	if res.StatusCode != http.StatusOK || body.Error != "" {
+		return model.OIDCClaims{}, fmt.Errorf("token request failed (status %d): %s %s", res.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return model.OIDCClaims{}, errors.New("token response has no id_token")
+	}
+	return o.verifyIDToken(ctx, body.IDToken)
+}
+
+type idTokenClaims struct {
+	Iss               string       `json:"iss"`
+	Sub               string       `json:"sub"`
+	Aud               audience     `json:"aud"`
+	Azp               string       `json:"azp"`
+	Exp               int64        `json:"exp"`
+	Iat               int64        `json:"iat"`
+	Nonce             string       `json:"nonce"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	Name              string       `json:"name"`
+	PreferredUsername string       `json:"preferred_username"`
+}
+
+// audience is a single string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// flexibleBool also accepts "true" and "false", some providers send
+// email_verified as a string.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+func (o *oidcProviderImpl) verifyIDToken(ctx context.Context, token string) (model.OIDCClaims, error) {
+	// claims are checked below, with the leeway we allow
+	parser := jwt.Parser{SkipClaimsValidation: true, UseJSONNumber: true}
+	parsed, err := parser.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected id_token algorithm %s", t.Method.Alg())
+		}
+		kid, _ := t.Header["kid"].(string)
+		return o.getKey(ctx, kid)
+	})
+	if err != nil {
+		return model.OIDCClaims{}, fmt.Errorf("invalid id_token: %w", err)
+	}
+	mapClaims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return model.OIDCClaims{}, errors.New("invalid id_token claims")
+	}
+	claims := idTokenClaims{}
+	if err := helper.DecodeClaim(mapClaims, &claims); err != nil {
+		return model.OIDCClaims{}, fmt.Errorf("invalid id_token claims: %w", err)
+	}
+
+	now := o.now()
+	switch {
+	case claims.Iss != o.cfg.Issuer:
+		return model.OIDCClaims{}, fmt.Errorf("id_token issued by %q", claims.Iss)
+	case !containsString(claims.Aud, o.cfg.ClientID):
+		return model.OIDCClaims{}, errors.New("id_token is not meant for this client")
+	case len(claims.Aud) > 1 && claims.Azp != o.cfg.ClientID:
+		return model.OIDCClaims{}, errors.New("id_token authorized party is not this client")
+	case claims.Exp == 0 || now.After(time.Unix(claims.Exp, 0).Add(OIDC_LEEWAY)):
+		return model.OIDCClaims{}, errors.New("id_token has expired")
+	case time.Unix(claims.Iat, 0).After(now.Add(OIDC_LEEWAY)):
+		return model.OIDCClaims{}, errors.New("id_token issued in the future")
+	case claims.Sub == "":
+		return model.OIDCClaims{}, errors.New("id_token has no subject")
+	}
+
+	return model.OIDCClaims{
+		Subject:           claims.Sub,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+		Nonce:             claims.Nonce,
+	}, nil
+}
+
+func (o *oidcProviderImpl) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	discovery := &oidcDiscovery{}
+	if err := o.getJSON(ctx, strings.TrimSuffix(o.cfg.Issuer, "/")+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, fmt.Errorf("cannot read provider metadata: %w", err)
+	}
+	// the metadata must belong to the issuer we were configured with
+	if discovery.Issuer != o.cfg.Issuer {
+		return nil, fmt.Errorf("provider metadata is for issuer %q", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("provider metadata misses an endpoint")
+	}
+	o.discovery = discovery
+	return discovery, nil
+}
+
+func (o *oidcProviderImpl) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if key, ok := o.keys[kid]; ok {
+		return key, nil
+	}
+	// keys are rotated by the provider, refetch but not on every request
+	if o.keys != nil && o.now().Sub(o.keysFetchedAt) < OIDC_KEYS_REFRESH_GAP {
+		return nil, fmt.Errorf("unknown id_token key %q", kid)
+	}
+
+	set := model.JSONWebKeySet{}
+	if err := o.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("cannot read provider keys: %w", err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	o.keys = keys
+	o.keysFetchedAt = o.now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown id_token key %q", kid)
+}
+
+func (o *oidcProviderImpl) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, res.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(out)
+}
+
+func rsaPublicKey(jwk model.JSONWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31 {
+		return nil, errors.New("invalid rsa exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
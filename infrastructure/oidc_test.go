@@ -0,0 +1,100 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"mygram/config"
+	"mygram/infrastructure/oidctest"
+	"mygram/pkg/helper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCProvider(t *testing.T) {
+	server := oidctest.NewServer("mygram")
+	defer server.Close()
+	server.Claims = map[string]any{"sub": "123", "email": "user@mail.com", "email_verified": "true"}
+
+	newProvider := func(clientID string) OIDCProvider {
+		return NewOIDCProvider(config.OIDCProviderConfig{
+			Name:        "stub",
+			Issuer:      server.URL,
+			ClientID:    clientID,
+			RedirectURL: "http://localhost:3000/auth/oidc/stub/callback",
+		}, server.Client())
+	}
+
+	// authorize follows the login url and returns the code of the redirect
+	authorize := func(t *testing.T, provider OIDCProvider, verifier string) string {
+		authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", helper.PKCEChallenge(verifier))
+		assert.Nil(t, err)
+
+		client := server.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		res, err := client.Get(authURL)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		assert.Nil(t, err)
+		assert.Equal(t, "state", location.Query().Get("state"))
+		return location.Query().Get("code")
+	}
+
+	t.Run("success exchange", func(t *testing.T) {
+		provider := newProvider("mygram")
+		code := authorize(t, provider, "verifier")
+
+		claims, err := provider.Exchange(context.Background(), code, "verifier")
+		assert.Nil(t, err)
+		assert.Equal(t, "123", claims.Subject)
+		assert.Equal(t, "user@mail.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+		assert.Equal(t, "nonce", claims.Nonce)
+	})
+
+	t.Run("error wrong code verifier", func(t *testing.T) {
+		provider := newProvider("mygram")
+		code := authorize(t, provider, "verifier")
+
+		_, err := provider.Exchange(context.Background(), code, "another-verifier")
+		assert.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("error id token for another client", func(t *testing.T) {
+		provider := newProvider("mygram")
+		server.ClientID = "other"
+		defer func() { server.ClientID = "mygram" }()
+
+		token, err := server.IDToken("nonce")
+		assert.Nil(t, err)
+		_, err = provider.(*oidcProviderImpl).verifyIDToken(context.Background(), token)
+		assert.ErrorContains(t, err, "not meant for this client")
+	})
+
+	t.Run("error id token signed by another key", func(t *testing.T) {
+		provider := newProvider("mygram")
+		key := server.Key
+		defer func() { server.Key = key }()
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.Nil(t, err)
+
+		// load the real keys first
+		_, err = provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+		assert.Nil(t, err)
+		token, err := server.IDToken("nonce")
+		assert.Nil(t, err)
+		_, err = provider.(*oidcProviderImpl).verifyIDToken(context.Background(), token)
+		assert.Nil(t, err)
+
+		server.Key = other
+		token, err = server.IDToken("nonce")
+		assert.Nil(t, err)
+		_, err = provider.(*oidcProviderImpl).verifyIDToken(context.Background(), token)
+		assert.ErrorContains(t, err, "invalid id_token")
+	})
+}
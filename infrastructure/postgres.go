@@ -1,7 +1,7 @@
 package infrastructure
 
 import (
-	"fmt"
+	"mygram/config"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -15,25 +15,26 @@ type gormPostgresImpl struct {
 	master *gorm.DB
 }
 
-func NewGormPostgres() GormPostgres {
+func NewGormPostgres(cfg config.DatabaseConfig) GormPostgres {
 	return &gormPostgresImpl{
-		master: connect(),
+		master: connect(cfg),
 	}
 }
 
-func connect() *gorm.DB {
-	host := "localhost"
-	port := "5432"
-	user := "postgres"
-	password := "KAK14semangat"
-	dbname := "mygram"
-
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbname)
-	db, err := gorm.Open(postgres.Open(connectionString), &gorm.Config{})
+func connect(cfg config.DatabaseConfig) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
 
+	// connection pool
+	sqlDB, err := db.DB()
 	if err != nil {
 		panic(err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return db
 }
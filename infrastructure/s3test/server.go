@@ -0,0 +1,195 @@
+// Package s3test runs a minimal S3-compatible object store for tests, in
+// place of a MinIO. It serves path-style requests on a single bucket and
+// checks their Signature Version 4, sent as Authorization header or as a
+// presigned URL. Payload hashes are taken as sent.
+package s3test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	REGION            = "us-east-1"
+	ACCESS_KEY_ID     = "s3test-access-key"
+	SECRET_ACCESS_KEY = "s3test-secret-key"
+
+	timeFormat = "20060102T150405Z"
+)
+
+type Server struct {
+	*httptest.Server
+	Bucket string
+
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+type Object struct {
+	ContentType string
+	Data        []byte
+}
+
+// NewServer starts a store holding bucket, close it when done.
+func NewServer(bucket string) *Server {
+	s := &Server{Bucket: bucket, objects: map[string]Object{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Object returns what is stored under key.
+func (s *Server) Object(key string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	return object, ok
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.Bucket+"/")
+	if !ok || key == "" {
+		writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if err := verify(r); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(data)) != r.ContentLength {
+			writeError(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		s.mu.Lock()
+		s.objects[key] = Object{ContentType: r.Header.Get("Content-Type"), Data: data}
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := s.Object(key)
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.Data)))
+		w.Write(object.Data)
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func verify(r *http.Request) error {
+	query := r.URL.Query()
+	var credential, signedHeaders, signature, amzDate, payloadHash string
+	if query.Has("X-Amz-Signature") {
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		amzDate = query.Get("X-Amz-Date")
+		payloadHash = "UNSIGNED-PAYLOAD"
+		query.Del("X-Amz-Signature")
+
+		t, err := time.Parse(timeFormat, amzDate)
+		if err != nil {
+			return err
+		}
+		expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || time.Now().After(t.Add(time.Duration(expires)*time.Second)) {
+			return errors.New("request has expired")
+		}
+	} else {
+		fields, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+		if !ok {
+			return errors.New("missing authorization")
+		}
+		for _, field := range strings.Split(fields, ", ") {
+			name, value, _ := strings.Cut(field, "=")
+			switch name {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signedHeaders = value
+			case "Signature":
+				signature = value
+			}
+		}
+		amzDate = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+
+		t, err := time.Parse(timeFormat, amzDate)
+		if err != nil {
+			return err
+		}
+		if skew := time.Since(t); skew > 15*time.Minute || skew < -15*time.Minute {
+			return errors.New("request time too skewed")
+		}
+	}
+
+	scope := amzDate[:8] + "/" + REGION + "/s3/aws4_request"
+	if credential != ACCESS_KEY_ID+"/"+scope {
+		return fmt.Errorf("unknown credential %q", credential)
+	}
+
+	headers := ""
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Host
+		if name != "host" {
+			value = strings.TrimSpace(strings.Join(r.Header.Values(name), ","))
+		}
+		headers += name + ":" + value + "\n"
+	}
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), canonicalQuery(query), headers, signedHeaders, payloadHash}, "\n")
+	hash := sha256.Sum256([]byte(canonical))
+
+	key := []byte("AWS4" + SECRET_ACCESS_KEY)
+	for _, part := range []string{amzDate[:8], REGION, "s3", "aws4_request", "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	if !hmac.Equal([]byte(hex.EncodeToString(key)), []byte(signature)) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+func canonicalQuery(query url.Values) string {
+	pairs := []string{}
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code></Error>", code)
+}
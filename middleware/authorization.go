@@ -1,77 +1,208 @@
 package middleware
 
 import (
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"mygram/config"
+	"mygram/model"
 	"mygram/pkg"
 	"mygram/pkg/helper"
+	"mygram/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	STATIC_USERNAME = "golang006awesome"
-	STATIC_PASSWORD = "mysecretpassword"
-
-	CLAIM_USER_ID  = "claim_user_id"
-	CLAIM_USERNAME = "claim_username"
+	CLAIM_PRINCIPAL = "claim_principal"
 )
 
-func CheckAuthBasic(ctx *gin.Context) {
-	auth := ctx.GetHeader("Authorization")
+type Authorization interface {
+	// CheckAuthBasic only accepts Basic credentials, failures are answered
+	// with a WWW-Authenticate challenge.
+	CheckAuthBasic(ctx *gin.Context)
+	// WithBasic returns a copy whose CheckAuth also accepts Basic
+	// credentials, for the route groups Basic auth is enabled on.
+	WithBasic() Authorization
+	// CheckAuthBearer only accepts user access tokens issued by this service.
+	CheckAuthBearer(ctx *gin.Context)
+	// CheckAuth accepts an access token like CheckAuthBearer or an API key
+	// given as "Authorization: ApiKey <key>", use RequireScope on its routes.
+	CheckAuth(ctx *gin.Context)
+	// Bearer builds a bearer check for route groups expecting other tokens.
+	Bearer(expect helper.TokenExpectation) gin.HandlerFunc
+}
 
-	authArr := strings.Split(auth, " ")
-	if len(authArr) < 2 {
+type authorizationImpl struct {
+	jwt     config.JWTConfig
+	keys    *helper.KeySet
+	session service.SessionService
+	apiKeys service.APIKeyService
+	// basic is nil when Basic auth is not configured
+	basic       service.BasicAuthService
+	realm       string
+	acceptBasic bool
+	access      helper.TokenExpectation
+}
+
+func NewAuthorization(jwt config.JWTConfig, keys *helper.KeySet, session service.SessionService, apiKeys service.APIKeyService, basic service.BasicAuthService, realm string) Authorization {
+	return &authorizationImpl{
+		jwt:     jwt,
+		keys:    keys,
+		session: session,
+		apiKeys: apiKeys,
+		basic:   basic,
+		realm:   realm,
+		access: helper.TokenExpectation{
+			Issuer:   jwt.Issuer,
+			Audience: jwt.Audience,
+			Subject:  model.SUBJECT_ACCESS_TOKEN,
+			Leeway:   jwt.Leeway,
+		},
+	}
+}
+
+func (a *authorizationImpl) CheckAuthBasic(ctx *gin.Context) {
+	a.checkBasic(ctx)
+}
+
+func (a *authorizationImpl) WithBasic() Authorization {
+	withBasic := *a
+	withBasic.acceptBasic = true
+	return &withBasic
+}
+
+func (a *authorizationImpl) checkBasic(ctx *gin.Context) {
+	challenge := func(code string, errs ...string) {
+		ctx.Header("WWW-Authenticate", a.basicChallenge())
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Code:    code,
 			Message: "unauthorized",
-			Errors:  []string{"invalid token"},
+			Errors:  errs,
 		})
+	}
+
+	email, password, ok := ctx.Request.BasicAuth()
+	if !ok {
+		challenge(pkg.ERR_CODE_TOKEN_MISSING, "missing basic credentials")
 		return
 	}
-	if authArr[0] != "Basic" {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
-			Message: "unauthorized",
-			Errors:  []string{"invalid authorization method"},
-		})
+	if a.basic == nil {
+		challenge(pkg.ERR_CODE_INVALID_AUTH_METHOD, "basic auth is not enabled")
 		return
 	}
 
-	token := authArr[1]
-	basic, err := base64.StdEncoding.DecodeString(token)
+	principal, err := a.basic.Authenticate(ctx, email, password, ctx.ClientIP())
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
-			Message: "unauthorized",
-			Errors:  []string{"invalid token", "failed to decode"},
-		})
+		var locked *service.LoginLockedError
+		switch {
+		case errors.As(err, &locked):
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, pkg.ErrorResponse{
+				Code:    pkg.ERR_CODE_LOGIN_LOCKED,
+				Message: "too many requests",
+				Errors:  []string{err.Error()},
+			})
+		case errors.Is(err, service.ErrInvalidCredentials):
+			challenge(pkg.ERR_CODE_INVALID_CREDENTIALS, err.Error())
+		case errors.Is(err, service.ErrBasicAuthTwoFactor):
+			// no challenge, other credentials would not help
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+				Code:    pkg.ERR_CODE_INVALID_AUTH_METHOD,
+				Message: "unauthorized",
+				Errors:  []string{err.Error()},
+			})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+				Message: "internal server error",
+				Errors:  []string{err.Error()},
+			})
+		}
 		return
 	}
 
-	if string(basic) != fmt.Sprintf("%v:%v", STATIC_USERNAME, STATIC_PASSWORD) {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+	ctx.Set(CLAIM_PRINCIPAL, principal)
+	ctx.Next()
+}
+
+func (a *authorizationImpl) basicChallenge() string {
+	return fmt.Sprintf(`Basic realm="%s", charset="UTF-8"`, a.realm)
+}
+
+// abortUnauthorized answers 401, on groups with Basic auth it also tells
+// clients they may send credentials.
+func (a *authorizationImpl) abortUnauthorized(ctx *gin.Context, body pkg.ErrorResponse) {
+	if a.acceptBasic {
+		ctx.Header("WWW-Authenticate", a.basicChallenge())
+	}
+	ctx.AbortWithStatusJSON(http.StatusUnauthorized, body)
+}
+
+func (a *authorizationImpl) CheckAuthBearer(ctx *gin.Context) {
+	a.checkBearer(ctx, a.access)
+}
+
+func (a *authorizationImpl) CheckAuth(ctx *gin.Context) {
+	scheme, key, _ := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if a.acceptBasic && strings.EqualFold(scheme, "Basic") {
+		a.checkBasic(ctx)
+		return
+	}
+	if scheme != "ApiKey" {
+		a.checkBearer(ctx, a.access)
+		return
+	}
+
+	principal, err := a.apiKeys.Authenticate(ctx, key)
+	if errors.Is(err, service.ErrInvalidAPIKey) {
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    pkg.ERR_CODE_INVALID_API_KEY,
 			Message: "unauthorized",
-			Errors:  []string{"invalid username or password"},
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+			Message: "internal server error",
+			Errors:  []string{err.Error()},
 		})
 		return
 	}
+
+	ctx.Set(CLAIM_PRINCIPAL, principal)
 	ctx.Next()
 }
 
-func CheckAuthBearer(ctx *gin.Context) {
+func (a *authorizationImpl) Bearer(expect helper.TokenExpectation) gin.HandlerFunc {
+	if expect.Leeway == 0 {
+		expect.Leeway = a.jwt.Leeway
+	}
+	return func(ctx *gin.Context) {
+		a.checkBearer(ctx, expect)
+	}
+}
+
+func (a *authorizationImpl) checkBearer(ctx *gin.Context, expect helper.TokenExpectation) {
 	auth := ctx.GetHeader("Authorization")
 
 	authArr := strings.Split(auth, " ")
 	if len(authArr) < 2 {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    pkg.ERR_CODE_TOKEN_MISSING,
 			Message: "unauthorized",
 			Errors:  []string{"invalid token"},
 		})
 		return
 	}
 	if authArr[0] != "Bearer" {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    pkg.ERR_CODE_INVALID_AUTH_METHOD,
 			Message: "unauthorized",
 			Errors:  []string{"invalid authorization method"},
 		})
@@ -79,15 +210,119 @@ func CheckAuthBearer(ctx *gin.Context) {
 	}
 
 	token := authArr[1]
-	claims, err := helper.ValidateToken(token)
+	claims, err := helper.ValidateToken(token, a.keys, expect)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+		code := helper.ErrTokenMalformed.Code
+		var tokenErr *helper.TokenError
+		if errors.As(err, &tokenErr) {
+			code = tokenErr.Code
+		}
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    code,
+			Message: "unauthorized",
+			Errors:  []string{"invalid token", err.Error()},
+		})
+		return
+	}
+
+	claim := model.AccessClaim{}
+	if err := helper.DecodeClaim(claims, &claim); err != nil || (expect.Subject == model.SUBJECT_ACCESS_TOKEN && claim.UserID == 0) {
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    helper.ErrTokenMalformed.Code,
+			Message: "unauthorized",
+			Errors:  []string{"invalid token", "missing user claims"},
+		})
+		return
+	}
+
+	// reject tokens revoked by logout or by logging out all sessions
+	issuedAt := time.Unix(int64(claim.Iat), 0)
+	revoked, err := a.session.IsRevoked(ctx, claim.Jti, claim.UserID, issuedAt)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+			Message: "internal server error",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if revoked {
+		a.abortUnauthorized(ctx, pkg.ErrorResponse{
+			Code:    pkg.ERR_CODE_TOKEN_REVOKED,
 			Message: "unauthorized",
-			Errors:  []string{"invalid token", "failed to decode"},
+			Errors:  []string{"token has been revoked"},
 		})
 		return
 	}
-	ctx.Set(CLAIM_USER_ID, claims["user_id"])
-	ctx.Set(CLAIM_USERNAME, claims["username"])
+
+	ctx.Set(CLAIM_PRINCIPAL, model.Principal{
+		UserID:      claim.UserID,
+		Username:    claim.Username,
+		Scopes:      claim.Scopes,
+		Role:        claim.Role,
+		Permissions: claim.Permissions,
+		TokenID:     claim.Jti,
+		IssuedAt:    issuedAt,
+		ExpiresAt:   time.Unix(int64(claim.Exp), 0),
+	})
 	ctx.Next()
 }
+
+// GetPrincipal returns the caller set by the auth middleware. When there is
+// none the request is aborted with 401 and ok is false.
+func GetPrincipal(ctx *gin.Context) (principal model.Principal, ok bool) {
+	value, exists := ctx.Get(CLAIM_PRINCIPAL)
+	principal, ok = value.(model.Principal)
+	if !exists || !ok || principal.UserID == 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Code:    pkg.ERR_CODE_TOKEN_MISSING,
+			Message: "unauthorized",
+			Errors:  []string{"invalid user session"},
+		})
+		return model.Principal{}, false
+	}
+	return principal, true
+}
+
+// RequirePermission only lets the request through when the principal holds
+// every given permission. It must run after a bearer check.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := GetPrincipal(ctx)
+		if !ok {
+			return
+		}
+		for _, permission := range permissions {
+			if !principal.HasPermission(permission) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+					Code:    pkg.ERR_CODE_PERMISSION_DENIED,
+					Message: "forbidden",
+					Errors:  []string{fmt.Sprintf("missing permission %s", permission)},
+				})
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RequireScope only lets API keys through when they were given every scope,
+// access tokens always pass. It must run after CheckAuth.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := GetPrincipal(ctx)
+		if !ok {
+			return
+		}
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+					Code:    pkg.ERR_CODE_INSUFFICIENT_SCOPE,
+					Message: "forbidden",
+					Errors:  []string{fmt.Sprintf("missing scope %s", scope)},
+				})
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
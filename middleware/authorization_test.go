@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/service"
+	"mygram/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckAuthBearer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtCfg := config.JWTConfig{Issuer: "project", Audience: "mygram", Leeway: time.Second}
+	keys := helper.NewHMACKeySet("a-very-long-secret-used-for-testing-only")
+	now := time.Now()
+
+	newToken := func(sub string, userID uint64) string {
+		token, err := helper.GenerateToken(model.AccessClaim{
+			StandardClaim: model.StandardClaim{
+				Jti: "jti",
+				Iss: "project",
+				Aud: "mygram",
+				Sub: sub,
+				Exp: uint64(now.Add(time.Hour).Unix()),
+				Iat: uint64(now.Unix()),
+				Nbf: uint64(now.Unix()),
+			},
+			UserID:   userID,
+			Username: "username",
+		}, keys)
+		assert.Nil(t, err)
+		return token
+	}
+
+	t.Run("error public token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		g, _ := gin.CreateTestContext(rec)
+		g.Request = httptest.NewRequest(http.MethodGet, "/photos", nil)
+		g.Request.Header.Set("Authorization", "Bearer "+newToken(model.SUBJECT_PUBLIC_TOKEN, 0))
+
+		auth := NewAuthorization(jwtCfg, keys, mocks.NewSessionService(t), nil, nil, "")
+		auth.CheckAuthBearer(g)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+		assert.Contains(t, rec.Body.String(), helper.ErrTokenWrongType.Code)
+	})
+
+	t.Run("success keeps user id above 2^53", func(t *testing.T) {
+		userID := uint64(1<<53 + 1)
+		rec := httptest.NewRecorder()
+		g, _ := gin.CreateTestContext(rec)
+		g.Request = httptest.NewRequest(http.MethodGet, "/photos", nil)
+		g.Request.Header.Set("Authorization", "Bearer "+newToken(model.SUBJECT_ACCESS_TOKEN, userID))
+
+		sessionMock := mocks.NewSessionService(t)
+		sessionMock.On("IsRevoked", mock.Anything, "jti", userID, time.Unix(now.Unix(), 0)).Return(false, nil)
+
+		auth := NewAuthorization(jwtCfg, keys, sessionMock, nil, nil, "")
+		auth.CheckAuthBearer(g)
+
+		principal, ok := GetPrincipal(g)
+		assert.True(t, ok)
+		assert.Equal(t, userID, principal.UserID)
+		assert.Equal(t, "username", principal.Username)
+		assert.Equal(t, "jti", principal.TokenID)
+	})
+
+	t.Run("error missing principal", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		g, _ := gin.CreateTestContext(rec)
+		g.Request = httptest.NewRequest(http.MethodGet, "/photos", nil).WithContext(context.Background())
+
+		_, ok := GetPrincipal(g)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+	})
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc      string
+		principal model.Principal
+		status    int
+	}{
+		{desc: "success admin", principal: model.Principal{UserID: 1, Role: model.ROLE_ADMIN, Permissions: model.RolePermissions[model.ROLE_ADMIN]}, status: http.StatusOK},
+		{desc: "error user", principal: model.Principal{UserID: 1, Role: model.ROLE_USER}, status: http.StatusForbidden},
+		{desc: "error no principal", status: http.StatusUnauthorized},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			_, g := gin.CreateTestContext(rec)
+			g.GET("/users", func(ctx *gin.Context) {
+				if tC.principal.UserID != 0 {
+					ctx.Set(CLAIM_PRINCIPAL, tC.principal)
+				}
+			}, RequirePermission(model.PERMISSION_USERS_READ), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+			assert.Equal(t, tC.status, rec.Code)
+		})
+	}
+}
+
+func TestCheckAuthAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc      string
+		principal model.Principal
+		err       error
+		status    int
+	}{
+		{desc: "success key with scope", principal: model.Principal{UserID: 1, APIKeyID: 2, Scopes: []string{model.SCOPE_PHOTOS_READ}}, status: http.StatusOK},
+		{desc: "error key without scope", principal: model.Principal{UserID: 1, APIKeyID: 2, Scopes: []string{model.SCOPE_COMMENTS_READ}}, status: http.StatusForbidden},
+		{desc: "error invalid key", err: service.ErrInvalidAPIKey, status: http.StatusUnauthorized},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			apiKeyMock := mocks.NewAPIKeyService(t)
+			apiKeyMock.On("Authenticate", mock.Anything, "mgk_key").Return(tC.principal, tC.err)
+
+			rec := httptest.NewRecorder()
+			_, g := gin.CreateTestContext(rec)
+			auth := NewAuthorization(config.JWTConfig{}, nil, nil, apiKeyMock, nil, "")
+			g.GET("/photos", auth.CheckAuth, RequireScope(model.SCOPE_PHOTOS_READ), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/photos", nil)
+			req.Header.Set("Authorization", "ApiKey mgk_key")
+			g.ServeHTTP(rec, req)
+			assert.Equal(t, tC.status, rec.Code)
+		})
+	}
+}
+
+func TestCheckAuthBasic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc      string
+		basic     bool
+		header    string
+		principal model.Principal
+		err       error
+		status    int
+		challenge bool
+	}{
+		{desc: "success basic on enabled group", basic: true, header: "Basic dXNlckBtYWlsLmNvbTpwYXNzd29yZA==", principal: model.Principal{UserID: 1}, status: http.StatusOK},
+		{desc: "success lowercase scheme", basic: true, header: "basic dXNlckBtYWlsLmNvbTpwYXNzd29yZA==", principal: model.Principal{UserID: 1}, status: http.StatusOK},
+		{desc: "error wrong password", basic: true, header: "Basic dXNlckBtYWlsLmNvbTpwYXNzd29yZA==", err: service.ErrInvalidCredentials, status: http.StatusUnauthorized, challenge: true},
+		{desc: "error missing credentials", basic: true, status: http.StatusUnauthorized, challenge: true},
+		{desc: "error basic on other group", header: "Basic dXNlckBtYWlsLmNvbTpwYXNzd29yZA==", status: http.StatusUnauthorized},
+		{desc: "error locked", basic: true, header: "Basic dXNlckBtYWlsLmNvbTpwYXNzd29yZA==", err: &service.LoginLockedError{RetryAfter: time.Minute}, status: http.StatusTooManyRequests},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			basicMock := mocks.NewBasicAuthService(t)
+			if tC.principal.UserID != 0 || tC.err != nil {
+				basicMock.On("Authenticate", mock.Anything, "user@mail.com", "password", mock.Anything).Return(tC.principal, tC.err)
+			}
+
+			rec := httptest.NewRecorder()
+			_, g := gin.CreateTestContext(rec)
+			auth := NewAuthorization(config.JWTConfig{}, nil, nil, nil, basicMock, "mygram")
+			if tC.basic {
+				auth = auth.WithBasic()
+			}
+			g.GET("/photos", auth.CheckAuth, RequireScope(model.SCOPE_PHOTOS_READ), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/photos", nil)
+			if tC.header != "" {
+				req.Header.Set("Authorization", tC.header)
+			}
+			g.ServeHTTP(rec, req)
+			assert.Equal(t, tC.status, rec.Code)
+			if tC.challenge {
+				assert.Equal(t, `Basic realm="mygram", charset="UTF-8"`, rec.Header().Get("WWW-Authenticate"))
+			} else {
+				assert.Empty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
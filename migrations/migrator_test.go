@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("error missing down script", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000001_init.up.sql": {Data: []byte("CREATE TABLE a();")},
+		}
+		_, err := Load(fsys)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("error invalid file name", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"init.sql": {Data: []byte("CREATE TABLE a();")},
+		}
+		_, err := Load(fsys)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("success ordered by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000002_second.up.sql":   {Data: []byte("up 2")},
+			"000002_second.down.sql": {Data: []byte("down 2")},
+			"000001_first.up.sql":    {Data: []byte("up 1")},
+			"000001_first.down.sql":  {Data: []byte("down 1")},
+		}
+		res, err := Load(fsys)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, int64(1), res[0].Version)
+		assert.Equal(t, "second", res[1].Name)
+		assert.Equal(t, "down 2", res[1].Down)
+	})
+
+	t.Run("success embedded migrations", func(t *testing.T) {
+		res, err := Load(FS)
+		assert.Nil(t, err)
+		assert.NotEqual(t, 0, len(res))
+	})
+}
+
+func TestUp(t *testing.T) {
+	t.Run("success apply pending migrations only", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+
+		migrator := migratorImpl{db: db, migrations: []Migration{
+			{Version: 1, Name: "first", Up: "CREATE TABLE a()", Down: "DROP TABLE a"},
+			{Version: 2, Name: "second", Up: "CREATE TABLE b()", Down: "DROP TABLE b"},
+		}}
+
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b()")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations")).WithArgs(int64(2), "second").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		res, err := migrator.Up(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, int64(2), res[0].Version)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -50,4 +50,4 @@ CREATE TABLE social_medias(
     constraint fk_social_medias_user_id 
         foreign key (user_id) 
         references users(id)
-);
\ No newline at end of file
+);
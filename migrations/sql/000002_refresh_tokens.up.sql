@@ -0,0 +1,16 @@
+CREATE TABLE refresh_tokens(
+    id serial primary key not null,
+    user_id int not null,
+    family_id varchar(64) not null,
+    token_hash varchar(64) not null unique,
+    expires_at timestamp not null,
+    used_at timestamp,
+    revoked_at timestamp,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    constraint fk_refresh_tokens_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
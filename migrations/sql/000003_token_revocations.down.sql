@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id;
+DROP TABLE IF EXISTS user_token_watermarks;
+DROP TABLE IF EXISTS revoked_tokens;
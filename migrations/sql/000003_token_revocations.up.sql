@@ -0,0 +1,22 @@
+CREATE TABLE revoked_tokens(
+    jti varchar(64) primary key not null,
+    user_id int not null,
+    expires_at timestamp not null,
+    created_at timestamp not null default now(),
+    constraint fk_revoked_tokens_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+CREATE TABLE user_token_watermarks(
+    user_id int primary key not null,
+    revoked_before timestamp not null,
+    updated_at timestamp not null default now(),
+    constraint fk_user_token_watermarks_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN permissions,
+    DROP COLUMN role;
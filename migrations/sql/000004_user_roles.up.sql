@@ -0,0 +1,6 @@
+-- role decides the permissions of a user, permissions holds extra grants
+-- on top of the role. promote the first admin with:
+--   UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users
+    ADD COLUMN role varchar(32) not null default 'user',
+    ADD COLUMN permissions text not null default '';
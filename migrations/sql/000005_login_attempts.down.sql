@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS login_throttles;
+DROP INDEX IF EXISTS idx_login_attempts_ip;
+DROP INDEX IF EXISTS idx_login_attempts_email;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,20 @@
+-- audit trail of failed logins, user_id is null for unknown emails
+CREATE TABLE login_attempts(
+    id serial primary key not null,
+    email varchar(255) not null,
+    user_id int,
+    ip varchar(64) not null,
+    reason varchar(32) not null,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX idx_login_attempts_email ON login_attempts(email);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip);
+
+-- throttle state, key is "account:<email>" or "ip:<address>"
+CREATE TABLE login_throttles(
+    key varchar(320) primary key not null,
+    failures int not null default 0,
+    last_failure_at timestamp not null,
+    locked_until timestamp
+);
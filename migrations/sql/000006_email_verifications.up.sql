@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN verified_at timestamp;
+
+CREATE TABLE email_verifications(
+    jti varchar(64) primary key not null,
+    user_id int not null,
+    expires_at timestamp not null,
+    used_at timestamp,
+    created_at timestamp not null default now(),
+    constraint fk_email_verifications_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_email_verifications_user_id ON email_verifications(user_id);
@@ -0,0 +1,14 @@
+-- only the sha256 of the mailed token is stored
+CREATE TABLE password_resets(
+    id serial primary key not null,
+    user_id int not null,
+    token_hash varchar(64) not null unique,
+    expires_at timestamp not null,
+    used_at timestamp,
+    created_at timestamp not null default now(),
+    constraint fk_password_resets_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_password_resets_user_id ON password_resets(user_id);
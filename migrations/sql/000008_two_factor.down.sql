@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_recovery_codes_user_id;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totps;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled_at;
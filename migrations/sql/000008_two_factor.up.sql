@@ -0,0 +1,27 @@
+ALTER TABLE users ADD COLUMN two_factor_enabled_at timestamp;
+
+-- the secret of an enrollment, two-factor is only on once the first code
+-- confirmed it. last_used_step stops a code from being replayed
+CREATE TABLE user_totps(
+    user_id int primary key not null,
+    secret varchar(64) not null,
+    last_used_step bigint not null default 0,
+    created_at timestamp not null default now(),
+    constraint fk_user_totps_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+-- only the sha256 of each recovery code is stored
+CREATE TABLE recovery_codes(
+    id serial primary key not null,
+    user_id int not null,
+    code_hash varchar(64) not null,
+    used_at timestamp,
+    created_at timestamp not null default now(),
+    constraint fk_recovery_codes_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes(user_id);
@@ -0,0 +1,19 @@
+-- the prefix is shown to users and used for lookup, only the sha256 of the
+-- whole key is stored
+CREATE TABLE api_keys(
+    id serial primary key not null,
+    user_id int not null,
+    name varchar(100) not null,
+    prefix varchar(16) not null unique,
+    key_hash varchar(64) not null,
+    scopes text not null default '',
+    last_used_at timestamp,
+    expires_at timestamp,
+    revoked_at timestamp,
+    created_at timestamp not null default now(),
+    constraint fk_api_keys_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
@@ -0,0 +1,25 @@
+-- pending logins, the state is single use and only its sha256 is stored
+CREATE TABLE oidc_states(
+    state_hash varchar(64) primary key not null,
+    provider varchar(64) not null,
+    code_verifier varchar(128) not null,
+    nonce varchar(128) not null,
+    expires_at timestamp not null,
+    created_at timestamp not null default now()
+);
+
+CREATE TABLE user_identities(
+    id serial primary key not null,
+    user_id int not null,
+    provider varchar(64) not null,
+    subject varchar(255) not null,
+    email varchar(255) not null default '',
+    created_at timestamp not null default now(),
+    constraint uq_user_identities_provider_subject
+        unique (provider, subject),
+    constraint fk_user_identities_user_id
+        foreign key (user_id)
+        references users(id)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_follows_followee_id;
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,19 @@
+-- one row per edge, the primary key rules out duplicates and the check
+-- rules out following yourself
+CREATE TABLE follows(
+    follower_id int not null,
+    followee_id int not null,
+    created_at timestamp not null default now(),
+    primary key (follower_id, followee_id),
+    constraint chk_follows_not_self
+        check (follower_id <> followee_id),
+    constraint fk_follows_follower_id
+        foreign key (follower_id)
+        references users(id),
+    constraint fk_follows_followee_id
+        foreign key (followee_id)
+        references users(id)
+);
+
+-- the primary key serves "following", this one serves "followers"
+CREATE INDEX idx_follows_followee_id ON follows(followee_id, created_at);
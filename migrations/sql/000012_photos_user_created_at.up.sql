@@ -0,0 +1,3 @@
+-- serves the feed, one backward scan per followed user from the cursor on,
+-- and the photo list of profiles
+CREATE INDEX idx_photos_user_id_created_at ON photos(user_id, created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photo_likes_photo_id;
+DROP TABLE IF EXISTS photo_likes;
@@ -0,0 +1,18 @@
+-- one row per like, the unique constraint makes liking twice a no-op
+CREATE TABLE photo_likes(
+    user_id int not null,
+    photo_id int not null,
+    created_at timestamp not null default now(),
+    constraint uq_photo_likes_user_id_photo_id
+        unique (user_id, photo_id),
+    constraint fk_photo_likes_user_id
+        foreign key (user_id)
+        references users(id),
+    constraint fk_photo_likes_photo_id
+        foreign key (photo_id)
+        references photos(id)
+);
+
+-- the unique constraint serves "liked by me", this one serves the counts
+-- and the likers of a photo
+CREATE INDEX idx_photo_likes_photo_id ON photo_likes(photo_id, created_at);
@@ -0,0 +1,3 @@
+-- key of the uploaded image in the blob store, empty for photos that point
+-- to an external url
+ALTER TABLE photos ADD COLUMN storage_key varchar(255) not null default '';
@@ -0,0 +1,2 @@
+-- resized copies of the photo, filled in by the variant workers
+ALTER TABLE photos ADD COLUMN variants jsonb not null default '[]';
@@ -0,0 +1,7 @@
+ALTER TABLE photos
+    DROP COLUMN IF EXISTS taken_at,
+    DROP COLUMN IF EXISTS camera_make,
+    DROP COLUMN IF EXISTS camera_model,
+    DROP COLUMN IF EXISTS orientation,
+    DROP COLUMN IF EXISTS width,
+    DROP COLUMN IF EXISTS height;
@@ -0,0 +1,8 @@
+-- read from the exif of uploaded images, width and height are as displayed
+ALTER TABLE photos
+    ADD COLUMN taken_at timestamp,
+    ADD COLUMN camera_make varchar(255) not null default '',
+    ADD COLUMN camera_model varchar(255) not null default '',
+    ADD COLUMN orientation smallint not null default 0,
+    ADD COLUMN width integer not null default 0,
+    ADD COLUMN height integer not null default 0;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_comments_photo_id_created_at;
@@ -0,0 +1,2 @@
+-- serves the comment pages of the photo detail, oldest first from the cursor
+CREATE INDEX idx_comments_photo_id_created_at ON comments(photo_id, created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- the url the variants were made from, the catch-up pass makes them again
+-- for photos where it is not the url anymore. null for the photos made
+-- before, they all get their variants made once
+ALTER TABLE photos ADD COLUMN variants_url text;
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// API_KEY_PREFIX starts every key so leaked ones are easy to grep for.
+const API_KEY_PREFIX = "mgk_"
+
+const (
+	SCOPE_PHOTOS_READ         = "photos:read"
+	SCOPE_PHOTOS_WRITE        = "photos:write"
+	SCOPE_COMMENTS_READ       = "comments:read"
+	SCOPE_COMMENTS_WRITE      = "comments:write"
+	SCOPE_SOCIAL_MEDIAS_READ  = "social_medias:read"
+	SCOPE_SOCIAL_MEDIAS_WRITE = "social_medias:write"
+)
+
+// APIKeyScopes lists every scope an API key can be given.
+var APIKeyScopes = []string{
+	SCOPE_PHOTOS_READ,
+	SCOPE_PHOTOS_WRITE,
+	SCOPE_COMMENTS_READ,
+	SCOPE_COMMENTS_WRITE,
+	SCOPE_SOCIAL_MEDIAS_READ,
+	SCOPE_SOCIAL_MEDIAS_WRITE,
+}
+
+type APIKey struct {
+	ID         uint64      `json:"id"`
+	UserID     uint64      `json:"user_id"`
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix"`
+	KeyHash    string      `json:"-"`
+	Scopes     Permissions `json:"scopes" gorm:"column:scopes"`
+	LastUsedAt *time.Time  `json:"last_used_at"`
+	ExpiresAt  *time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time  `json:"-"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (c CreateAPIKeyRequest) Validate() error {
+	if len(c.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if len(c.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range c.Scopes {
+		known := false
+		for _, s := range APIKeyScopes {
+			known = known || s == scope
+		}
+		if !known {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// CreatedAPIKey is returned once on creation, Key can not be shown again.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
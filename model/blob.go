@@ -0,0 +1,12 @@
+package model
+
+import "io"
+
+// Blob is where a stored object can be read from, either RedirectURL or
+// Content is set. Close Content when done.
+type Blob struct {
+	RedirectURL string
+	Content     io.ReadCloser
+	ContentType string
+	Size        int64
+}
@@ -1,18 +1,24 @@
 package model
 
 import (
+	"errors"
 	"time"
+
+	"gorm.io/gorm"
 )
 
+var ErrInvalidCommentCursor = errors.New("invalid comment cursor")
+
 type Comments struct {
-	ID        int       `json:"id" gorm:"primaryKey"`
-	Message   string    `json:"message" gorm:"notNull"`
-	PhotoID   int       `json:"photo_id" gorm:"notNull"`
-	UserID    int       `json:"user_id" gorm:"notNull"`
-	User      User      `json:"-"`
-	Photo     Photo     `json:"-"`
-	CreatedAt time.Time `json:"create_at"`
-	UpdatedAt time.Time `json:"update_at"`
+	ID        int            `json:"id" gorm:"primaryKey"`
+	Message   string         `json:"message" gorm:"notNull"`
+	PhotoID   int            `json:"photo_id" gorm:"notNull"`
+	UserID    int            `json:"user_id" gorm:"notNull"`
+	User      User           `json:"-"`
+	Photo     Photo          `json:"-"`
+	CreatedAt time.Time      `json:"create_at"`
+	UpdatedAt time.Time      `json:"update_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
 }
 
 type CommentUser struct {
@@ -57,3 +63,50 @@ type CreateComment struct {
 type UpdateComment struct {
 	Message string `json:"message"`
 }
+
+// CommentsRequest is read from the query like FeedRequest.
+type CommentsRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// CommentCursor points at the last comment of a page. Comments are listed
+// oldest first, the next page starts strictly after it in (created_at, id)
+// order.
+type CommentCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+type PhotoComment struct {
+	ID        int       `json:"id"`
+	Message   string    `json:"message"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PhotoComments holds one page, NextCursor is empty on the last one.
+type PhotoComments struct {
+	Comments   []PhotoComment `json:"comments"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func (c CommentsRequest) Normalize() (CommentsRequest, error) {
+	req, err := FeedRequest(c).Normalize()
+	return CommentsRequest(req), err
+}
+
+// Encode returns the same kind of opaque string as FeedCursor.Encode.
+func (c CommentCursor) Encode() string {
+	return FeedCursor(c).Encode()
+}
+
+func DecodeCommentCursor(cursor string) (CommentCursor, error) {
+	decoded, err := DecodeFeedCursor(cursor)
+	if err != nil {
+		return CommentCursor{}, ErrInvalidCommentCursor
+	}
+	return CommentCursor(decoded), nil
+}
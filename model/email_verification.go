@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// EmailVerificationClaim binds the token to the address it was sent to, so
+// it is useless once the user changed their email.
+type EmailVerificationClaim struct {
+	StandardClaim
+	UserID uint64 `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// EmailVerification remembers issued tokens by jti so each is used once.
+type EmailVerification struct {
+	Jti       string     `json:"jti"`
+	UserID    uint64     `json:"user_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
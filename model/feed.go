@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+
+// FeedRequest is read from the query, Cursor is the next_cursor of the
+// previous page and Limit defaults to DEFAULT_PAGE_SIZE.
+type FeedRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// FeedCursor points at the last photo of a page. The next page starts
+// strictly after it in (created_at, id) order, so photos posted meanwhile
+// never shift or repeat later pages.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+type FeedPhoto struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Caption   string    `json:"caption"`
+	URL       string    `json:"url"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Feed holds one page, NextCursor is empty on the last one.
+type Feed struct {
+	Photos     []FeedPhoto `json:"photos"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// Normalize fills in the default limit and rejects out of range values.
+func (f FeedRequest) Normalize() (FeedRequest, error) {
+	if f.Limit == 0 {
+		f.Limit = DEFAULT_PAGE_SIZE
+	}
+	if f.Limit < 1 || f.Limit > MAX_PAGE_SIZE {
+		return FeedRequest{}, fmt.Errorf("invalid limit: must be between 1 and %d", MAX_PAGE_SIZE)
+	}
+	return f, nil
+}
+
+// Encode returns an opaque url-safe string, postgres timestamps only keep
+// microseconds so that is all it carries.
+func (c FeedCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixMicro(), 10) + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeFeedCursor(cursor string) (FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	createdAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	photoID, err := strconv.Atoi(id)
+	if err != nil || photoID < 1 {
+		return FeedCursor{}, ErrInvalidFeedCursor
+	}
+	return FeedCursor{CreatedAt: time.UnixMicro(createdAt).UTC(), ID: photoID}, nil
+}
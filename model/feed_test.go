@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedCursor(t *testing.T) {
+	t.Run("success round trip keeps microseconds", func(t *testing.T) {
+		cursor := FeedCursor{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC), ID: 42}
+
+		decoded, err := DecodeFeedCursor(cursor.Encode())
+		assert.Nil(t, err)
+		assert.Equal(t, FeedCursor{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), ID: 42}, decoded)
+	})
+
+	t.Run("error tampered cursor", func(t *testing.T) {
+		for _, cursor := range []string{"", "not base64!", "MTIz", "YWJjOjQy", "MTIzOjA"} {
+			_, err := DecodeFeedCursor(cursor)
+			assert.ErrorIs(t, err, ErrInvalidFeedCursor, cursor)
+		}
+	})
+}
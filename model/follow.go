@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Follow is an edge of the social graph, FollowerID follows FolloweeID.
+type Follow struct {
+	FollowerID uint64    `json:"follower_id"`
+	FolloweeID uint64    `json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// FollowUser is one entry of a followers or following list.
+type FollowUser struct {
+	ID         uint64    `json:"id"`
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type FollowList struct {
+	Users []FollowUser `json:"users"`
+	Page  PageInfo     `json:"page"`
+}
+
+// FollowCounts leaves deleted users out.
+type FollowCounts struct {
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+}
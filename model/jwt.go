@@ -10,6 +10,17 @@ import "time"
 // iat (issued at time): Time at which the JWT was issued; can be used to determine age of the JWT
 // jti (JWT ID): Unique identifier; can be used to prevent the JWT from being replayed (allows a token to be used only once)
 
+const (
+	SUBJECT_ACCESS_TOKEN = "access-token"
+	SUBJECT_PUBLIC_TOKEN = "public-token"
+	// SUBJECT_EMAIL_VERIFICATION_TOKEN tokens are mailed to prove the user
+	// owns the address, they are never accepted as bearer tokens.
+	SUBJECT_EMAIL_VERIFICATION_TOKEN = "email-verification-token"
+	// SUBJECT_TWO_FACTOR_CHALLENGE_TOKEN tokens only prove the password was
+	// right, they can be exchanged for a session with a second factor.
+	SUBJECT_TWO_FACTOR_CHALLENGE_TOKEN = "two-factor-challenge-token"
+)
+
 type StandardClaim struct {
 	Jti string `json:"jti"`
 	Iss string `json:"iss"`
@@ -25,4 +36,43 @@ type AccessClaim struct {
 	UserID   uint64    `json:"user_id"`
 	Username string    `json:"username"`
 	Dob      time.Time `json:"dob"`
+	Scopes   []string  `json:"scopes,omitempty"`
+	// Role and Permissions are copied from the user when the token is
+	// issued, a role change applies once the access token is refreshed.
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Principal is the authenticated caller of a request, built by the auth
+// middleware from the access token.
+type Principal struct {
+	UserID      uint64
+	Username    string
+	Scopes      []string
+	Role        string
+	Permissions []string
+	TokenID     string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	// APIKeyID is set when the caller used an API key instead of a token,
+	// Scopes then limits what it may do.
+	APIKeyID uint64
+}
+
+// JSONWebKey is the public part of a signing key as described in RFC 7517.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
 }
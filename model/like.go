@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// PhotoLike records that UserID likes PhotoID.
+type PhotoLike struct {
+	UserID    uint64    `json:"user_id"`
+	PhotoID   int       `json:"photo_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PhotoLiker is one entry of the likers of a photo.
+type PhotoLiker struct {
+	ID       uint64    `json:"id"`
+	Username string    `json:"username"`
+	LikedAt  time.Time `json:"liked_at"`
+}
+
+type PhotoLikeList struct {
+	Users []PhotoLiker `json:"users"`
+	Page  PageInfo     `json:"page"`
+}
+
+// PhotoLikeStatus is returned after liking or unliking a photo.
+type PhotoLikeStatus struct {
+	PhotoID   int   `json:"photo_id"`
+	LikeCount int64 `json:"like_count"`
+	LikedByMe bool  `json:"liked_by_me"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	LOGIN_FAILURE_UNKNOWN_EMAIL  = "unknown_email"
+	LOGIN_FAILURE_WRONG_PASSWORD = "wrong_password"
+	LOGIN_FAILURE_LOCKED         = "locked"
+	LOGIN_FAILURE_SECOND_FACTOR  = "wrong_second_factor"
+)
+
+// LoginAttempt is the audit record of a failed login.
+type LoginAttempt struct {
+	ID        uint64    `json:"id"`
+	Email     string    `json:"email"`
+	UserID    *uint64   `json:"user_id"`
+	IP        string    `json:"ip"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// OIDCClaims are the verified ID token claims of an OpenID Connect login.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Nonce             string
+}
+
+// OIDCState keeps what the callback needs to finish a login, it is looked
+// up by the sha256 of the state sent to the provider.
+type OIDCState struct {
+	StateHash    string    `json:"-"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"-"`
+	Nonce        string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserIdentity links an account of an OpenID Connect provider to a user.
+type UserIdentity struct {
+	ID        uint64    `json:"id"`
+	UserID    uint64    `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "errors"
+
+const (
+	DEFAULT_PAGE_SIZE = 20
+	MAX_PAGE_SIZE     = 100
+)
+
+// PageRequest is read from the page and page_size query parameters, both
+// optional.
+type PageRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// PageInfo describes the page returned next to a list.
+type PageInfo struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+// Normalize fills in the defaults and rejects out of range values.
+func (p PageRequest) Normalize() (PageRequest, error) {
+	if p.Page == 0 {
+		p.Page = 1
+	}
+	if p.PageSize == 0 {
+		p.PageSize = DEFAULT_PAGE_SIZE
+	}
+	if p.Page < 1 {
+		return PageRequest{}, errors.New("invalid page: must be at least 1")
+	}
+	if p.PageSize < 1 || p.PageSize > MAX_PAGE_SIZE {
+		return PageRequest{}, errors.New("invalid page_size: must be between 1 and 100")
+	}
+	return p, nil
+}
+
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
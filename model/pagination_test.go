@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageRequestNormalize(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		in     PageRequest
+		out    PageRequest
+		offset int
+		err    bool
+	}{
+		{desc: "success defaults", out: PageRequest{Page: 1, PageSize: DEFAULT_PAGE_SIZE}},
+		{desc: "success third page", in: PageRequest{Page: 3, PageSize: 10}, out: PageRequest{Page: 3, PageSize: 10}, offset: 20},
+		{desc: "error negative page", in: PageRequest{Page: -1}, err: true},
+		{desc: "error page size too large", in: PageRequest{PageSize: MAX_PAGE_SIZE + 1}, err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			page, err := tC.in.Normalize()
+			if tC.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tC.out, page)
+			assert.Equal(t, tC.offset, page.Offset())
+		})
+	}
+}
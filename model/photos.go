@@ -3,17 +3,40 @@ package model
 import (
 	"errors"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Photo struct {
-	ID        int       `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title" gorm:"notNull"`
-	Caption   string    `json:"caption"`
-	URL       string    `json:"url" gorm:"notNull"`
-	UserID    int       `json:"user_id" gorm:"notNull"`
-	User      User      `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int            `json:"id" gorm:"primaryKey"`
+	Title     string         `json:"title" gorm:"notNull"`
+	Caption   string         `json:"caption"`
+	URL       string         `json:"url" gorm:"notNull"`
+	UserID    int            `json:"user_id" gorm:"notNull"`
+	User      User           `json:"-"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+	// StorageKey is set when the image was uploaded to the blob store
+	StorageKey string        `json:"-"`
+	Variants   PhotoVariants `json:"variants" gorm:"type:jsonb"`
+	Metadata   PhotoMetadata `json:"metadata" gorm:"embedded"`
+	// LikeCount and LikedByMe are only filled in by GetAllPhotos and
+	// GetPhotoByID
+	LikeCount int64 `json:"-" gorm:"->"`
+	LikedByMe bool  `json:"-" gorm:"->"`
+}
+
+// PhotoMetadata is read from uploaded images, it stays empty for photos
+// registered by URL. The GPS position is never kept here.
+type PhotoMetadata struct {
+	TakenAt     *time.Time `json:"taken_at"`
+	CameraMake  string     `json:"camera_make"`
+	CameraModel string     `json:"camera_model"`
+	// Orientation is the EXIF one, Width and Height are as displayed
+	Orientation int `json:"orientation"`
+	Width       int `json:"width"`
+	Height      int `json:"height"`
 }
 
 type PhotoUserGet struct {
@@ -27,6 +50,15 @@ type CreatePhoto struct {
 	URL     string `json:"url" validate:"required,url"`
 }
 
+// UploadPhoto holds the text fields of a multipart POST /photos, the image
+// itself is sent in the "image" field.
+type UploadPhoto struct {
+	Title   string `form:"title"`
+	Caption string `form:"caption"`
+	// KeepLocation leaves the GPS position in the stored image
+	KeepLocation bool `form:"keep_location"`
+}
+
 type UpdatePhoto struct {
 	Title   string `json:"title"`
 	Caption string `json:"caption"`
@@ -34,14 +66,36 @@ type UpdatePhoto struct {
 }
 
 type PhotoGet struct {
-	ID        int          `json:"id"`
-	Title     string       `json:"title"`
-	Caption   string       `json:"caption"`
-	URL       string       `json:"url"`
-	UserID    int          `json:"user_id"`
-	User      PhotoUserGet `json:"user"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	ID        int           `json:"id"`
+	Title     string        `json:"title"`
+	Caption   string        `json:"caption"`
+	URL       string        `json:"url"`
+	UserID    int           `json:"user_id"`
+	User      PhotoUserGet  `json:"user"`
+	Variants  PhotoVariants `json:"variants"`
+	LikeCount int64         `json:"like_count"`
+	LikedByMe bool          `json:"liked_by_me"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// PhotoDetail comes with the first page of comments, the next ones are
+// read from GET /photos/:photoId/comments with Comments.NextCursor. The
+// comments are left out for API keys without the comments:read scope.
+type PhotoDetail struct {
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	Caption   string         `json:"caption"`
+	URL       string         `json:"url"`
+	UserID    int            `json:"user_id"`
+	User      PhotoUserGet   `json:"user"`
+	Variants  PhotoVariants  `json:"variants"`
+	Metadata  PhotoMetadata  `json:"metadata"`
+	LikeCount int64          `json:"like_count"`
+	LikedByMe bool           `json:"liked_by_me"`
+	Comments  *PhotoComments `json:"comments,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
 }
 
 type PhotoUpdate struct {
@@ -61,3 +115,10 @@ func (p CreatePhoto) PhotoValidate() error {
 	}
 	return nil
 }
+
+func (p UploadPhoto) PhotoValidate() error {
+	if p.Title == "" {
+		return errors.New("invalid title cause is required")
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// PublicUser is what anyone may see of an account.
+type PublicUser struct {
+	ID        uint64    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicProfile is a user with their follow counts, one page of their
+// photos, newest first, and all their social media links.
+type PublicProfile struct {
+	User PublicUser `json:"user"`
+	FollowCounts
+	Photos       []Photo        `json:"photos"`
+	PhotosPage   PageInfo       `json:"photos_page"`
+	SocialMedias []SocialMedias `json:"social_medias"`
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+const (
+	ROLE_USER      = "user"
+	ROLE_MODERATOR = "moderator"
+	ROLE_ADMIN     = "admin"
+
+	PERMISSION_USERS_READ             = "users:read"
+	PERMISSION_USERS_DELETE           = "users:delete"
+	PERMISSION_USERS_MANAGE           = "users:manage"
+	PERMISSION_PHOTOS_MODERATE        = "photos:moderate"
+	PERMISSION_COMMENTS_MODERATE      = "comments:moderate"
+	PERMISSION_SOCIAL_MEDIAS_MODERATE = "social_medias:moderate"
+)
+
+// RolePermissions lists what each role is allowed on top of managing its
+// own resources, which every user can do.
+var RolePermissions = map[string][]string{
+	ROLE_USER: {},
+	ROLE_MODERATOR: {
+		PERMISSION_PHOTOS_MODERATE,
+		PERMISSION_COMMENTS_MODERATE,
+		PERMISSION_SOCIAL_MEDIAS_MODERATE,
+	},
+	ROLE_ADMIN: {
+		PERMISSION_USERS_READ,
+		PERMISSION_USERS_DELETE,
+		PERMISSION_USERS_MANAGE,
+		PERMISSION_PHOTOS_MODERATE,
+		PERMISSION_COMMENTS_MODERATE,
+		PERMISSION_SOCIAL_MEDIAS_MODERATE,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// Permissions is stored space separated in a text column, like OAuth scopes.
+type Permissions []string
+
+func (p Permissions) Value() (driver.Value, error) {
+	return strings.Join(p, " "), nil
+}
+
+func (p *Permissions) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+	case string:
+		*p = strings.Fields(v)
+	case []byte:
+		*p = strings.Fields(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into permissions", src)
+	}
+	return nil
+}
+
+// EffectivePermissions merges the permissions of the user's role with the
+// ones granted to the user directly.
+func (u User) EffectivePermissions() []string {
+	seen := map[string]bool{}
+	permissions := []string{}
+	for _, list := range [][]string{RolePermissions[u.Role], u.Permissions} {
+		for _, permission := range list {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+func (p Principal) HasPermission(permission string) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope is always true for sessions, API keys only get the scopes they
+// were created with.
+func (p Principal) HasScope(scope string) bool {
+	if p.APIKeyID == 0 {
+		return true
+	}
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type UpdateUserRole struct {
+	Role        string   `json:"role" binding:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+func (u UpdateUserRole) Validate() error {
+	if !IsValidRole(u.Role) {
+		return fmt.Errorf("invalid role %q", u.Role)
+	}
+	known := map[string]bool{}
+	for _, permissions := range RolePermissions {
+		for _, permission := range permissions {
+			known[permission] = true
+		}
+	}
+	for _, permission := range u.Permissions {
+		if !known[permission] {
+			return fmt.Errorf("invalid permission %q", permission)
+		}
+	}
+	return nil
+}
@@ -3,16 +3,19 @@ package model
 import (
 	"errors"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type SocialMedias struct {
-	ID        int       `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"notNull"`
-	URL       string    `json:"url" gorm:"notNull"`
-	UserID    int       `json:"user_id" gorm:"notNull"`
-	User      User      `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int            `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"notNull"`
+	URL       string         `json:"url" gorm:"notNull"`
+	UserID    int            `json:"user_id" gorm:"notNull"`
+	User      User           `json:"-"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
 }
 
 type SocialMediaUserGet struct {
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type UserTOTP struct {
+	UserID       uint64    `json:"user_id"`
+	Secret       string    `json:"-"`
+	LastUsedStep int64     `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uint64     `json:"id"`
+	UserID    uint64     `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TwoFactorChallengeClaim is handed out by the login when the password was
+// right but a second factor is still missing.
+type TwoFactorChallengeClaim struct {
+	StandardClaim
+	UserID uint64 `json:"user_id"`
+}
+
+// TOTPEnrollment is shown once so the user can add the secret to an
+// authenticator app, URI is meant to be rendered as a QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// SecondFactor is either a code of the authenticator app or one of the
+// recovery codes.
+type SecondFactor struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	SecondFactor
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	SecondFactor
+}
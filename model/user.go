@@ -2,21 +2,38 @@ package model
 
 import (
 	"errors"
+	"net/mail"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint64         `json:"id"`
-	Username  string         `json:"username"`
-	Email     string         `json:"email"`
-	Password  string         `json:"-"`
-	DoB       time.Time      `json:"age" gorm:"column:dob"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+	ID                 uint64         `json:"id"`
+	Username           string         `json:"username"`
+	Email              string         `json:"email"`
+	Password           string         `json:"-"`
+	DoB                time.Time      `json:"age" gorm:"column:dob"`
+	Role               string         `json:"role" gorm:"column:role;default:user"`
+	Permissions        Permissions    `json:"permissions,omitempty" gorm:"column:permissions"`
+	VerifiedAt         *time.Time     `json:"verified_at" gorm:"column:verified_at"`
+	TwoFactorEnabledAt *time.Time     `json:"two_factor_enabled_at" gorm:"column:two_factor_enabled_at"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+}
+
+// UserStats counts what a user posted, soft deleted rows left out.
+type UserStats struct {
+	PhotoCount       int64 `json:"photo_count"`
+	CommentCount     int64 `json:"comment_count"`
+	SocialMediaCount int64 `json:"social_media_count"`
+}
+
+// UserProfile is the account of the caller as returned by /users/me.
+type UserProfile struct {
+	User
+	UserStats
 }
 
 type DefaultColumn struct {
@@ -38,7 +55,7 @@ type UserMediaSocial struct {
 type UserSignUp struct {
 	Username string    `json:"username" binding:"required"`
 	Password string    `json:"password" binding:"required"`
-	Email    string    `json:"email"`
+	Email    string    `json:"email" binding:"required"`
 	DoB      time.Time `json:"age"`
 }
 
@@ -51,8 +68,8 @@ func (u UserSignUp) Validate() error {
 	if u.Username == "" {
 		return errors.New("invalid username")
 	}
-	if len(u.Password) < 6 {
-		return errors.New("invalid password: length must be at least 6 characters")
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return errors.New("invalid email")
 	}
 
 	dobString := u.DoB.Format(time.RFC3339)
@@ -70,10 +87,3 @@ func (u UserSignUp) Validate() error {
 
 	return nil
 }
-
-func (u UserSignIn) Authenticate(passwordHash string) error {
-	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(u.Password)); err != nil {
-		return errors.New("invalid credentials")
-	}
-	return nil
-}
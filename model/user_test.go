@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,4 +14,14 @@ func TestUserValidate(t *testing.T) {
 
 		assert.NotNil(t, err)
 	})
+	t.Run("error email", func(t *testing.T) {
+		for _, email := range []string{"", "not-an-email", "User <user@mail.com>"} {
+			user := UserSignUp{Username: "username", Email: email, Password: "abc12345", DoB: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+			assert.NotNil(t, user.Validate(), email)
+		}
+	})
+	t.Run("success", func(t *testing.T) {
+		user := UserSignUp{Username: "username", Email: "user@mail.com", Password: "abc12345", DoB: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+		assert.Nil(t, user.Validate())
+	})
 }
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	VARIANT_FORMAT_JPEG = "jpeg"
+	VARIANT_FORMAT_PNG  = "png"
+	VARIANT_FORMAT_WEBP = "webp"
+)
+
+// PhotoVariant is a resized copy of a photo, clients pick the smallest one
+// at least as wide as where it is shown.
+type PhotoVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+	URL    string `json:"url"`
+	Size   int64  `json:"size"`
+	// Key is the blob of the variant, kept in the database only
+	Key string `json:"-"`
+}
+
+// PhotoVariants is stored as a JSON array in a jsonb column.
+type PhotoVariants []PhotoVariant
+
+type storedVariant struct {
+	PhotoVariant
+	Key string `json:"key"`
+}
+
+func (p PhotoVariants) Value() (driver.Value, error) {
+	stored := make([]storedVariant, 0, len(p))
+	for _, variant := range p {
+		stored = append(stored, storedVariant{PhotoVariant: variant, Key: variant.Key})
+	}
+	b, err := json.Marshal(stored)
+	return string(b), err
+}
+
+func (p *PhotoVariants) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*p = PhotoVariants{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into photo variants", src)
+	}
+
+	stored := []storedVariant{}
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return err
+	}
+	variants := make(PhotoVariants, 0, len(stored))
+	for _, variant := range stored {
+		variant.PhotoVariant.Key = variant.Key
+		variants = append(variants, variant.PhotoVariant)
+	}
+	*p = variants
+	return nil
+}
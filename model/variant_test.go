@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhotoVariants(t *testing.T) {
+	t.Run("success key kept in the column only", func(t *testing.T) {
+		variants := PhotoVariants{{Width: 320, Height: 240, Format: VARIANT_FORMAT_JPEG, URL: "http://localhost/blobs/variants/1/320.jpg", Size: 10, Key: "variants/1/320.jpg"}}
+
+		value, err := variants.Value()
+		assert.Nil(t, err)
+		assert.Contains(t, value, `"key":"variants/1/320.jpg"`)
+
+		scanned := PhotoVariants{}
+		assert.Nil(t, scanned.Scan([]byte(value.(string))))
+		assert.Equal(t, variants, scanned)
+	})
+
+	t.Run("success null is empty", func(t *testing.T) {
+		scanned := PhotoVariants{{Width: 1}}
+		assert.Nil(t, scanned.Scan(nil))
+		assert.Equal(t, PhotoVariants{}, scanned)
+	})
+}
@@ -1,6 +1,27 @@
 package pkg
 
 type ErrorResponse struct {
+	Code    string   `json:"code,omitempty"`
 	Message string   `json:"message"`
 	Errors  []string `json:"errors,omitempty"`
 }
+
+// error codes returned with 401 responses
+const (
+	ERR_CODE_TOKEN_MISSING       = "token_missing"
+	ERR_CODE_INVALID_AUTH_METHOD = "invalid_authorization_method"
+	ERR_CODE_TOKEN_REVOKED       = "token_revoked"
+	ERR_CODE_INVALID_CREDENTIALS = "invalid_credentials"
+	ERR_CODE_INVALID_API_KEY     = "invalid_api_key"
+)
+
+// error codes returned with 403 responses
+const (
+	ERR_CODE_PERMISSION_DENIED  = "permission_denied"
+	ERR_CODE_INSUFFICIENT_SCOPE = "insufficient_scope"
+)
+
+// error codes returned with 429 responses
+const (
+	ERR_CODE_LOGIN_LOCKED = "login_locked"
+)
@@ -1,33 +1,47 @@
 package helper
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const (
-	SECRET_JWT = "mysecretjwtdontsharethistoanyoneelse"
-)
-
-func GenerateToken(claim any) (token string, err error) {
+func GenerateToken(claim any, keys *KeySet) (token string, err error) {
 	jwtClaim := jwt.MapClaims{}
 	b, err := json.Marshal(claim)
 	if err != nil {
 		log.Println("cannot marshal claim payload")
 		return
 	}
-	err = json.Unmarshal(b, &jwtClaim)
+	// json.Number keeps ids above 2^53 intact
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&jwtClaim)
 	if err != nil {
 		log.Println("cannot mapping claim to jwt claim")
 		return
 	}
+	key, err := keys.SigningKey(time.Now())
+	if err != nil {
+		log.Println("cannot pick signing key", err.Error())
+		return
+	}
 	// prepare
-	parseToken := jwt.NewWithClaims(jwt.SigningMethodHS512, jwtClaim)
+	parseToken := jwt.NewWithClaims(key.Method, jwtClaim)
+	if key.ID != "" {
+		parseToken.Header["kid"] = key.ID
+	}
 	// generate token
-	token, err = parseToken.SignedString([]byte(SECRET_JWT))
+	token, err = parseToken.SignedString(key.Private)
 	if err != nil {
 		log.Println("cannot generate token", err.Error())
 		return
@@ -35,28 +49,60 @@ func GenerateToken(claim any) (token string, err error) {
 	return
 }
 
-func ValidateToken(token string) (claim jwt.MapClaims, err error) {
-	jwtToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+func ValidateToken(token string, keys *KeySet, expect TokenExpectation) (claim jwt.MapClaims, err error) {
+	// exp, nbf and iat are checked by expect, with leeway. numbers are kept
+	// as json.Number so ids above 2^53 survive
+	parser := jwt.Parser{SkipClaimsValidation: true, UseJSONNumber: true}
+	jwtToken, err := parser.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := keys.VerificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		// the key decides the algorithm, never the token
+		if t.Method.Alg() != key.Method.Alg() {
 			return nil, jwt.ErrSignatureInvalid
 		}
 
-		return []byte(SECRET_JWT), nil
+		return key.Public, nil
 	})
 	if err != nil {
 		log.Println("error validating jwt token", err.Error())
-		return
+		if validationErr, ok := err.(*jwt.ValidationError); ok {
+			var tokenErr *TokenError
+			if errors.As(validationErr.Inner, &tokenErr) {
+				return nil, tokenErr
+			}
+			if validationErr.Errors&jwt.ValidationErrorSignatureInvalid != 0 || validationErr.Inner == jwt.ErrSignatureInvalid {
+				return nil, ErrTokenSignatureInvalid
+			}
+		}
+		return nil, ErrTokenMalformed
 	}
 
 	// translate claim
 	claim, ok := jwtToken.Claims.(jwt.MapClaims)
 	if !ok {
 		log.Println("error translate claim")
-		return
+		return nil, ErrTokenMalformed
+	}
+
+	if err = expect.validate(claim, time.Now()); err != nil {
+		return nil, err
 	}
 	return
 }
 
+// DecodeClaim maps validated claims onto a typed claim such as
+// model.AccessClaim.
+func DecodeClaim(claim jwt.MapClaims, out any) error {
+	b, err := json.Marshal(claim)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
 func GenerateHash(in string) (out string, err error) {
 	outByte, err := bcrypt.GenerateFromPassword([]byte(in), bcrypt.DefaultCost)
 	if err != nil {
@@ -76,3 +122,28 @@ func CompareHash(plainPassword, hashedPassword string) (match bool, err error) {
 	}
 	return true, nil
 }
+
+// GenerateRandomToken returns n random bytes encoded as url-safe base64,
+// suitable for opaque tokens handed to clients.
+func GenerateRandomToken(n int) (token string, err error) {
+	b := make([]byte, n)
+	if _, err = rand.Read(b); err != nil {
+		log.Println("error generate random token", err.Error())
+		return
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex sha256 of an opaque token. Only the hash is
+// stored so a leaked table cannot be replayed.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PKCEChallenge returns the S256 code challenge of a PKCE code verifier,
+// see RFC 7636.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,3 +26,61 @@ func TestGenerateHash(t *testing.T) {
 		assert.NotEqual(t, "", res)
 	})
 }
+
+func TestValidateToken(t *testing.T) {
+	secret := "a-very-long-secret-used-for-testing-only"
+	expect := TokenExpectation{
+		Issuer:   "project",
+		Audience: "mygram",
+		Subject:  "access-token",
+		Leeway:   30 * time.Second,
+	}
+	now := time.Now()
+	newClaim := func() map[string]any {
+		return map[string]any{
+			"iss": "project",
+			"aud": "mygram",
+			"sub": "access-token",
+			"exp": now.Add(time.Hour).Unix(),
+			"iat": now.Unix(),
+			"nbf": now.Unix(),
+		}
+	}
+
+	testCases := []struct {
+		desc   string
+		secret string
+		modify func(claim map[string]any)
+		err    error
+	}{
+		{desc: "success", secret: secret, modify: func(claim map[string]any) {}},
+		{desc: "success expired within leeway", secret: secret, modify: func(claim map[string]any) { claim["exp"] = now.Add(-10 * time.Second).Unix() }},
+		{desc: "error expired", secret: secret, modify: func(claim map[string]any) { claim["exp"] = now.Add(-time.Minute).Unix() }, err: ErrTokenExpired},
+		{desc: "error not yet valid", secret: secret, modify: func(claim map[string]any) { claim["nbf"] = now.Add(time.Minute).Unix() }, err: ErrTokenNotYetValid},
+		{desc: "error wrong issuer", secret: secret, modify: func(claim map[string]any) { claim["iss"] = "go-middleware" }, err: ErrTokenWrongIssuer},
+		{desc: "error wrong audience", secret: secret, modify: func(claim map[string]any) { claim["aud"] = "golang-006" }, err: ErrTokenWrongAudience},
+		{desc: "error public token", secret: secret, modify: func(claim map[string]any) { claim["sub"] = "public-token" }, err: ErrTokenWrongType},
+		{desc: "error missing exp", secret: secret, modify: func(claim map[string]any) { delete(claim, "exp") }, err: ErrTokenMalformed},
+		{desc: "error signature", secret: "another-secret-with-enough-characters", modify: func(claim map[string]any) {}, err: ErrTokenSignatureInvalid},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			claim := newClaim()
+			tC.modify(claim)
+			token, err := GenerateToken(claim, NewHMACKeySet(tC.secret))
+			assert.Nil(t, err)
+
+			_, err = ValidateToken(token, NewHMACKeySet(secret), expect)
+			if tC.err != nil {
+				assert.ErrorIs(t, err, tC.err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	t.Run("error malformed", func(t *testing.T) {
+		_, err := ValidateToken("not-a-token", NewHMACKeySet(secret), expect)
+		assert.ErrorIs(t, err, ErrTokenMalformed)
+	})
+}
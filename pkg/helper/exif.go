@@ -0,0 +1,249 @@
+package helper
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExif = errors.New("invalid exif data")
+
+const (
+	EXIF_TAG_MAKE                 = 0x010f
+	EXIF_TAG_MODEL                = 0x0110
+	EXIF_TAG_ORIENTATION          = 0x0112
+	EXIF_TAG_DATE_TIME            = 0x0132
+	EXIF_TAG_EXIF_IFD             = 0x8769
+	EXIF_TAG_GPS_IFD              = 0x8825
+	EXIF_TAG_DATE_TIME_ORIGINAL   = 0x9003
+	EXIF_TAG_OFFSET_TIME_ORIGINAL = 0x9011
+)
+
+// exifTypeSizes is the size in bytes of one value of each TIFF field type.
+var exifTypeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8, 13: 4}
+
+// Exif holds the fields of an EXIF block that are kept with a photo.
+type Exif struct {
+	Make        string
+	Model       string
+	Orientation int
+	// TakenAt is in UTC when the camera recorded its offset, otherwise the
+	// local time of the camera read as UTC
+	TakenAt *time.Time
+	HasGPS  bool
+}
+
+type exifEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	// at is the offset of the entry in the block
+	at uint32
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// ParseExif reads the TIFF structure found in the EXIF segment of a JPEG,
+// the eXIf chunk of a PNG or the EXIF chunk of a WebP.
+func ParseExif(tiff []byte) (Exif, error) {
+	r, err := newExifReader(tiff)
+	if err != nil {
+		return Exif{}, err
+	}
+	ifd0, err := r.ifd(r.order.Uint32(tiff[4:]))
+	if err != nil {
+		return Exif{}, err
+	}
+
+	exif := Exif{}
+	var dateTime, original, offset string
+	for _, entry := range ifd0 {
+		switch entry.tag {
+		case EXIF_TAG_MAKE:
+			exif.Make = r.ascii(entry)
+		case EXIF_TAG_MODEL:
+			exif.Model = r.ascii(entry)
+		case EXIF_TAG_ORIENTATION:
+			if orientation := r.short(entry); orientation >= 1 && orientation <= 8 {
+				exif.Orientation = orientation
+			}
+		case EXIF_TAG_DATE_TIME:
+			dateTime = r.ascii(entry)
+		case EXIF_TAG_GPS_IFD:
+			// a stripped block keeps an empty directory
+			if at := r.long(entry); at != 0 {
+				gps, err := r.ifd(at)
+				exif.HasGPS = err != nil || len(gps) > 0
+			}
+		case EXIF_TAG_EXIF_IFD:
+			at := r.long(entry)
+			if at == 0 {
+				continue
+			}
+			sub, err := r.ifd(at)
+			if err != nil {
+				return Exif{}, err
+			}
+			for _, entry := range sub {
+				switch entry.tag {
+				case EXIF_TAG_DATE_TIME_ORIGINAL:
+					original = r.ascii(entry)
+				case EXIF_TAG_OFFSET_TIME_ORIGINAL:
+					offset = r.ascii(entry)
+				}
+			}
+		}
+	}
+
+	// the original time is when the shutter was pressed, DateTime is
+	// rewritten by editors
+	if original == "" {
+		original, offset = dateTime, ""
+	}
+	if takenAt, ok := parseExifTime(original, offset); ok {
+		exif.TakenAt = &takenAt
+	}
+	return exif, nil
+}
+
+// StripExifGPS empties the GPS directory of tiff in place and zeroes the
+// values it pointed to, it reports whether there was one. The directory
+// stays, empty, so no offset in the block has to move.
+func StripExifGPS(tiff []byte) (bool, error) {
+	r, err := newExifReader(tiff)
+	if err != nil {
+		return false, err
+	}
+	ifd0, err := r.ifd(r.order.Uint32(tiff[4:]))
+	if err != nil {
+		return false, err
+	}
+
+	stripped := false
+	for _, entry := range ifd0 {
+		if entry.tag != EXIF_TAG_GPS_IFD {
+			continue
+		}
+		at := r.long(entry)
+		if at == 0 {
+			continue
+		}
+		gps, err := r.ifd(at)
+		if err != nil {
+			return false, err
+		}
+		for _, field := range gps {
+			if start, end, ok := r.outOfLine(field); ok {
+				clear(tiff[start:end])
+			}
+		}
+		// count, entries and next directory offset
+		clear(tiff[at : at+2+12*uint32(len(gps))+4])
+		stripped = true
+	}
+	return stripped, nil
+}
+
+func newExifReader(tiff []byte) (*exifReader, error) {
+	if len(tiff) < 8 {
+		return nil, ErrInvalidExif
+	}
+	r := &exifReader{data: tiff}
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		r.order = binary.LittleEndian
+	case "MM\x00*":
+		r.order = binary.BigEndian
+	default:
+		return nil, ErrInvalidExif
+	}
+	return r, nil
+}
+
+func (r *exifReader) ifd(at uint32) ([]exifEntry, error) {
+	if uint64(at)+2 > uint64(len(r.data)) {
+		return nil, ErrInvalidExif
+	}
+	count := uint32(r.order.Uint16(r.data[at:]))
+	if uint64(at)+2+12*uint64(count)+4 > uint64(len(r.data)) {
+		return nil, ErrInvalidExif
+	}
+	entries := make([]exifEntry, 0, count)
+	for i := uint32(0); i < count; i++ {
+		offset := at + 2 + 12*i
+		entries = append(entries, exifEntry{
+			tag:   r.order.Uint16(r.data[offset:]),
+			typ:   r.order.Uint16(r.data[offset+2:]),
+			count: r.order.Uint32(r.data[offset+4:]),
+			at:    offset,
+		})
+	}
+	return entries, nil
+}
+
+// value returns the bytes of the values of an entry, stored in the entry
+// itself when they fit in 4 bytes.
+func (r *exifReader) value(entry exifEntry) []byte {
+	if start, end, ok := r.outOfLine(entry); ok {
+		return r.data[start:end]
+	}
+	size := uint64(exifTypeSizes[entry.typ]) * uint64(entry.count)
+	if size == 0 || size > 4 {
+		return nil
+	}
+	return r.data[entry.at+8 : uint64(entry.at)+8+size]
+}
+
+func (r *exifReader) outOfLine(entry exifEntry) (uint32, uint32, bool) {
+	size := uint64(exifTypeSizes[entry.typ]) * uint64(entry.count)
+	if size <= 4 {
+		return 0, 0, false
+	}
+	start := uint64(r.order.Uint32(r.data[entry.at+8:]))
+	if start+size > uint64(len(r.data)) {
+		return 0, 0, false
+	}
+	return uint32(start), uint32(start + size), true
+}
+
+func (r *exifReader) ascii(entry exifEntry) string {
+	if entry.typ != 2 {
+		return ""
+	}
+	value, _, _ := strings.Cut(string(r.value(entry)), "\x00")
+	return strings.TrimSpace(value)
+}
+
+func (r *exifReader) short(entry exifEntry) int {
+	value := r.value(entry)
+	if entry.typ != 3 || len(value) < 2 {
+		return 0
+	}
+	return int(r.order.Uint16(value))
+}
+
+func (r *exifReader) long(entry exifEntry) uint32 {
+	value := r.value(entry)
+	// 13 is the IFD type of newer writers
+	if (entry.typ != 4 && entry.typ != 13) || len(value) < 4 {
+		return 0
+	}
+	return r.order.Uint32(value)
+}
+
+func parseExifTime(value, offset string) (time.Time, bool) {
+	if offset != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+			return t.UTC(), true
+		}
+	}
+	t, err := time.Parse("2006:01:02 15:04:05", value)
+	if err != nil || t.Year() < 1900 {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testExif builds a little endian EXIF block with a camera, an orientation,
+// the time the photo was taken and a GPS latitude.
+func testExif(orientation uint16) []byte {
+	le := binary.LittleEndian
+	entry := func(b []byte, tag, typ uint16, count, value uint32) []byte {
+		b = le.AppendUint16(b, tag)
+		b = le.AppendUint16(b, typ)
+		b = le.AppendUint32(b, count)
+		return le.AppendUint32(b, value)
+	}
+
+	b := []byte("II*\x00")
+	b = le.AppendUint32(b, 8)
+	// ifd0 at 8, 5 entries up to 74
+	b = le.AppendUint16(b, 5)
+	b = entry(b, EXIF_TAG_MAKE, 2, 6, 74)
+	b = entry(b, EXIF_TAG_MODEL, 2, 7, 80)
+	b = entry(b, EXIF_TAG_ORIENTATION, 3, 1, uint32(orientation))
+	b = entry(b, EXIF_TAG_EXIF_IFD, 4, 1, 88)
+	b = entry(b, EXIF_TAG_GPS_IFD, 4, 1, 146)
+	b = le.AppendUint32(b, 0)
+	b = append(b, "Canon\x00EOS 5D\x00\x00"...)
+	// exif ifd at 88, 2 entries up to 118
+	b = le.AppendUint16(b, 2)
+	b = entry(b, EXIF_TAG_DATE_TIME_ORIGINAL, 2, 20, 118)
+	b = entry(b, EXIF_TAG_OFFSET_TIME_ORIGINAL, 2, 7, 138)
+	b = le.AppendUint32(b, 0)
+	b = append(b, "2024:05:06 07:08:09\x00+02:00\x00\x00"...)
+	// gps ifd at 146, latitude ref inline and latitude at 176
+	b = le.AppendUint16(b, 2)
+	b = entry(b, 1, 2, 2, uint32('N'))
+	b = entry(b, 2, 5, 3, 176)
+	b = le.AppendUint32(b, 0)
+	for _, v := range []uint32{48, 1, 51, 1, 2430, 100} {
+		b = le.AppendUint32(b, v)
+	}
+	return b
+}
+
+func TestParseExif(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		exif, err := ParseExif(testExif(6))
+		assert.Nil(t, err)
+		assert.Equal(t, "Canon", exif.Make)
+		assert.Equal(t, "EOS 5D", exif.Model)
+		assert.Equal(t, 6, exif.Orientation)
+		assert.Equal(t, time.Date(2024, 5, 6, 5, 8, 9, 0, time.UTC), *exif.TakenAt)
+		assert.True(t, exif.HasGPS)
+	})
+
+	t.Run("success invalid orientation is ignored", func(t *testing.T) {
+		exif, err := ParseExif(testExif(9))
+		assert.Nil(t, err)
+		assert.Equal(t, 0, exif.Orientation)
+	})
+
+	t.Run("error truncated", func(t *testing.T) {
+		_, err := ParseExif(testExif(1)[:40])
+		assert.ErrorIs(t, err, ErrInvalidExif)
+	})
+
+	t.Run("error not tiff", func(t *testing.T) {
+		_, err := ParseExif([]byte("not an exif block"))
+		assert.ErrorIs(t, err, ErrInvalidExif)
+	})
+}
+
+func TestStripExifGPS(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tiff := testExif(1)
+		stripped, err := StripExifGPS(tiff)
+		assert.Nil(t, err)
+		assert.True(t, stripped)
+		// the latitude is gone, the rest is untouched
+		assert.Equal(t, make([]byte, 24), tiff[176:])
+		assert.Equal(t, make([]byte, 30), tiff[146:176])
+		assert.Equal(t, testExif(1)[:146], tiff[:146])
+
+		exif, err := ParseExif(tiff)
+		assert.Nil(t, err)
+		assert.False(t, exif.HasGPS)
+		assert.Equal(t, "EOS 5D", exif.Model)
+	})
+
+	t.Run("success without gps", func(t *testing.T) {
+		tiff := testExif(1)[:146]
+		// drop the gps entry from ifd0
+		binary.LittleEndian.PutUint16(tiff[8:], 4)
+		stripped, err := StripExifGPS(tiff)
+		assert.Nil(t, err)
+		assert.False(t, stripped)
+	})
+}
@@ -0,0 +1,56 @@
+package helper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LoadHtpasswd reads an htpasswd file, see ParseHtpasswd.
+func LoadHtpasswd(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := ParseHtpasswd(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// ParseHtpasswd reads "user:hash" lines as written by htpasswd -B. Blank
+// lines and lines starting with # are skipped. Only bcrypt hashes are
+// accepted, the MD5, SHA1 and crypt variants are too weak to keep.
+func ParseHtpasswd(r io.Reader) (map[string]string, error) {
+	entries := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(text, ":")
+		if !ok || user == "" {
+			return nil, fmt.Errorf("line %d: expected user:hash", line)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("line %d: %s is not a bcrypt hash", line, user)
+		}
+		if _, exists := entries[user]; exists {
+			return nil, fmt.Errorf("line %d: duplicate user %s", line, user)
+		}
+		entries[user] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHtpasswd(t *testing.T) {
+	hash, err := PasswordPolicy{Cost: 4}.Hash("secret")
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		desc    string
+		input   string
+		entries int
+		err     string
+	}{
+		{desc: "success", input: "# service accounts\n\nbot@mail.com:" + hash + "\n", entries: 1},
+		{desc: "success apache prefix", input: "bot@mail.com:" + strings.Replace(hash, "$2a$", "$2y$", 1), entries: 1},
+		{desc: "error md5 hash", input: "bot@mail.com:$apr1$salt$hash", err: "line 1: bot@mail.com is not a bcrypt hash"},
+		{desc: "error missing hash", input: "bot@mail.com", err: "line 1: expected user:hash"},
+		{desc: "error duplicate user", input: "bot@mail.com:" + hash + "\nbot@mail.com:" + hash, err: "line 2: duplicate user bot@mail.com"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			entries, err := ParseHtpasswd(strings.NewReader(tC.input))
+			if tC.err != "" {
+				assert.EqualError(t, err, tC.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, entries, tC.entries)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// ResizeImage scales src to width, keeping its aspect ratio. Every pixel of
+// the result is the average of the source pixels it covers, which is what
+// thumbnails need: no aliasing when shrinking a lot, and alpha is averaged
+// premultiplied so transparent pixels do not bleed their color.
+func ResizeImage(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	height := int(math.Round(float64(srcHeight) * float64(width) / float64(srcWidth)))
+	if height < 1 {
+		height = 1
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, srcWidth, srcHeight))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	// horizontal pass into tmp, srcHeight rows of width pixels
+	tmp := make([]float64, width*srcHeight*4)
+	for x, span := range resizeSpans(srcWidth, width) {
+		for y := 0; y < srcHeight; y++ {
+			row := rgba.Pix[y*rgba.Stride:]
+			out := tmp[(y*width+x)*4:]
+			for i, weight := range span.weights {
+				in := row[(span.start+i)*4:]
+				for c := 0; c < 4; c++ {
+					out[c] += float64(in[c]) * weight
+				}
+			}
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, span := range resizeSpans(srcHeight, height) {
+		for x := 0; x < width; x++ {
+			var sum [4]float64
+			for i, weight := range span.weights {
+				in := tmp[((span.start+i)*width+x)*4:]
+				for c := 0; c < 4; c++ {
+					sum[c] += in[c] * weight
+				}
+			}
+			out := dst.Pix[y*dst.Stride+x*4:]
+			for c := 0; c < 4; c++ {
+				out[c] = uint8(math.Min(255, math.Round(sum[c])))
+			}
+		}
+	}
+	return dst
+}
+
+// resizeSpan lists the source pixels covered by one destination pixel,
+// weighted by how much of them it covers. The weights add up to 1.
+type resizeSpan struct {
+	start   int
+	weights []float64
+}
+
+func resizeSpans(from, to int) []resizeSpan {
+	scale := float64(from) / float64(to)
+	spans := make([]resizeSpan, to)
+	for i := range spans {
+		begin, end := float64(i)*scale, float64(i+1)*scale
+		first := int(begin)
+		last := int(math.Ceil(end))
+		if last > from {
+			last = from
+		}
+		span := resizeSpan{start: first, weights: make([]float64, 0, last-first)}
+		for p := first; p < last; p++ {
+			covered := math.Min(end, float64(p+1)) - math.Max(begin, float64(p))
+			span.weights = append(span.weights, covered/scale)
+		}
+		spans[i] = span
+	}
+	return spans
+}
@@ -0,0 +1,396 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+)
+
+var ErrInvalidImage = errors.New("invalid image")
+
+var (
+	jpegExifHeader = []byte("Exif\x00\x00")
+	pngSignature   = []byte("\x89PNG\r\n\x1a\n")
+	// size of the first sub-block and application identifier of gif xmp
+	gifXMPHeader = []byte("\x0bXMP DataXMP")
+)
+
+// WebP VP8X flags of the optional chunks
+const (
+	WEBP_FLAG_XMP  = 0x04
+	WEBP_FLAG_EXIF = 0x08
+)
+
+// ImageMetadata is what is known of an image from its headers.
+type ImageMetadata struct {
+	Exif
+	// Width and Height are as displayed, after the orientation is applied
+	Width  int
+	Height int
+}
+
+// ReadImageMetadata reads the dimensions and the EXIF of a JPEG, PNG, GIF
+// or WebP image. It is best effort, what can not be read is left empty.
+func ReadImageMetadata(src []byte) ImageMetadata {
+	metadata := ImageMetadata{}
+	if tiff := findExif(src); tiff != nil {
+		if exif, err := ParseExif(tiff); err == nil {
+			metadata.Exif = exif
+		}
+	}
+
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(src)); err == nil {
+		metadata.Width, metadata.Height = cfg.Width, cfg.Height
+	}
+	if metadata.Orientation >= 5 {
+		metadata.Width, metadata.Height = metadata.Height, metadata.Width
+	}
+	return metadata
+}
+
+// StripImageLocation returns src without the GPS position of its EXIF and
+// without its XMP, which can hold the position as well. The other EXIF
+// fields, among them the orientation, are kept. EXIF that can not be
+// parsed is removed as a whole. A GIF has no EXIF, only its XMP is removed.
+func StripImageLocation(src []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(src, []byte{0xff, 0xd8}):
+		return stripJPEGLocation(src)
+	case bytes.HasPrefix(src, pngSignature):
+		return stripPNGLocation(src)
+	case isWebP(src):
+		return stripWebPLocation(src)
+	case bytes.HasPrefix(src, []byte("GIF8")):
+		return stripGIFLocation(src)
+	}
+	return src, nil
+}
+
+// OrientImage turns img the way an EXIF orientation says it is displayed.
+func OrientImage(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:])
+		}
+	}
+	return dst
+}
+
+// findExif returns the TIFF block of the EXIF of src, a slice of src.
+func findExif(src []byte) []byte {
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(src, []byte{0xff, 0xd8}):
+		_ = eachJPEGSegment(src, func(marker byte, payload []byte) bool {
+			if marker == 0xe1 && bytes.HasPrefix(payload, jpegExifHeader) {
+				tiff = payload[len(jpegExifHeader):]
+				return false
+			}
+			return true
+		})
+	case bytes.HasPrefix(src, pngSignature):
+		_ = eachPNGChunk(src, func(typ string, data []byte) bool {
+			if typ == "eXIf" {
+				tiff = data
+				return false
+			}
+			return true
+		})
+	case isWebP(src):
+		_ = eachWebPChunk(src, func(fourCC string, data []byte) bool {
+			if fourCC == "EXIF" {
+				// some writers keep the jpeg header
+				tiff = bytes.TrimPrefix(data, jpegExifHeader)
+				return false
+			}
+			return true
+		})
+	}
+	return tiff
+}
+
+// eachJPEGSegment calls fn with the marker segments before the image data.
+// payload is a slice of src, without the marker and length.
+func eachJPEGSegment(src []byte, fn func(marker byte, payload []byte) bool) error {
+	for at := 2; ; {
+		if at+4 > len(src) || src[at] != 0xff {
+			return ErrInvalidImage
+		}
+		marker := src[at+1]
+		// start of scan, the compressed data follows
+		if marker == 0xda {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(src[at+2:]))
+		if length < 2 || at+2+length > len(src) {
+			return ErrInvalidImage
+		}
+		if !fn(marker, src[at+4:at+2+length]) {
+			return nil
+		}
+		at += 2 + length
+	}
+}
+
+func stripJPEGLocation(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	out = append(out, src[:2]...)
+	at := 2
+	err := eachJPEGSegment(src, func(marker byte, payload []byte) bool {
+		segment := src[at : at+4+len(payload)]
+		at += len(segment)
+		switch {
+		case marker == 0xe1 && bytes.HasPrefix(payload, jpegExifHeader):
+			exif := bytes.Clone(segment)
+			if _, err := StripExifGPS(exif[4+len(jpegExifHeader):]); err != nil {
+				return true
+			}
+			out = append(out, exif...)
+		case marker == 0xe1, marker == 0xed:
+			// xmp, and photoshop resources that carry iptc locations
+		default:
+			out = append(out, segment...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(out, src[at:]...), nil
+}
+
+// eachPNGChunk calls fn with the chunks of a png, data is a slice of src.
+func eachPNGChunk(src []byte, fn func(typ string, data []byte) bool) error {
+	for at := len(pngSignature); at < len(src); {
+		if at+12 > len(src) {
+			return ErrInvalidImage
+		}
+		length := int(binary.BigEndian.Uint32(src[at:]))
+		if length < 0 || at+12+length > len(src) {
+			return ErrInvalidImage
+		}
+		typ := string(src[at+4 : at+8])
+		if !fn(typ, src[at+8:at+8+length]) || typ == "IEND" {
+			return nil
+		}
+		at += 12 + length
+	}
+	return nil
+}
+
+func stripPNGLocation(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	out = append(out, pngSignature...)
+	err := eachPNGChunk(src, func(typ string, data []byte) bool {
+		switch typ {
+		case "eXIf":
+			exif := bytes.Clone(data)
+			if _, err := StripExifGPS(exif); err != nil {
+				return true
+			}
+			out = appendPNGChunk(out, typ, exif)
+		case "tEXt", "zTXt", "iTXt":
+			// xmp, and exif kept as text by imagemagick
+			keyword, _, _ := bytes.Cut(data, []byte{0})
+			if string(keyword) == "XML:com.adobe.xmp" || strings.HasPrefix(string(keyword), "Raw profile type") {
+				return true
+			}
+			out = appendPNGChunk(out, typ, data)
+		default:
+			out = appendPNGChunk(out, typ, data)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func appendPNGChunk(out []byte, typ string, data []byte) []byte {
+	out = binary.BigEndian.AppendUint32(out, uint32(len(data)))
+	start := len(out)
+	out = append(out, typ...)
+	out = append(out, data...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(out[start:]))
+}
+
+// stripGIFLocation drops the XMP application extensions of a gif. The XMP
+// packet is written raw, followed by a trailer that makes it read as
+// sub-blocks, so skipping the sub-blocks finds its end.
+func stripGIFLocation(src []byte) ([]byte, error) {
+	// header and logical screen descriptor
+	at := 13
+	if len(src) < at {
+		return nil, ErrInvalidImage
+	}
+	if src[10]&0x80 != 0 {
+		at += 3 << (src[10]&0x07 + 1)
+	}
+	if at > len(src) {
+		return nil, ErrInvalidImage
+	}
+	out := make([]byte, 0, len(src))
+	out = append(out, src[:at]...)
+	for at < len(src) {
+		start := at
+		switch src[at] {
+		case 0x3b:
+			// trailer
+			return append(out, src[at:]...), nil
+		case 0x21:
+			if at+2 > len(src) {
+				return nil, ErrInvalidImage
+			}
+			xmp := src[at+1] == 0xff && bytes.HasPrefix(src[at+2:], gifXMPHeader)
+			end, err := skipGIFSubBlocks(src, at+2)
+			if err != nil {
+				return nil, err
+			}
+			if !xmp {
+				out = append(out, src[start:end]...)
+			}
+			at = end
+		case 0x2c:
+			// image descriptor, local color table and lzw code size
+			if at+11 > len(src) {
+				return nil, ErrInvalidImage
+			}
+			at += 10
+			if src[at-1]&0x80 != 0 {
+				at += 3 << (src[at-1]&0x07 + 1)
+			}
+			end, err := skipGIFSubBlocks(src, at+1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, src[start:end]...)
+			at = end
+		default:
+			return nil, ErrInvalidImage
+		}
+	}
+	// some writers leave the trailer out
+	return out, nil
+}
+
+// skipGIFSubBlocks returns the offset after the sub-blocks starting at at.
+func skipGIFSubBlocks(src []byte, at int) (int, error) {
+	for {
+		if at >= len(src) {
+			return 0, ErrInvalidImage
+		}
+		size := int(src[at])
+		at += 1 + size
+		if size == 0 {
+			return at, nil
+		}
+	}
+}
+
+func isWebP(src []byte) bool {
+	return len(src) >= 12 && string(src[:4]) == "RIFF" && string(src[8:12]) == "WEBP"
+}
+
+// eachWebPChunk calls fn with the chunks of a webp, data is a slice of src.
+func eachWebPChunk(src []byte, fn func(fourCC string, data []byte) bool) error {
+	for at := 12; at < len(src); {
+		if at+8 > len(src) {
+			return ErrInvalidImage
+		}
+		size := int(binary.LittleEndian.Uint32(src[at+4:]))
+		if size < 0 || at+8+size > len(src) {
+			return ErrInvalidImage
+		}
+		if !fn(string(src[at:at+4]), src[at+8:at+8+size]) {
+			return nil
+		}
+		// chunks are padded to an even size
+		at += 8 + size + size&1
+	}
+	return nil
+}
+
+func stripWebPLocation(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	out = append(out, src[:12]...)
+	flags := -1
+	removed := byte(0)
+	err := eachWebPChunk(src, func(fourCC string, data []byte) bool {
+		switch fourCC {
+		case "EXIF":
+			exif := bytes.Clone(data)
+			if _, err := StripExifGPS(bytes.TrimPrefix(exif, jpegExifHeader)); err != nil {
+				removed |= WEBP_FLAG_EXIF
+				return true
+			}
+			out = appendWebPChunk(out, fourCC, exif)
+		case "XMP ":
+			removed |= WEBP_FLAG_XMP
+		default:
+			if fourCC == "VP8X" && len(data) > 0 {
+				flags = len(out) + 8
+			}
+			out = appendWebPChunk(out, fourCC, data)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the extended header announces the chunks that follow
+	if flags >= 0 {
+		out[flags] &^= removed
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+func appendWebPChunk(out []byte, fourCC string, data []byte) []byte {
+	out = append(out, fourCC...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+	out = append(out, data...)
+	if len(data)&1 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
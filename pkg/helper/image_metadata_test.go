@@ -0,0 +1,166 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testXMP = `<x:xmpmeta><rdf:Description exif:GPSLatitude="48,51.243N"/></x:xmpmeta>`
+
+func TestImageLocation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+
+	t.Run("success jpeg", func(t *testing.T) {
+		encoded := &bytes.Buffer{}
+		assert.Nil(t, jpeg.Encode(encoded, src, nil))
+		segment := func(marker byte, payload []byte) []byte {
+			b := []byte{0xff, marker}
+			b = binary.BigEndian.AppendUint16(b, uint16(len(payload)+2))
+			return append(b, payload...)
+		}
+		photo := append([]byte{0xff, 0xd8}, segment(0xe1, append([]byte("Exif\x00\x00"), testExif(6)...))...)
+		photo = append(photo, segment(0xe1, []byte("http://ns.adobe.com/xap/1.0/\x00"+testXMP))...)
+		photo = append(photo, encoded.Bytes()[2:]...)
+
+		metadata := ReadImageMetadata(photo)
+		assert.Equal(t, 2, metadata.Width)
+		assert.Equal(t, 4, metadata.Height)
+		assert.Equal(t, "EOS 5D", metadata.Model)
+		assert.True(t, metadata.HasGPS)
+
+		stripped, err := StripImageLocation(photo)
+		assert.Nil(t, err)
+		_, err = jpeg.Decode(bytes.NewReader(stripped))
+		assert.Nil(t, err)
+		assert.NotContains(t, string(stripped), "GPSLatitude")
+		metadata = ReadImageMetadata(stripped)
+		assert.False(t, metadata.HasGPS)
+		assert.Equal(t, 6, metadata.Orientation)
+		assert.Equal(t, "EOS 5D", metadata.Model)
+	})
+
+	t.Run("success png", func(t *testing.T) {
+		encoded := &bytes.Buffer{}
+		assert.Nil(t, png.Encode(encoded, src))
+		// right after the header chunk
+		ihdrEnd := len(pngSignature) + 12 + 13
+		photo := append([]byte{}, encoded.Bytes()[:ihdrEnd]...)
+		photo = appendPNGChunk(photo, "eXIf", testExif(1))
+		photo = appendPNGChunk(photo, "iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00"+testXMP))
+		photo = appendPNGChunk(photo, "tEXt", []byte("Comment\x00kept"))
+		photo = append(photo, encoded.Bytes()[ihdrEnd:]...)
+		assert.True(t, ReadImageMetadata(photo).HasGPS)
+
+		stripped, err := StripImageLocation(photo)
+		assert.Nil(t, err)
+		_, err = png.Decode(bytes.NewReader(stripped))
+		assert.Nil(t, err)
+		assert.NotContains(t, string(stripped), "GPSLatitude")
+		assert.Contains(t, string(stripped), "Comment\x00kept")
+		metadata := ReadImageMetadata(stripped)
+		assert.False(t, metadata.HasGPS)
+		assert.Equal(t, 4, metadata.Width)
+		assert.Equal(t, "Canon", metadata.Make)
+	})
+
+	t.Run("success webp", func(t *testing.T) {
+		vp8x := []byte{WEBP_FLAG_EXIF | WEBP_FLAG_XMP, 0, 0, 0, 0x2b, 0x01, 0, 0xc7, 0, 0}
+		photo := []byte("RIFF\x00\x00\x00\x00WEBP")
+		photo = appendWebPChunk(photo, "VP8X", vp8x)
+		photo = appendWebPChunk(photo, "EXIF", testExif(1))
+		photo = appendWebPChunk(photo, "XMP ", []byte(testXMP))
+		binary.LittleEndian.PutUint32(photo[4:], uint32(len(photo)-8))
+
+		metadata := ReadImageMetadata(photo)
+		assert.Equal(t, 300, metadata.Width)
+		assert.Equal(t, 200, metadata.Height)
+		assert.True(t, metadata.HasGPS)
+
+		stripped, err := StripImageLocation(photo)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(stripped), "GPSLatitude")
+		assert.Equal(t, byte(WEBP_FLAG_EXIF), stripped[20])
+		assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:]))
+		assert.False(t, ReadImageMetadata(stripped).HasGPS)
+	})
+
+	t.Run("success gif", func(t *testing.T) {
+		palette := color.Palette{color.Black, color.White}
+		paletted := image.NewPaletted(image.Rect(0, 0, 4, 2), palette)
+		encoded := &bytes.Buffer{}
+		// two frames, so the netscape loop extension is written as well
+		assert.Nil(t, gif.EncodeAll(encoded, &gif.GIF{
+			Image:  []*image.Paletted{paletted, paletted},
+			Delay:  []int{10, 10},
+			Config: image.Config{ColorModel: palette, Width: 4, Height: 2},
+		}))
+		// the xmp packet ends with the trailer that closes its sub-blocks
+		xmp := append([]byte{0x21, 0xff}, gifXMPHeader...)
+		xmp = append(xmp, testXMP...)
+		xmp = append(xmp, 0x01)
+		for i := 0xff; i >= 0; i-- {
+			xmp = append(xmp, byte(i))
+		}
+		xmp = append(xmp, 0x00)
+		// after the header, the screen descriptor and the 2 color palette
+		photo := append(bytes.Clone(encoded.Bytes()[:19]), xmp...)
+		photo = append(photo, encoded.Bytes()[19:]...)
+		_, err := gif.DecodeAll(bytes.NewReader(photo))
+		assert.Nil(t, err)
+
+		stripped, err := StripImageLocation(photo)
+		assert.Nil(t, err)
+		assert.Equal(t, encoded.Bytes(), stripped)
+	})
+
+	t.Run("error truncated gif", func(t *testing.T) {
+		_, err := StripImageLocation([]byte("GIF89a\x04\x00\x02\x00\x00\x00\x00\x21\xff\x0b"))
+		assert.ErrorIs(t, err, ErrInvalidImage)
+	})
+
+	t.Run("error truncated jpeg", func(t *testing.T) {
+		_, err := StripImageLocation([]byte{0xff, 0xd8, 0xff, 0xe1, 0x10})
+		assert.ErrorIs(t, err, ErrInvalidImage)
+	})
+}
+
+func TestOrientImage(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, red)
+	src.SetRGBA(1, 0, blue)
+
+	testCases := []struct {
+		desc        string
+		orientation int
+		want        []color.RGBA
+		bounds      image.Rectangle
+	}{
+		{desc: "success normal", orientation: 1, want: []color.RGBA{red, blue}, bounds: image.Rect(0, 0, 2, 1)},
+		{desc: "success rotate 180", orientation: 3, want: []color.RGBA{blue, red}, bounds: image.Rect(0, 0, 2, 1)},
+		{desc: "success rotate 90 clockwise", orientation: 6, want: []color.RGBA{red, blue}, bounds: image.Rect(0, 0, 1, 2)},
+		{desc: "success rotate 90 counterclockwise", orientation: 8, want: []color.RGBA{blue, red}, bounds: image.Rect(0, 0, 1, 2)},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dst := OrientImage(src, tC.orientation)
+			assert.Equal(t, tC.bounds, dst.Bounds())
+			i := 0
+			for y := 0; y < tC.bounds.Dy(); y++ {
+				for x := 0; x < tC.bounds.Dx(); x++ {
+					assert.Equal(t, tC.want[i], color.RGBAModel.Convert(dst.At(x, y)))
+					i++
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeImage(t *testing.T) {
+	t.Run("success keeps the aspect ratio", func(t *testing.T) {
+		src := image.NewNRGBA(image.Rect(10, 10, 410, 310))
+		dst := ResizeImage(src, 100)
+		assert.Equal(t, image.Rect(0, 0, 100, 75), dst.Bounds())
+	})
+
+	t.Run("success averages covered pixels", func(t *testing.T) {
+		src := image.NewGray(image.Rect(0, 0, 4, 2))
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 2; y++ {
+				if x%2 == 0 {
+					src.SetGray(x, y, color.Gray{Y: 255})
+				}
+			}
+		}
+		dst := ResizeImage(src, 2)
+		assert.Equal(t, image.Rect(0, 0, 2, 1), dst.Bounds())
+		assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, dst.RGBAAt(0, 0))
+		assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, dst.RGBAAt(1, 0))
+	})
+
+	t.Run("success transparent pixels do not bleed", func(t *testing.T) {
+		src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+		src.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+		src.SetNRGBA(1, 0, color.NRGBA{G: 255, A: 0})
+		dst := ResizeImage(src, 1)
+		assert.Equal(t, color.RGBA{R: 128, A: 128}, dst.RGBAAt(0, 0))
+	})
+
+	t.Run("success non integer scale", func(t *testing.T) {
+		src := image.NewGray(image.Rect(0, 0, 3, 3))
+		for i := range src.Pix {
+			src.Pix[i] = 200
+		}
+		dst := ResizeImage(src, 2)
+		for x := 0; x < 2; x++ {
+			for y := 0; y < 2; y++ {
+				assert.Equal(t, color.RGBA{R: 200, G: 200, B: 200, A: 255}, dst.RGBAAt(x, y))
+			}
+		}
+	})
+}
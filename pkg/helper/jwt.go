@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// TokenError is returned by ValidateToken, Code is stable and safe to send
+// back to clients so they can tell an expired token from a wrong one.
+type TokenError struct {
+	Code    string
+	Message string
+}
+
+func (e *TokenError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrTokenMalformed        = &TokenError{Code: "token_malformed", Message: "token is malformed"}
+	ErrTokenSignatureInvalid = &TokenError{Code: "token_signature_invalid", Message: "token signature is invalid"}
+	ErrTokenExpired          = &TokenError{Code: "token_expired", Message: "token is expired"}
+	ErrTokenNotYetValid      = &TokenError{Code: "token_not_yet_valid", Message: "token is not valid yet"}
+	ErrTokenWrongIssuer      = &TokenError{Code: "token_wrong_issuer", Message: "token has wrong issuer"}
+	ErrTokenWrongAudience    = &TokenError{Code: "token_wrong_audience", Message: "token has wrong audience"}
+	ErrTokenWrongType        = &TokenError{Code: "token_wrong_type", Message: "token has wrong type"}
+)
+
+// TokenExpectation describes which tokens a route group accepts. Empty
+// fields are not checked. Leeway is the clock skew tolerated on exp, nbf and
+// iat.
+type TokenExpectation struct {
+	Issuer   string
+	Audience string
+	Subject  string
+	Leeway   time.Duration
+}
+
+func (e TokenExpectation) validate(claim jwt.MapClaims, now time.Time) error {
+	exp, ok := numericClaim(claim, "exp")
+	if !ok {
+		return ErrTokenMalformed
+	}
+	if now.After(time.Unix(exp, 0).Add(e.Leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := numericClaim(claim, "nbf"); ok && now.Add(e.Leeway).Before(time.Unix(nbf, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if iat, ok := numericClaim(claim, "iat"); ok && now.Add(e.Leeway).Before(time.Unix(iat, 0)) {
+		return ErrTokenNotYetValid
+	}
+
+	if e.Issuer != "" {
+		if iss, _ := claim["iss"].(string); iss != e.Issuer {
+			return ErrTokenWrongIssuer
+		}
+	}
+	if e.Audience != "" && !hasAudience(claim["aud"], e.Audience) {
+		return ErrTokenWrongAudience
+	}
+	if e.Subject != "" {
+		if sub, _ := claim["sub"].(string); sub != e.Subject {
+			return ErrTokenWrongType
+		}
+	}
+	return nil
+}
+
+func numericClaim(claim jwt.MapClaims, key string) (int64, bool) {
+	switch v := claim[key].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// hasAudience accepts both the single string and the array form of aud.
+func hasAudience(aud any, expected string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == expected
+	case []any:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
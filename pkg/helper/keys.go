@@ -0,0 +1,225 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"mygram/model"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrTokenUnknownKey = &TokenError{Code: "token_unknown_key", Message: "token is signed with an unknown or retired key"}
+
+// SigningKey is one key of a KeySet. Keys without ID are only used for the
+// legacy HS512 secret and are never published.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	Private    any
+	Public     any
+	ActiveFrom time.Time
+}
+
+// KeySet holds every key tokens may be signed with. The newest key whose
+// ActiveFrom has passed signs new tokens; the key it replaced is still
+// accepted for the overlap window so tokens signed just before a rotation
+// stay valid until they expire.
+type KeySet struct {
+	keys    []SigningKey
+	overlap time.Duration
+}
+
+func NewKeySet(keys []SigningKey, overlap time.Duration) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key set needs at least one key")
+	}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("every key needs an id")
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate key id %s", key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	sorted := append([]SigningKey{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+	return &KeySet{keys: sorted, overlap: overlap}, nil
+}
+
+// NewHMACKeySet keeps the single shared secret setup working.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{keys: []SigningKey{{
+		Method:  jwt.SigningMethodHS512,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}}}
+}
+
+// SigningKey returns the key new tokens are signed with at now.
+func (k *KeySet) SigningKey(now time.Time) (SigningKey, error) {
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].ActiveFrom.After(now) {
+			return k.keys[i], nil
+		}
+	}
+	return SigningKey{}, errors.New("no signing key is active yet")
+}
+
+// VerificationKey returns the key identified by kid if tokens signed with it
+// are still accepted at now.
+func (k *KeySet) VerificationKey(kid string, now time.Time) (SigningKey, error) {
+	for i, key := range k.keys {
+		if key.ID != kid {
+			continue
+		}
+		if key.ActiveFrom.After(now) {
+			return SigningKey{}, ErrTokenUnknownKey
+		}
+		// retired keys are accepted until the overlap window is over
+		if i+1 < len(k.keys) && !k.keys[i+1].ActiveFrom.After(now) && now.After(k.keys[i+1].ActiveFrom.Add(k.overlap)) {
+			return SigningKey{}, ErrTokenUnknownKey
+		}
+		return key, nil
+	}
+	return SigningKey{}, ErrTokenUnknownKey
+}
+
+// PublicKeys returns the asymmetric keys verifiers should know about at now:
+// the keys still accepted and the ones scheduled to become active.
+func (k *KeySet) PublicKeys(now time.Time) []SigningKey {
+	keys := []SigningKey{}
+	for _, key := range k.keys {
+		if key.ID == "" {
+			continue
+		}
+		if !key.ActiveFrom.After(now) {
+			if _, err := k.VerificationKey(key.ID, now); err != nil {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// LoadSigningKey reads a PKCS#8 or PKCS#1 private key from a PEM file. RSA
+// keys sign with RS256 and Ed25519 keys with EdDSA.
+func LoadSigningKey(id, path string, activeFrom time.Time) (SigningKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	var private any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported PEM block %s in %s", block.Type, path)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("cannot parse private key %s: %w", path, err)
+	}
+	return NewSigningKey(id, private, activeFrom)
+}
+
+func NewSigningKey(id string, private any, activeFrom time.Time) (SigningKey, error) {
+	key := SigningKey{ID: id, Private: private, ActiveFrom: activeFrom}
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return SigningKey{}, fmt.Errorf("rsa key %s must be at least 2048 bits", id)
+		}
+		key.Method = jwt.SigningMethodRS256
+		key.Public = &k.PublicKey
+	case ed25519.PrivateKey:
+		key.Method = SigningMethodEdDSA
+		key.Public = k.Public()
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported key type %T for key %s", private, id)
+	}
+	return key, nil
+}
+
+// JWK converts the public part of the key for the JWKS endpoint.
+func (k SigningKey) JWK() model.JSONWebKey {
+	jwk := model.JSONWebKey{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// SigningMethodEdDSA implements Ed25519 signatures, jwt-go v3 ships without it.
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+type signingMethodEdDSA struct{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	sig, err := signer.Sign(nil, []byte(signingString), crypto.Hash(0))
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
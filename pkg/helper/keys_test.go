@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySet(t *testing.T) {
+	now := time.Now()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	oldKey, err := NewSigningKey("old", rsaKey, now.Add(-48*time.Hour))
+	assert.Nil(t, err)
+	newKey, err := NewSigningKey("new", edKey, now.Add(-time.Hour))
+	assert.Nil(t, err)
+	nextKey, err := NewSigningKey("next", rsaKey, now.Add(time.Hour))
+	assert.Nil(t, err)
+
+	expect := TokenExpectation{Subject: "access-token"}
+	claim := map[string]any{"sub": "access-token", "exp": now.Add(time.Hour).Unix()}
+
+	t.Run("sign with newest active key", func(t *testing.T) {
+		keys, err := NewKeySet([]SigningKey{nextKey, oldKey, newKey}, 2*time.Hour)
+		assert.Nil(t, err)
+
+		key, err := keys.SigningKey(now)
+		assert.Nil(t, err)
+		assert.Equal(t, "new", key.ID)
+
+		token, err := GenerateToken(claim, keys)
+		assert.Nil(t, err)
+		_, err = ValidateToken(token, keys, expect)
+		assert.Nil(t, err)
+	})
+
+	t.Run("accept replaced key within overlap", func(t *testing.T) {
+		signing, err := NewKeySet([]SigningKey{oldKey}, 2*time.Hour)
+		assert.Nil(t, err)
+		token, err := GenerateToken(claim, signing)
+		assert.Nil(t, err)
+
+		rotated, err := NewKeySet([]SigningKey{oldKey, newKey}, 2*time.Hour)
+		assert.Nil(t, err)
+		_, err = ValidateToken(token, rotated, expect)
+		assert.Nil(t, err)
+
+		retired, err := NewKeySet([]SigningKey{oldKey, newKey}, 30*time.Minute)
+		assert.Nil(t, err)
+		_, err = ValidateToken(token, retired, expect)
+		assert.ErrorIs(t, err, ErrTokenUnknownKey)
+	})
+
+	t.Run("reject hmac token when keys are asymmetric", func(t *testing.T) {
+		token, err := GenerateToken(claim, NewHMACKeySet("a-very-long-secret-used-for-testing-only"))
+		assert.Nil(t, err)
+
+		keys, err := NewKeySet([]SigningKey{newKey}, time.Hour)
+		assert.Nil(t, err)
+		_, err = ValidateToken(token, keys, expect)
+		assert.ErrorIs(t, err, ErrTokenUnknownKey)
+	})
+
+	t.Run("publish active and scheduled keys only", func(t *testing.T) {
+		keys, err := NewKeySet([]SigningKey{oldKey, newKey, nextKey}, 30*time.Minute)
+		assert.Nil(t, err)
+
+		published := []string{}
+		for _, key := range keys.PublicKeys(now) {
+			published = append(published, key.JWK().Kid)
+		}
+		assert.Equal(t, []string{"new", "next"}, published)
+		assert.Equal(t, "OKP", newKey.JWK().Kty)
+		assert.Equal(t, "RSA", nextKey.JWK().Kty)
+	})
+}
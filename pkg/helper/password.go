@@ -0,0 +1,134 @@
+package helper
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BCRYPT_MAX_BYTES is the most bcrypt looks at, longer passwords would be
+// silently truncated so they are rejected instead.
+const BCRYPT_MAX_BYTES = 72
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = func() map[string]bool {
+	passwords := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			passwords[line] = true
+		}
+	}
+	return passwords
+}()
+
+// PasswordPolicyError lists every rule a password breaks.
+type PasswordPolicyError struct {
+	Errors []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("invalid password: %s", strings.Join(e.Errors, "; "))
+}
+
+type PasswordPolicy struct {
+	MinLength     int
+	MaxBytes      int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectCommon  bool
+	// Cost is the bcrypt cost new hashes are made with.
+	Cost int
+}
+
+// DefaultPasswordPolicy is used where no configuration is available.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	MaxBytes:     BCRYPT_MAX_BYTES,
+	RejectCommon: true,
+	Cost:         bcrypt.DefaultCost,
+}
+
+func (p PasswordPolicy) Validate(password string) error {
+	errs := []string{}
+	if len([]rune(password)) < p.MinLength {
+		errs = append(errs, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 || maxBytes > BCRYPT_MAX_BYTES {
+		maxBytes = BCRYPT_MAX_BYTES
+	}
+	if len(password) > maxBytes {
+		errs = append(errs, fmt.Sprintf("must be at most %d bytes", maxBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		errs = append(errs, "must contain an upper case letter")
+	}
+	if p.RequireLower && !lower {
+		errs = append(errs, "must contain a lower case letter")
+	}
+	if p.RequireDigit && !digit {
+		errs = append(errs, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		errs = append(errs, "must contain a symbol")
+	}
+	if p.RejectCommon && commonPasswords[strings.ToLower(password)] {
+		errs = append(errs, "is too common")
+	}
+
+	if len(errs) > 0 {
+		return &PasswordPolicyError{Errors: errs}
+	}
+	return nil
+}
+
+// Hash hashes password with the policy cost.
+func (p PasswordPolicy) Hash(password string) (string, error) {
+	cost := p.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// NeedsRehash tells if hash was made with another cost than the policy's,
+// so it can be replaced the next time the plain password is known.
+func (p PasswordPolicy) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	want := p.Cost
+	if want == 0 {
+		want = bcrypt.DefaultCost
+	}
+	return cost != want
+}
@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:     10,
+		MaxBytes:      BCRYPT_MAX_BYTES,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		RejectCommon:  true,
+		Cost:          4,
+	}
+
+	testCases := []struct {
+		desc     string
+		password string
+		errs     int
+	}{
+		{desc: "success", password: "Correct-Horse-7"},
+		{desc: "error too short", password: "Sh0rt!", errs: 1},
+		{desc: "error too long", password: "A1!" + strings.Repeat("a", 70), errs: 1},
+		{desc: "error missing classes", password: "alllowercase", errs: 3},
+		{desc: "error common", password: "Password123", errs: 2},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := policy.Validate(tC.password)
+			if tC.errs == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			var policyErr *PasswordPolicyError
+			assert.ErrorAs(t, err, &policyErr)
+			assert.Equal(t, tC.errs, len(policyErr.Errors), policyErr.Errors)
+		})
+	}
+
+	t.Run("success needs rehash", func(t *testing.T) {
+		hash, err := policy.Hash("Correct-Horse-7")
+		assert.Nil(t, err)
+		assert.False(t, policy.NeedsRehash(hash))
+
+		policy.Cost = 5
+		assert.True(t, policy.NeedsRehash(hash))
+	})
+}
@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 appendix B, SHA1 seed, last 6 of the 8 digits
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tC := range testCases {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(tC.unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, tC.code, code, tC.unix)
+	}
+
+	t.Run("success within skew", func(t *testing.T) {
+		now := time.Unix(1111111109, 0)
+		step, ok := ValidateTOTP(secret, "081804", now.Add(TOTP_PERIOD), 1)
+		assert.True(t, ok)
+		assert.Equal(t, TOTPStep(now), step)
+	})
+
+	t.Run("error outside skew", func(t *testing.T) {
+		_, ok := ValidateTOTP(secret, "081804", time.Unix(1111111109, 0).Add(2*TOTP_PERIOD), 1)
+		assert.False(t, ok)
+	})
+
+	t.Run("success uri", func(t *testing.T) {
+		uri := TOTPURI("mygram", "user@mail.com", secret)
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/mygram:user@mail.com?"))
+		assert.Contains(t, uri, "secret="+secret)
+	})
+}
@@ -0,0 +1,272 @@
+package helper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math/bits"
+	"slices"
+)
+
+const (
+	// WEBP_MAX_SIZE is the largest width and height of a lossless webp.
+	WEBP_MAX_SIZE = 1 << 14
+
+	webpLosslessSignature = 0x2f
+	// prefix codes are at most 15 bits, the code of their lengths 7
+	webpMaxCodeLength       = 15
+	webpMaxLengthCodeLength = 7
+	// the green code also holds the 24 backward reference lengths
+	webpGreenAlphabetSize = 256 + 24
+)
+
+// webpCodeLengthOrder is the order the lengths of the code length code are
+// written in.
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebP writes img as a lossless WebP. Every pixel is written as a
+// literal with prefix codes fitted to the image, there are no transforms
+// nor backward references, so the files are larger than the ones of
+// libwebp but any decoder reads them.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > WEBP_MAX_SIZE || height > WEBP_MAX_SIZE {
+		return fmt.Errorf("webp: cannot encode a %dx%d image", width, height)
+	}
+	// webp keeps colors without premultiplied alpha
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+
+	// green, red, blue and alpha, in the order of the prefix codes
+	histograms := [4][]int{make([]int, webpGreenAlphabetSize), make([]int, 256), make([]int, 256), make([]int, 256)}
+	opaque := true
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		histograms[0][nrgba.Pix[i+1]]++
+		histograms[1][nrgba.Pix[i]]++
+		histograms[2][nrgba.Pix[i+2]]++
+		histograms[3][nrgba.Pix[i+3]]++
+		opaque = opaque && nrgba.Pix[i+3] == 0xff
+	}
+
+	bw := &bitWriter{}
+	bw.write(webpLosslessSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if opaque {
+		bw.write(0, 1)
+	} else {
+		bw.write(1, 1)
+	}
+	// version
+	bw.write(0, 3)
+	// no transform, no color cache and a single group of prefix codes
+	bw.write(0, 1)
+	bw.write(0, 1)
+	bw.write(0, 1)
+
+	codes := [4]prefixCode{}
+	for i, histogram := range histograms {
+		codes[i] = writePrefixCode(bw, histogram)
+	}
+	// the distance code is never used, one symbol of zero bits
+	writePrefixCode(bw, []int{0})
+
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		codes[0].write(bw, nrgba.Pix[i+1])
+		codes[1].write(bw, nrgba.Pix[i])
+		codes[2].write(bw, nrgba.Pix[i+2])
+		codes[3].write(bw, nrgba.Pix[i+3])
+	}
+
+	out := []byte("RIFF\x00\x00\x00\x00WEBP")
+	out = appendWebPChunk(out, "VP8L", bw.bytes())
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	_, err := w.Write(out)
+	return err
+}
+
+// bitWriter packs values from their lowest bit, as webp reads them.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (b *bitWriter) write(value uint32, n uint) {
+	b.acc |= uint64(value) << b.nbits
+	b.nbits += n
+	for b.nbits >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.nbits -= 8
+	}
+}
+
+func (b *bitWriter) bytes() []byte {
+	if b.nbits > 0 {
+		return append(b.buf, byte(b.acc))
+	}
+	return b.buf
+}
+
+type prefixCode struct {
+	lengths []uint8
+	// codes are bit reversed, a prefix code is read from its first bit
+	codes []uint32
+}
+
+func (p prefixCode) write(bw *bitWriter, symbol byte) {
+	bw.write(p.codes[symbol], uint(p.lengths[symbol]))
+}
+
+// writePrefixCode writes the code fitted to histogram and returns it.
+func writePrefixCode(bw *bitWriter, histogram []int) prefixCode {
+	used := []int{}
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	lengths := make([]uint8, len(histogram))
+	if len(used) < 2 {
+		// a simple code of one symbol takes no bits
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		bw.write(1, 1)
+		bw.write(0, 1)
+		if symbol < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbol), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbol), 8)
+		}
+		return prefixCode{lengths: lengths, codes: make([]uint32, len(histogram))}
+	}
+
+	huffmanLengths(histogram, lengths, webpMaxCodeLength)
+
+	// the lengths are written with a code of their own
+	lengthHistogram := make([]int, len(webpCodeLengthOrder))
+	for _, length := range lengths {
+		lengthHistogram[length]++
+	}
+	// some decoders do not take a code of a single symbol
+	for symbol := 0; countUsed(lengthHistogram) < 2; symbol++ {
+		if lengthHistogram[symbol] == 0 {
+			lengthHistogram[symbol] = 1
+		}
+	}
+	lengthLengths := make([]uint8, len(lengthHistogram))
+	huffmanLengths(lengthHistogram, lengthLengths, webpMaxLengthCodeLength)
+	lengthCodes := canonicalCodes(lengthLengths)
+
+	n := len(webpCodeLengthOrder)
+	for n > 4 && lengthLengths[webpCodeLengthOrder[n-1]] == 0 {
+		n--
+	}
+	bw.write(0, 1)
+	bw.write(uint32(n-4), 4)
+	for _, symbol := range webpCodeLengthOrder[:n] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	// a length for every symbol of the alphabet
+	bw.write(0, 1)
+	for _, length := range lengths {
+		bw.write(lengthCodes[length], uint(lengthLengths[length]))
+	}
+	return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+}
+
+func countUsed(histogram []int) int {
+	used := 0
+	for _, count := range histogram {
+		if count > 0 {
+			used++
+		}
+	}
+	return used
+}
+
+// huffmanLengths sets the code lengths of a huffman code of histogram, of
+// at least two symbols. Rare symbols are counted as more frequent until the
+// code fits in maxLength bits.
+func huffmanLengths(histogram []int, lengths []uint8, maxLength int) {
+	weights := slices.Clone(histogram)
+	for floor := 2; huffmanDepths(weights, lengths) > maxLength; floor *= 2 {
+		for i, weight := range weights {
+			if weight > 0 && weight < floor {
+				weights[i] = floor
+			}
+		}
+	}
+}
+
+// huffmanDepths sets the depth of every symbol in a huffman tree of weights
+// and returns the deepest.
+func huffmanDepths(weights []int, lengths []uint8) int {
+	type node struct {
+		weight      int
+		symbol      int
+		left, right int
+	}
+	nodes := []node{}
+	active := []int{}
+	for symbol, weight := range weights {
+		if weight > 0 {
+			nodes = append(nodes, node{weight: weight, symbol: symbol, left: -1, right: -1})
+			active = append(active, len(nodes)-1)
+		}
+	}
+	for len(active) > 1 {
+		slices.SortStableFunc(active, func(a, b int) int { return nodes[a].weight - nodes[b].weight })
+		nodes = append(nodes, node{weight: nodes[active[0]].weight + nodes[active[1]].weight, symbol: -1, left: active[0], right: active[1]})
+		active = append(active[2:], len(nodes)-1)
+	}
+
+	clear(lengths)
+	deepest := 0
+	var walk func(at, depth int)
+	walk = func(at, depth int) {
+		if nodes[at].symbol >= 0 {
+			lengths[nodes[at].symbol] = uint8(depth)
+			deepest = max(deepest, depth)
+			return
+		}
+		walk(nodes[at].left, depth+1)
+		walk(nodes[at].right, depth+1)
+	}
+	walk(active[0], 0)
+	return deepest
+}
+
+// canonicalCodes gives the codes of the symbols, shorter codes first and
+// in the order of the symbols for the same length, bit reversed.
+func canonicalCodes(lengths []uint8) []uint32 {
+	counts := make([]uint32, webpMaxCodeLength+1)
+	for _, length := range lengths {
+		counts[length]++
+	}
+	counts[0] = 0
+	next := make([]uint32, webpMaxCodeLength+1)
+	code := uint32(0)
+	for length := 1; length <= webpMaxCodeLength; length++ {
+		code = (code + counts[length-1]) << 1
+		next[length] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		codes[symbol] = bits.Reverse32(next[length]) >> (32 - length)
+		next[length]++
+	}
+	return codes
+}
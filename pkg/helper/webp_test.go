@@ -0,0 +1,100 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	testCases := []struct {
+		desc string
+		img  func() image.Image
+	}{
+		{
+			desc: "success gradient with alpha",
+			img: func() image.Image {
+				img := image.NewNRGBA(image.Rect(0, 0, 67, 33))
+				for y := 0; y < 33; y++ {
+					for x := 0; x < 67; x++ {
+						img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 3), G: uint8(y * 7), B: uint8(x * y), A: uint8(255 - x)})
+					}
+				}
+				return img
+			},
+		},
+		{
+			desc: "success single color",
+			img: func() image.Image {
+				img := image.NewRGBA(image.Rect(0, 0, 5, 3))
+				for i := range img.Pix {
+					img.Pix[i] = 0xff
+				}
+				return img
+			},
+		},
+		{
+			desc: "success offset bounds",
+			img: func() image.Image {
+				img := image.NewGray(image.Rect(10, 20, 14, 22))
+				for i := range img.Pix {
+					img.Pix[i] = uint8(i * 30)
+				}
+				return img
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			src := tC.img()
+			encoded := &bytes.Buffer{}
+			assert.Nil(t, EncodeWebP(encoded, src))
+
+			cfg, err := webp.DecodeConfig(bytes.NewReader(encoded.Bytes()))
+			assert.Nil(t, err)
+			assert.Equal(t, src.Bounds().Dx(), cfg.Width)
+			assert.Equal(t, src.Bounds().Dy(), cfg.Height)
+
+			decoded, err := webp.Decode(bytes.NewReader(encoded.Bytes()))
+			assert.Nil(t, err)
+			bounds := src.Bounds()
+			for y := 0; y < bounds.Dy(); y++ {
+				for x := 0; x < bounds.Dx(); x++ {
+					want := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y))
+					if !assert.Equal(t, want, color.NRGBAModel.Convert(decoded.At(x, y)), "pixel %d,%d", x, y) {
+						return
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("error too large", func(t *testing.T) {
+		err := EncodeWebP(&bytes.Buffer{}, image.NewGray(image.Rect(0, 0, WEBP_MAX_SIZE+1, 1)))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestHuffmanLengths(t *testing.T) {
+	// fibonacci counts make the deepest trees
+	histogram := make([]int, 40)
+	histogram[0], histogram[1] = 1, 1
+	for i := 2; i < len(histogram); i++ {
+		histogram[i] = histogram[i-1] + histogram[i-2]
+	}
+	lengths := make([]uint8, len(histogram))
+	huffmanLengths(histogram, lengths, webpMaxCodeLength)
+
+	// the code is complete, no prefix is left over
+	kraft := 0
+	for _, length := range lengths {
+		assert.LessOrEqual(t, length, uint8(webpMaxCodeLength))
+		assert.Greater(t, length, uint8(0))
+		kraft += 1 << (webpMaxCodeLength - length)
+	}
+	assert.Equal(t, 1<<webpMaxCodeLength, kraft)
+}
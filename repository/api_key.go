@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyQuery interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	// GetAPIKeyByPrefix returns the zero value when no key has the prefix.
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	// GetAPIKeysByUser lists the keys of the user that were not revoked.
+	GetAPIKeysByUser(ctx context.Context, userID uint64) ([]model.APIKey, error)
+	// RevokeAPIKey returns false when the user has no such active key.
+	RevokeAPIKey(ctx context.Context, userID, id uint64) (bool, error)
+	RevokeAPIKeysByUser(ctx context.Context, userID uint64) error
+	// TouchAPIKey sets last_used_at unless it was set less than gap ago, so
+	// busy keys do not write on every request.
+	TouchAPIKey(ctx context.Context, id uint64, usedAt time.Time, gap time.Duration) error
+}
+
+type apiKeyQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewAPIKeyQuery(db infrastructure.GormPostgres) APIKeyQuery {
+	return &apiKeyQueryImpl{db: db}
+}
+
+func (a *apiKeyQueryImpl) CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	db := a.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("api_keys").
+		Create(&key).Error; err != nil {
+		return model.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (a *apiKeyQueryImpl) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	db := a.db.GetConnection()
+	key := model.APIKey{}
+	if err := db.
+		WithContext(ctx).
+		Table("api_keys").
+		Where("prefix = ?", prefix).
+		First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.APIKey{}, nil
+		}
+		return model.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (a *apiKeyQueryImpl) GetAPIKeysByUser(ctx context.Context, userID uint64) ([]model.APIKey, error) {
+	db := a.db.GetConnection()
+	keys := []model.APIKey{}
+	if err := db.
+		WithContext(ctx).
+		Table("api_keys").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (a *apiKeyQueryImpl) RevokeAPIKey(ctx context.Context, userID, id uint64) (bool, error) {
+	db := a.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("api_keys").
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (a *apiKeyQueryImpl) RevokeAPIKeysByUser(ctx context.Context, userID uint64) error {
+	db := a.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("api_keys").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+func (a *apiKeyQueryImpl) TouchAPIKey(ctx context.Context, id uint64, usedAt time.Time, gap time.Duration) error {
+	db := a.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("api_keys").
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-gap)).
+		Update("last_used_at", usedAt).
+		Error
+}
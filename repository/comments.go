@@ -16,6 +16,10 @@ type CommentsQuery interface {
 	UpdateComment(ctx context.Context, currentComment, newComment *model.Comments) (*model.Comments, error)
 	DeleteComment(ctx context.Context, comment *model.Comments) error
 	FindCommentByID(ctx context.Context, id int) (*model.Comments, error)
+	// GetCommentsByPhotoID returns up to limit comments of a photo, oldest
+	// first, starting after the cursor when there is one. Comments of
+	// deleted users are left out.
+	GetCommentsByPhotoID(ctx context.Context, photoID int, after *model.CommentCursor, limit int) ([]model.PhotoComment, error)
 }
 
 type CommentsCommand interface {
@@ -92,3 +96,25 @@ func (c *commentsQueryImpl) FindCommentByID(ctx context.Context, id int) (*model
 
 	return comment, nil
 }
+
+func (c *commentsQueryImpl) GetCommentsByPhotoID(ctx context.Context, photoID int, after *model.CommentCursor, limit int) ([]model.PhotoComment, error) {
+	db := c.db.GetConnection()
+	comments := []model.PhotoComment{}
+
+	query := db.
+		WithContext(ctx).
+		Table("comments").
+		Select("comments.id, comments.message, comments.user_id, users.username, comments.created_at, comments.updated_at").
+		Joins("JOIN users ON users.id = comments.user_id AND users.deleted_at IS NULL").
+		Where("comments.photo_id = ? AND comments.deleted_at IS NULL", photoID)
+	if after != nil {
+		query = query.Where("(comments.created_at, comments.id) > (?::timestamp, ?::int)", after.CreatedAt, after.ID)
+	}
+	if err := query.
+		Order("comments.created_at, comments.id").
+		Limit(limit).
+		Scan(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"mygram/infrastructure/mocks"
+	"mygram/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommentsByPhotoID(t *testing.T) {
+	t.Run("success first page", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT comments.id, comments.message, comments.user_id, users.username, comments.created_at, comments.updated_at FROM "comments" JOIN users ON users.id = comments.user_id AND users.deleted_at IS NULL WHERE comments.photo_id = $1 AND comments.deleted_at IS NULL ORDER BY comments.created_at, comments.id LIMIT $2`)).
+			WithArgs(10, 21).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "message", "username"}).AddRow(1, "nice", "commenter"))
+
+		commentRepo := commentsQueryImpl{db: postgresMock}
+		comments, err := commentRepo.GetCommentsByPhotoID(context.Background(), 10, nil, 21)
+		assert.Nil(t, err)
+		assert.Equal(t, []model.PhotoComment{{ID: 1, Message: "nice", Username: "commenter"}}, comments)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success after cursor", func(t *testing.T) {
+		cursor := model.CommentCursor{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 9}
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(regexp.QuoteMeta(`AND (comments.created_at, comments.id) > ($2::timestamp, $3::int) ORDER BY`)).
+			WithArgs(10, cursor.CreatedAt, 9, 21).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		commentRepo := commentsQueryImpl{db: postgresMock}
+		comments, err := commentRepo.GetCommentsByPhotoID(context.Background(), 10, &cursor, 21)
+		assert.Nil(t, err)
+		assert.Empty(t, comments)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
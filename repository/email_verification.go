@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerificationQuery interface {
+	CreateEmailVerification(ctx context.Context, verification model.EmailVerification) error
+	// GetLatestEmailVerification returns the zero value when the user never
+	// got a verification mail.
+	GetLatestEmailVerification(ctx context.Context, userID uint64) (model.EmailVerification, error)
+	// UseEmailVerification returns false when the token is unknown, expired
+	// or already used.
+	UseEmailVerification(ctx context.Context, jti string) (bool, error)
+	DeleteUnusedEmailVerifications(ctx context.Context, userID uint64) error
+}
+
+type emailVerificationQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewEmailVerificationQuery(db infrastructure.GormPostgres) EmailVerificationQuery {
+	return &emailVerificationQueryImpl{db: db}
+}
+
+func (e *emailVerificationQueryImpl) CreateEmailVerification(ctx context.Context, verification model.EmailVerification) error {
+	db := e.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("email_verifications").
+		Create(&verification).
+		Error
+}
+
+func (e *emailVerificationQueryImpl) GetLatestEmailVerification(ctx context.Context, userID uint64) (model.EmailVerification, error) {
+	db := e.db.GetConnection()
+	verification := model.EmailVerification{}
+	if err := db.
+		WithContext(ctx).
+		Table("email_verifications").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&verification).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.EmailVerification{}, nil
+		}
+		return model.EmailVerification{}, err
+	}
+	return verification, nil
+}
+
+func (e *emailVerificationQueryImpl) UseEmailVerification(ctx context.Context, jti string) (bool, error) {
+	db := e.db.GetConnection()
+	now := time.Now()
+	res := db.
+		WithContext(ctx).
+		Table("email_verifications").
+		Where("jti = ? AND used_at IS NULL AND expires_at > ?", jti, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (e *emailVerificationQueryImpl) DeleteUnusedEmailVerifications(ctx context.Context, userID uint64) error {
+	db := e.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("email_verifications").
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Delete(&model.EmailVerification{}).
+		Error
+}
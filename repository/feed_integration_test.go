@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/migrations"
+	"mygram/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testPostgres hands the repositories a connection to a throwaway schema.
+type testPostgres struct {
+	db *gorm.DB
+}
+
+func (t testPostgres) GetConnection() *gorm.DB {
+	return t.db
+}
+
+// newTestPostgres migrates a fresh schema on the database in
+// MYGRAM_TEST_DATABASE_DSN and skips the test when it is not set.
+func newTestPostgres(t *testing.T) testPostgres {
+	dsn := os.Getenv("MYGRAM_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("MYGRAM_TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.Nil(t, err)
+	sqlDB, err := db.DB()
+	require.Nil(t, err)
+	// search_path belongs to the session, keep a single one
+	sqlDB.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("mygram_test_%d", time.Now().UnixNano())
+	require.Nil(t, db.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		sqlDB.Close()
+	})
+	require.Nil(t, db.Exec("SET search_path TO "+schema).Error)
+
+	migrator, err := migrations.NewMigrator(sqlDB, migrations.FS)
+	require.Nil(t, err)
+	_, err = migrator.Up(context.Background())
+	require.Nil(t, err)
+	return testPostgres{db: db}
+}
+
+func TestGetFeedIntegration(t *testing.T) {
+	pg := newTestPostgres(t)
+	db := pg.GetConnection()
+	ctx := context.Background()
+
+	// 1000 users with 100 photos each, user 1 follows users 2 to 101 and
+	// user 101 is deleted. created_at repeats so ids have to break ties.
+	require.Nil(t, db.Exec(`INSERT INTO users (username, email, password)
+		SELECT 'user' || n, 'user' || n || '@mail.com', 'x' FROM generate_series(1, 1000) n`).Error)
+	require.Nil(t, db.Exec(`UPDATE users SET deleted_at = now() WHERE id = 101`).Error)
+	require.Nil(t, db.Exec(`INSERT INTO follows (follower_id, followee_id)
+		SELECT 1, n FROM generate_series(2, 101) n`).Error)
+	require.Nil(t, db.Exec(`INSERT INTO photos (title, url, user_id, created_at)
+		SELECT 'photo', 'https://mygram.io/p.jpg', u, timestamp '2024-01-01' + ((u * 7 + n) % 500) * interval '1 minute'
+		FROM generate_series(1, 1000) u, generate_series(1, 100) n`).Error)
+	require.Nil(t, db.Exec(`UPDATE photos SET deleted_at = now() WHERE user_id = 2 AND id % 2 = 0`).Error)
+	require.Nil(t, db.Exec(`ANALYZE`).Error)
+
+	expected := []int{}
+	require.Nil(t, db.Raw(`SELECT p.id FROM photos p JOIN users u ON u.id = p.user_id
+		WHERE p.deleted_at IS NULL AND u.deleted_at IS NULL
+			AND (p.user_id = 1 OR p.user_id IN (SELECT followee_id FROM follows WHERE follower_id = 1))
+		ORDER BY p.created_at DESC, p.id DESC`).Scan(&expected).Error)
+	require.Equal(t, 100*100-50, len(expected))
+
+	t.Run("success plan reads photos by author", func(t *testing.T) {
+		cursor := model.FeedCursor{CreatedAt: time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC), ID: 50000}
+		query, args := feedQuery(1, &cursor, 21)
+		rows := []string{}
+		require.Nil(t, db.Raw("EXPLAIN "+query, args...).Scan(&rows).Error)
+
+		plan := strings.Join(rows, "\n")
+		assert.Contains(t, plan, "idx_photos_user_id_created_at")
+		assert.NotContains(t, plan, "Seq Scan on photos")
+	})
+
+	t.Run("success pages are stable under inserts", func(t *testing.T) {
+		photoRepo := photoQueryImpl{db: pg}
+		got := []int{}
+		var after *model.FeedCursor
+		for page := 0; ; page++ {
+			photos, err := photoRepo.GetFeed(ctx, 1, after, 50)
+			require.Nil(t, err)
+			for _, photo := range photos {
+				got = append(got, photo.ID)
+			}
+			if len(photos) < 50 {
+				break
+			}
+			last := photos[len(photos)-1]
+			after = &model.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+
+			// new photos land before the cursor and must not shift the pages
+			if page%10 == 0 {
+				require.Nil(t, db.Exec(`INSERT INTO photos (title, url, user_id, created_at)
+					SELECT 'new', 'https://mygram.io/n.jpg', n, now() FROM generate_series(1, 20) n`).Error)
+			}
+		}
+		assert.Equal(t, expected, got)
+	})
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/infrastructure/mocks"
+	"mygram/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFeed(t *testing.T) {
+	t.Run("success first page", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(`WHERE photos.user_id = authors.user_id AND photos.deleted_at IS NULL\s+ORDER BY`).
+			WithArgs(1, 1, 21, 21).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(5, "followee"))
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		photos, err := photoRepo.GetFeed(context.Background(), 1, nil, 21)
+		assert.Nil(t, err)
+		assert.Equal(t, []model.FeedPhoto{{ID: 5, Username: "followee"}}, photos)
+	})
+
+	t.Run("success after cursor", func(t *testing.T) {
+		cursor := model.FeedCursor{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 9}
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(`AND \(photos.created_at, photos.id\) < \(\$3::timestamp, \$4::int\)`).
+			WithArgs(1, 1, cursor.CreatedAt, 9, 21, 21).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		photos, err := photoRepo.GetFeed(context.Background(), 1, &cursor, 21)
+		assert.Nil(t, err)
+		assert.Empty(t, photos)
+	})
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowQuery interface {
+	// CreateFollow returns false when the edge already exists.
+	CreateFollow(ctx context.Context, follow model.Follow) (bool, error)
+	// DeleteFollow returns false when there was no such edge.
+	DeleteFollow(ctx context.Context, followerID, followeeID uint64) (bool, error)
+	// GetFollowers and GetFollowing return a page of users, latest follow
+	// first, and the total. Deleted users are left out.
+	GetFollowers(ctx context.Context, userID uint64, limit, offset int) ([]model.FollowUser, int64, error)
+	GetFollowing(ctx context.Context, userID uint64, limit, offset int) ([]model.FollowUser, int64, error)
+	CountFollows(ctx context.Context, userID uint64) (model.FollowCounts, error)
+}
+
+type followQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewFollowQuery(db infrastructure.GormPostgres) FollowQuery {
+	return &followQueryImpl{db: db}
+}
+
+func (f *followQueryImpl) CreateFollow(ctx context.Context, follow model.Follow) (bool, error) {
+	db := f.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("follows").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&follow)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (f *followQueryImpl) DeleteFollow(ctx context.Context, followerID, followeeID uint64) (bool, error) {
+	db := f.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Delete(&model.Follow{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (f *followQueryImpl) GetFollowers(ctx context.Context, userID uint64, limit, offset int) ([]model.FollowUser, int64, error) {
+	return f.list(ctx, "follower_id", "followee_id", userID, limit, offset)
+}
+
+func (f *followQueryImpl) GetFollowing(ctx context.Context, userID uint64, limit, offset int) ([]model.FollowUser, int64, error) {
+	return f.list(ctx, "followee_id", "follower_id", userID, limit, offset)
+}
+
+// list returns the users in column userColumn of the edges whose
+// byColumn is userID.
+func (f *followQueryImpl) list(ctx context.Context, userColumn, byColumn string, userID uint64, limit, offset int) ([]model.FollowUser, int64, error) {
+	db := f.db.GetConnection()
+	users := []model.FollowUser{}
+	query := func() *gorm.DB {
+		return db.
+			WithContext(ctx).
+			Table("follows f").
+			Joins("JOIN users u ON u.id = f."+userColumn+" AND u.deleted_at IS NULL").
+			Where("f."+byColumn+" = ?", userID)
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return users, 0, nil
+	}
+
+	if err := query().
+		Select("u.id, u.username, f.created_at AS followed_at").
+		Order("f.created_at DESC, u.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (f *followQueryImpl) CountFollows(ctx context.Context, userID uint64) (model.FollowCounts, error) {
+	db := f.db.GetConnection()
+	counts := model.FollowCounts{}
+	if err := db.
+		WithContext(ctx).
+		Raw(`SELECT
+			(SELECT count(*) FROM follows f JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL WHERE f.followee_id = ?) AS follower_count,
+			(SELECT count(*) FROM follows f JOIN users u ON u.id = f.followee_id AND u.deleted_at IS NULL WHERE f.follower_id = ?) AS following_count`, userID, userID).
+		Scan(&counts).Error; err != nil {
+		return model.FollowCounts{}, err
+	}
+	return counts, nil
+}
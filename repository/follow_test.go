@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"mygram/infrastructure/mocks"
+	"mygram/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFollow(t *testing.T) {
+	t.Run("success duplicate edge is ignored", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "follows" ("follower_id","followee_id","created_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		followRepo := followQueryImpl{db: postgresMock}
+		created, err := followRepo.CreateFollow(context.Background(), model.Follow{FollowerID: 1, FolloweeID: 2})
+		assert.Nil(t, err)
+		assert.False(t, created)
+	})
+}
+
+func TestGetFollowers(t *testing.T) {
+	t.Run("success skips deleted users", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM follows f JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL WHERE f.followee_id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT u.id, u.username, f.created_at AS followed_at FROM follows f JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL WHERE f.followee_id = $1 ORDER BY f.created_at DESC, u.id DESC LIMIT $2 OFFSET $3`)).
+			WithArgs(1, 2, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(7, "follower"))
+
+		followRepo := followQueryImpl{db: postgresMock}
+		users, total, err := followRepo.GetFollowers(context.Background(), 1, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []model.FollowUser{{ID: 7, Username: "follower"}}, users)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LikeQuery interface {
+	// CreateLike returns false when the photo was already liked.
+	CreateLike(ctx context.Context, like model.PhotoLike) (bool, error)
+	// DeleteLike returns false when there was no such like.
+	DeleteLike(ctx context.Context, userID uint64, photoID int) (bool, error)
+	// GetLikers returns a page of the users who liked the photo, latest
+	// first, and the total. Deleted users are left out.
+	GetLikers(ctx context.Context, photoID int, limit, offset int) ([]model.PhotoLiker, int64, error)
+	GetLikeStatus(ctx context.Context, photoID int, userID uint64) (model.PhotoLikeStatus, error)
+}
+
+type likeQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewLikeQuery(db infrastructure.GormPostgres) LikeQuery {
+	return &likeQueryImpl{db: db}
+}
+
+func (l *likeQueryImpl) CreateLike(ctx context.Context, like model.PhotoLike) (bool, error) {
+	db := l.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("photo_likes").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&like)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (l *likeQueryImpl) DeleteLike(ctx context.Context, userID uint64, photoID int) (bool, error) {
+	db := l.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("photo_likes").
+		Where("user_id = ? AND photo_id = ?", userID, photoID).
+		Delete(&model.PhotoLike{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (l *likeQueryImpl) GetLikers(ctx context.Context, photoID int, limit, offset int) ([]model.PhotoLiker, int64, error) {
+	db := l.db.GetConnection()
+	users := []model.PhotoLiker{}
+	query := func() *gorm.DB {
+		return db.
+			WithContext(ctx).
+			Table("photo_likes l").
+			Joins("JOIN users u ON u.id = l.user_id AND u.deleted_at IS NULL").
+			Where("l.photo_id = ?", photoID)
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return users, 0, nil
+	}
+
+	if err := query().
+		Select("u.id, u.username, l.created_at AS liked_at").
+		Order("l.created_at DESC, u.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (l *likeQueryImpl) GetLikeStatus(ctx context.Context, photoID int, userID uint64) (model.PhotoLikeStatus, error) {
+	db := l.db.GetConnection()
+	status := model.PhotoLikeStatus{}
+	if err := db.
+		WithContext(ctx).
+		Raw(`SELECT ?::int AS photo_id,
+			(SELECT count(*) FROM photo_likes l JOIN users u ON u.id = l.user_id AND u.deleted_at IS NULL WHERE l.photo_id = ?) AS like_count,
+			EXISTS (SELECT 1 FROM photo_likes l WHERE l.photo_id = ? AND l.user_id = ?) AS liked_by_me`, photoID, photoID, photoID, userID).
+		Scan(&status).Error; err != nil {
+		return model.PhotoLikeStatus{}, err
+	}
+	return status, nil
+}
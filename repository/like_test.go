@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"mygram/infrastructure/mocks"
+	"mygram/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLike(t *testing.T) {
+	t.Run("success duplicate like is ignored", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "photo_likes" ("user_id","photo_id","created_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		likeRepo := likeQueryImpl{db: postgresMock}
+		created, err := likeRepo.CreateLike(context.Background(), model.PhotoLike{UserID: 1, PhotoID: 10})
+		assert.Nil(t, err)
+		assert.False(t, created)
+	})
+}
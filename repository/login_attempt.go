@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptQuery interface {
+	CreateLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error
+
+	// GetLoginThrottles returns the throttles found for keys, missing keys
+	// have never failed.
+	GetLoginThrottles(ctx context.Context, keys []string) ([]model.LoginThrottle, error)
+	// RegisterLoginFailure counts one more failure for key, restarting from
+	// one when the previous failure happened before resetBefore.
+	RegisterLoginFailure(ctx context.Context, key string, now, resetBefore time.Time) (model.LoginThrottle, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	DeleteLoginThrottle(ctx context.Context, key string) error
+}
+
+type loginAttemptQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewLoginAttemptQuery(db infrastructure.GormPostgres) LoginAttemptQuery {
+	return &loginAttemptQueryImpl{db: db}
+}
+
+func (l *loginAttemptQueryImpl) CreateLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error {
+	db := l.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("login_attempts").
+		Create(&attempt).
+		Error
+}
+
+func (l *loginAttemptQueryImpl) GetLoginThrottles(ctx context.Context, keys []string) ([]model.LoginThrottle, error) {
+	db := l.db.GetConnection()
+	throttles := []model.LoginThrottle{}
+	if err := db.
+		WithContext(ctx).
+		Table("login_throttles").
+		Where("key IN ?", keys).
+		Find(&throttles).Error; err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
+
+func (l *loginAttemptQueryImpl) RegisterLoginFailure(ctx context.Context, key string, now, resetBefore time.Time) (model.LoginThrottle, error) {
+	db := l.db.GetConnection()
+	throttle := model.LoginThrottle{}
+	// a single statement so concurrent guesses can not lose a failure
+	if err := db.
+		WithContext(ctx).
+		Raw(`INSERT INTO login_throttles (key, failures, last_failure_at) VALUES (?, 1, ?)
+			ON CONFLICT (key) DO UPDATE SET
+				failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+				last_failure_at = EXCLUDED.last_failure_at
+			RETURNING key, failures, last_failure_at, locked_until`, key, now, resetBefore).
+		Scan(&throttle).Error; err != nil {
+		return model.LoginThrottle{}, err
+	}
+	return throttle, nil
+}
+
+func (l *loginAttemptQueryImpl) LockLogin(ctx context.Context, key string, until time.Time) error {
+	db := l.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("login_throttles").
+		Where("key = ?", key).
+		Update("locked_until", until).
+		Error
+}
+
+func (l *loginAttemptQueryImpl) DeleteLoginThrottle(ctx context.Context, key string) error {
+	db := l.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("login_throttles").
+		Where("key = ?", key).
+		Delete(&model.LoginThrottle{}).
+		Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return nil
+}
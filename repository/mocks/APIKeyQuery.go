@@ -0,0 +1,181 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyQuery is an autogenerated mock type for the APIKeyQuery type
+type APIKeyQuery struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, key
+func (_m *APIKeyQuery) CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 model.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.APIKey) (model.APIKey, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.APIKey) model.APIKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(model.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.APIKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeyByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *APIKeyQuery) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByPrefix")
+	}
+
+	var r0 model.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.APIKey, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.APIKey); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(model.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeysByUser provides a mock function with given fields: ctx, userID
+func (_m *APIKeyQuery) GetAPIKeysByUser(ctx context.Context, userID uint64) ([]model.APIKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeysByUser")
+	}
+
+	var r0 []model.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.APIKey, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.APIKey); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, userID, id
+func (_m *APIKeyQuery) RevokeAPIKey(ctx context.Context, userID uint64, id uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKeysByUser provides a mock function with given fields: ctx, userID
+func (_m *APIKeyQuery) RevokeAPIKeysByUser(ctx context.Context, userID uint64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKeysByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchAPIKey provides a mock function with given fields: ctx, id, usedAt, gap
+func (_m *APIKeyQuery) TouchAPIKey(ctx context.Context, id uint64, usedAt time.Time, gap time.Duration) error {
+	ret := _m.Called(ctx, id, usedAt, gap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time, time.Duration) error); ok {
+		r0 = rf(ctx, id, usedAt, gap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyQuery creates a new instance of APIKeyQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyQuery {
+	mock := &APIKeyQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,197 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentsQuery is an autogenerated mock type for the CommentsQuery type
+type CommentsQuery struct {
+	mock.Mock
+}
+
+// CreateComment provides a mock function with given fields: ctx, comment
+func (_m *CommentsQuery) CreateComment(ctx context.Context, comment *model.Comments) (*model.Comments, error) {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 *model.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Comments) (*model.Comments, error)); ok {
+		return rf(ctx, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Comments) *model.Comments); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Comments) error); ok {
+		r1 = rf(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: ctx, comment
+func (_m *CommentsQuery) DeleteComment(ctx context.Context, comment *model.Comments) error {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Comments) error); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindCommentByID provides a mock function with given fields: ctx, id
+func (_m *CommentsQuery) FindCommentByID(ctx context.Context, id int) (*model.Comments, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCommentByID")
+	}
+
+	var r0 *model.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.Comments, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.Comments); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllComment provides a mock function with given fields: ctx
+func (_m *CommentsQuery) GetAllComment(ctx context.Context) ([]model.Comments, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllComment")
+	}
+
+	var r0 []model.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Comments, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Comments); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentsByPhotoID provides a mock function with given fields: ctx, photoID, after, limit
+func (_m *CommentsQuery) GetCommentsByPhotoID(ctx context.Context, photoID int, after *model.CommentCursor, limit int) ([]model.PhotoComment, error) {
+	ret := _m.Called(ctx, photoID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsByPhotoID")
+	}
+
+	var r0 []model.PhotoComment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *model.CommentCursor, int) ([]model.PhotoComment, error)); ok {
+		return rf(ctx, photoID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *model.CommentCursor, int) []model.PhotoComment); ok {
+		r0 = rf(ctx, photoID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PhotoComment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *model.CommentCursor, int) error); ok {
+		r1 = rf(ctx, photoID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateComment provides a mock function with given fields: ctx, currentComment, newComment
+func (_m *CommentsQuery) UpdateComment(ctx context.Context, currentComment *model.Comments, newComment *model.Comments) (*model.Comments, error) {
+	ret := _m.Called(ctx, currentComment, newComment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComment")
+	}
+
+	var r0 *model.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Comments, *model.Comments) (*model.Comments, error)); ok {
+		return rf(ctx, currentComment, newComment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Comments, *model.Comments) *model.Comments); ok {
+		r0 = rf(ctx, currentComment, newComment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Comments, *model.Comments) error); ok {
+		r1 = rf(ctx, currentComment, newComment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCommentsQuery creates a new instance of CommentsQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentsQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentsQuery {
+	mock := &CommentsQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,121 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationQuery is an autogenerated mock type for the EmailVerificationQuery type
+type EmailVerificationQuery struct {
+	mock.Mock
+}
+
+// CreateEmailVerification provides a mock function with given fields: ctx, verification
+func (_m *EmailVerificationQuery) CreateEmailVerification(ctx context.Context, verification model.EmailVerification) error {
+	ret := _m.Called(ctx, verification)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEmailVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EmailVerification) error); ok {
+		r0 = rf(ctx, verification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUnusedEmailVerifications provides a mock function with given fields: ctx, userID
+func (_m *EmailVerificationQuery) DeleteUnusedEmailVerifications(ctx context.Context, userID uint64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnusedEmailVerifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLatestEmailVerification provides a mock function with given fields: ctx, userID
+func (_m *EmailVerificationQuery) GetLatestEmailVerification(ctx context.Context, userID uint64) (model.EmailVerification, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestEmailVerification")
+	}
+
+	var r0 model.EmailVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.EmailVerification, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.EmailVerification); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.EmailVerification)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseEmailVerification provides a mock function with given fields: ctx, jti
+func (_m *EmailVerificationQuery) UseEmailVerification(ctx context.Context, jti string) (bool, error) {
+	ret := _m.Called(ctx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseEmailVerification")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailVerificationQuery creates a new instance of EmailVerificationQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailVerificationQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailVerificationQuery {
+	mock := &EmailVerificationQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,187 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FollowQuery is an autogenerated mock type for the FollowQuery type
+type FollowQuery struct {
+	mock.Mock
+}
+
+// CountFollows provides a mock function with given fields: ctx, userID
+func (_m *FollowQuery) CountFollows(ctx context.Context, userID uint64) (model.FollowCounts, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFollows")
+	}
+
+	var r0 model.FollowCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.FollowCounts, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.FollowCounts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.FollowCounts)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFollow provides a mock function with given fields: ctx, follow
+func (_m *FollowQuery) CreateFollow(ctx context.Context, follow model.Follow) (bool, error) {
+	ret := _m.Called(ctx, follow)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFollow")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Follow) (bool, error)); ok {
+		return rf(ctx, follow)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Follow) bool); ok {
+		r0 = rf(ctx, follow)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Follow) error); ok {
+		r1 = rf(ctx, follow)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFollow provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *FollowQuery) DeleteFollow(ctx context.Context, followerID uint64, followeeID uint64) (bool, error) {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFollow")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, followerID, followeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, followerID, followeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowers provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *FollowQuery) GetFollowers(ctx context.Context, userID uint64, limit int, offset int) ([]model.FollowUser, int64, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowers")
+	}
+
+	var r0 []model.FollowUser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) ([]model.FollowUser, int64, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) []model.FollowUser); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.FollowUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int, int) int64); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, int, int) error); ok {
+		r2 = rf(ctx, userID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFollowing provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *FollowQuery) GetFollowing(ctx context.Context, userID uint64, limit int, offset int) ([]model.FollowUser, int64, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowing")
+	}
+
+	var r0 []model.FollowUser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) ([]model.FollowUser, int64, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) []model.FollowUser); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.FollowUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int, int) int64); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, int, int) error); ok {
+		r2 = rf(ctx, userID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewFollowQuery creates a new instance of FollowQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFollowQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FollowQuery {
+	mock := &FollowQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,150 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LikeQuery is an autogenerated mock type for the LikeQuery type
+type LikeQuery struct {
+	mock.Mock
+}
+
+// CreateLike provides a mock function with given fields: ctx, like
+func (_m *LikeQuery) CreateLike(ctx context.Context, like model.PhotoLike) (bool, error) {
+	ret := _m.Called(ctx, like)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLike")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PhotoLike) (bool, error)); ok {
+		return rf(ctx, like)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.PhotoLike) bool); ok {
+		r0 = rf(ctx, like)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.PhotoLike) error); ok {
+		r1 = rf(ctx, like)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteLike provides a mock function with given fields: ctx, userID, photoID
+func (_m *LikeQuery) DeleteLike(ctx context.Context, userID uint64, photoID int) (bool, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLike")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) (bool, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) bool); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLikeStatus provides a mock function with given fields: ctx, photoID, userID
+func (_m *LikeQuery) GetLikeStatus(ctx context.Context, photoID int, userID uint64) (model.PhotoLikeStatus, error) {
+	ret := _m.Called(ctx, photoID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikeStatus")
+	}
+
+	var r0 model.PhotoLikeStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint64) (model.PhotoLikeStatus, error)); ok {
+		return rf(ctx, photoID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint64) model.PhotoLikeStatus); ok {
+		r0 = rf(ctx, photoID, userID)
+	} else {
+		r0 = ret.Get(0).(model.PhotoLikeStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, uint64) error); ok {
+		r1 = rf(ctx, photoID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLikers provides a mock function with given fields: ctx, photoID, limit, offset
+func (_m *LikeQuery) GetLikers(ctx context.Context, photoID int, limit int, offset int) ([]model.PhotoLiker, int64, error) {
+	ret := _m.Called(ctx, photoID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikers")
+	}
+
+	var r0 []model.PhotoLiker
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]model.PhotoLiker, int64, error)); ok {
+		return rf(ctx, photoID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []model.PhotoLiker); ok {
+		r0 = rf(ctx, photoID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PhotoLiker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) int64); ok {
+		r1 = rf(ctx, photoID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, int) error); ok {
+		r2 = rf(ctx, photoID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewLikeQuery creates a new instance of LikeQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLikeQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LikeQuery {
+	mock := &LikeQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
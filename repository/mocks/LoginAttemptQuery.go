@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginAttemptQuery is an autogenerated mock type for the LoginAttemptQuery type
+type LoginAttemptQuery struct {
+	mock.Mock
+}
+
+// CreateLoginAttempt provides a mock function with given fields: ctx, attempt
+func (_m *LoginAttemptQuery) CreateLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error {
+	ret := _m.Called(ctx, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoginAttempt) error); ok {
+		r0 = rf(ctx, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginThrottle provides a mock function with given fields: ctx, key
+func (_m *LoginAttemptQuery) DeleteLoginThrottle(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginThrottle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLoginThrottles provides a mock function with given fields: ctx, keys
+func (_m *LoginAttemptQuery) GetLoginThrottles(ctx context.Context, keys []string) ([]model.LoginThrottle, error) {
+	ret := _m.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginThrottles")
+	}
+
+	var r0 []model.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]model.LoginThrottle, error)); ok {
+		return rf(ctx, keys)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []model.LoginThrottle); ok {
+		r0 = rf(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockLogin provides a mock function with given fields: ctx, key, until
+func (_m *LoginAttemptQuery) LockLogin(ctx context.Context, key string, until time.Time) error {
+	ret := _m.Called(ctx, key, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, key, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterLoginFailure provides a mock function with given fields: ctx, key, now, resetBefore
+func (_m *LoginAttemptQuery) RegisterLoginFailure(ctx context.Context, key string, now time.Time, resetBefore time.Time) (model.LoginThrottle, error) {
+	ret := _m.Called(ctx, key, now, resetBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterLoginFailure")
+	}
+
+	var r0 model.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (model.LoginThrottle, error)); ok {
+		return rf(ctx, key, now, resetBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) model.LoginThrottle); ok {
+		r0 = rf(ctx, key, now, resetBefore)
+	} else {
+		r0 = ret.Get(0).(model.LoginThrottle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, key, now, resetBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLoginAttemptQuery creates a new instance of LoginAttemptQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptQuery {
+	mock := &LoginAttemptQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
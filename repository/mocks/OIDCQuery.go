@@ -0,0 +1,121 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCQuery is an autogenerated mock type for the OIDCQuery type
+type OIDCQuery struct {
+	mock.Mock
+}
+
+// CreateOIDCState provides a mock function with given fields: ctx, state
+func (_m *OIDCQuery) CreateOIDCState(ctx context.Context, state model.OIDCState) error {
+	ret := _m.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOIDCState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.OIDCState) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserIdentity provides a mock function with given fields: ctx, identity
+func (_m *OIDCQuery) CreateUserIdentity(ctx context.Context, identity model.UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *OIDCQuery) GetUserIdentity(ctx context.Context, provider string, subject string) (model.UserIdentity, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserIdentity")
+	}
+
+	var r0 model.UserIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (model.UserIdentity, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) model.UserIdentity); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		r0 = ret.Get(0).(model.UserIdentity)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseOIDCState provides a mock function with given fields: ctx, stateHash
+func (_m *OIDCQuery) UseOIDCState(ctx context.Context, stateHash string) (model.OIDCState, error) {
+	ret := _m.Called(ctx, stateHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseOIDCState")
+	}
+
+	var r0 model.OIDCState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.OIDCState, error)); ok {
+		return rf(ctx, stateHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.OIDCState); ok {
+		r0 = rf(ctx, stateHash)
+	} else {
+		r0 = ret.Get(0).(model.OIDCState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, stateHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCQuery creates a new instance of OIDCQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCQuery {
+	mock := &OIDCQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,121 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetQuery is an autogenerated mock type for the PasswordResetQuery type
+type PasswordResetQuery struct {
+	mock.Mock
+}
+
+// CreatePasswordReset provides a mock function with given fields: ctx, reset
+func (_m *PasswordResetQuery) CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error {
+	ret := _m.Called(ctx, reset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PasswordReset) error); ok {
+		r0 = rf(ctx, reset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUnusedPasswordResets provides a mock function with given fields: ctx, userID
+func (_m *PasswordResetQuery) DeleteUnusedPasswordResets(ctx context.Context, userID uint64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnusedPasswordResets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLatestPasswordReset provides a mock function with given fields: ctx, userID
+func (_m *PasswordResetQuery) GetLatestPasswordReset(ctx context.Context, userID uint64) (model.PasswordReset, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestPasswordReset")
+	}
+
+	var r0 model.PasswordReset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.PasswordReset, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.PasswordReset); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.PasswordReset)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UsePasswordReset provides a mock function with given fields: ctx, tokenHash
+func (_m *PasswordResetQuery) UsePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsePasswordReset")
+	}
+
+	var r0 model.PasswordReset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.PasswordReset, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.PasswordReset); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(model.PasswordReset)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPasswordResetQuery creates a new instance of PasswordResetQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetQuery {
+	mock := &PasswordResetQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
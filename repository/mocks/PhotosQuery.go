@@ -0,0 +1,322 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotosQuery is an autogenerated mock type for the PhotosQuery type
+type PhotosQuery struct {
+	mock.Mock
+}
+
+// CreatePhoto provides a mock function with given fields: ctx, photo
+func (_m *PhotosQuery) CreatePhoto(ctx context.Context, photo *model.Photo) (*model.Photo, error) {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePhoto")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Photo) (*model.Photo, error)); ok {
+		return rf(ctx, photo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Photo) *model.Photo); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Photo) error); ok {
+		r1 = rf(ctx, photo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePhoto provides a mock function with given fields: ctx, photo
+func (_m *PhotosQuery) DeletePhoto(ctx context.Context, photo *model.Photo) error {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePhoto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Photo) error); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindPhotoByID provides a mock function with given fields: ctx, photoId
+func (_m *PhotosQuery) FindPhotoByID(ctx context.Context, photoId int) (*model.Photo, error) {
+	ret := _m.Called(ctx, photoId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPhotoByID")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.Photo, error)); ok {
+		return rf(ctx, photoId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.Photo); ok {
+		r0 = rf(ctx, photoId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, photoId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllPhotos provides a mock function with given fields: ctx, viewerID
+func (_m *PhotosQuery) GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.Photo, error) {
+	ret := _m.Called(ctx, viewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPhotos")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Photo, error)); ok {
+		return rf(ctx, viewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Photo); ok {
+		r0 = rf(ctx, viewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID, after, limit
+func (_m *PhotosQuery) GetFeed(ctx context.Context, userID uint64, after *model.FeedCursor, limit int) ([]model.FeedPhoto, error) {
+	ret := _m.Called(ctx, userID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 []model.FeedPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.FeedCursor, int) ([]model.FeedPhoto, error)); ok {
+		return rf(ctx, userID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.FeedCursor, int) []model.FeedPhoto); ok {
+		r0 = rf(ctx, userID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.FeedPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, *model.FeedCursor, int) error); ok {
+		r1 = rf(ctx, userID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotoByID provides a mock function with given fields: ctx, photoID, viewerID
+func (_m *PhotosQuery) GetPhotoByID(ctx context.Context, photoID int, viewerID uint64) (*model.Photo, error) {
+	ret := _m.Called(ctx, photoID, viewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotoByID")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint64) (*model.Photo, error)); ok {
+		return rf(ctx, photoID, viewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint64) *model.Photo); ok {
+		r0 = rf(ctx, photoID, viewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, uint64) error); ok {
+		r1 = rf(ctx, photoID, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotosByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *PhotosQuery) GetPhotosByUserID(ctx context.Context, userID uint64, limit int, offset int) ([]model.Photo, int64, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotosByUserID")
+	}
+
+	var r0 []model.Photo
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) ([]model.Photo, int64, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) []model.Photo); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int, int) int64); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, int, int) error); ok {
+		r2 = rf(ctx, userID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPhotosWithStaleVariants provides a mock function with given fields: ctx, afterID, limit
+func (_m *PhotosQuery) GetPhotosWithStaleVariants(ctx context.Context, afterID int, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotosWithStaleVariants")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]model.Photo, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []model.Photo); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePhoto provides a mock function with given fields: ctx, currentPhoto, newPhoto
+func (_m *PhotosQuery) UpdatePhoto(ctx context.Context, currentPhoto *model.Photo, newPhoto *model.Photo) (*model.Photo, error) {
+	ret := _m.Called(ctx, currentPhoto, newPhoto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePhoto")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Photo, *model.Photo) (*model.Photo, error)); ok {
+		return rf(ctx, currentPhoto, newPhoto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Photo, *model.Photo) *model.Photo); ok {
+		r0 = rf(ctx, currentPhoto, newPhoto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Photo, *model.Photo) error); ok {
+		r1 = rf(ctx, currentPhoto, newPhoto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePhotoVariants provides a mock function with given fields: ctx, photoID, url, variants
+func (_m *PhotosQuery) UpdatePhotoVariants(ctx context.Context, photoID int, url string, variants model.PhotoVariants) (bool, error) {
+	ret := _m.Called(ctx, photoID, url, variants)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePhotoVariants")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, model.PhotoVariants) (bool, error)); ok {
+		return rf(ctx, photoID, url, variants)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, model.PhotoVariants) bool); ok {
+		r0 = rf(ctx, photoID, url, variants)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, model.PhotoVariants) error); ok {
+		r1 = rf(ctx, photoID, url, variants)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPhotosQuery creates a new instance of PhotosQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhotosQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhotosQuery {
+	mock := &PhotosQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,197 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SocialMediasQuery is an autogenerated mock type for the SocialMediasQuery type
+type SocialMediasQuery struct {
+	mock.Mock
+}
+
+// CreateSocialMedia provides a mock function with given fields: ctx, socialMedia
+func (_m *SocialMediasQuery) CreateSocialMedia(ctx context.Context, socialMedia *model.SocialMedias) (*model.SocialMedias, error) {
+	ret := _m.Called(ctx, socialMedia)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSocialMedia")
+	}
+
+	var r0 *model.SocialMedias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SocialMedias) (*model.SocialMedias, error)); ok {
+		return rf(ctx, socialMedia)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SocialMedias) *model.SocialMedias); ok {
+		r0 = rf(ctx, socialMedia)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SocialMedias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SocialMedias) error); ok {
+		r1 = rf(ctx, socialMedia)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSocialMedia provides a mock function with given fields: ctx, socialMedia
+func (_m *SocialMediasQuery) DeleteSocialMedia(ctx context.Context, socialMedia *model.SocialMedias) error {
+	ret := _m.Called(ctx, socialMedia)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSocialMedia")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SocialMedias) error); ok {
+		r0 = rf(ctx, socialMedia)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindSocialMediaByID provides a mock function with given fields: ctx, id
+func (_m *SocialMediasQuery) FindSocialMediaByID(ctx context.Context, id int) (*model.SocialMedias, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSocialMediaByID")
+	}
+
+	var r0 *model.SocialMedias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.SocialMedias, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.SocialMedias); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SocialMedias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllSocialMedia provides a mock function with given fields: ctx
+func (_m *SocialMediasQuery) GetAllSocialMedia(ctx context.Context) ([]model.SocialMedias, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllSocialMedia")
+	}
+
+	var r0 []model.SocialMedias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.SocialMedias, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.SocialMedias); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SocialMedias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSocialMediasByUserID provides a mock function with given fields: ctx, userID
+func (_m *SocialMediasQuery) GetSocialMediasByUserID(ctx context.Context, userID uint64) ([]model.SocialMedias, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSocialMediasByUserID")
+	}
+
+	var r0 []model.SocialMedias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.SocialMedias, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.SocialMedias); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SocialMedias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSocialMedia provides a mock function with given fields: ctx, currentsocialMedia, newsocialMedia
+func (_m *SocialMediasQuery) UpdateSocialMedia(ctx context.Context, currentsocialMedia *model.SocialMedias, newsocialMedia *model.SocialMedias) (*model.SocialMedias, error) {
+	ret := _m.Called(ctx, currentsocialMedia, newsocialMedia)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSocialMedia")
+	}
+
+	var r0 *model.SocialMedias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SocialMedias, *model.SocialMedias) (*model.SocialMedias, error)); ok {
+		return rf(ctx, currentsocialMedia, newsocialMedia)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SocialMedias, *model.SocialMedias) *model.SocialMedias); ok {
+		r0 = rf(ctx, currentsocialMedia, newsocialMedia)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SocialMedias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SocialMedias, *model.SocialMedias) error); ok {
+		r1 = rf(ctx, currentsocialMedia, newsocialMedia)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSocialMediasQuery creates a new instance of SocialMediasQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSocialMediasQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SocialMediasQuery {
+	mock := &SocialMediasQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
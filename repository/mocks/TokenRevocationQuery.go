@@ -0,0 +1,140 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TokenRevocationQuery is an autogenerated mock type for the TokenRevocationQuery type
+type TokenRevocationQuery struct {
+	mock.Mock
+}
+
+// DeleteExpiredRevokedTokens provides a mock function with given fields: ctx
+func (_m *TokenRevocationQuery) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredRevokedTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserTokensRevokedBefore provides a mock function with given fields: ctx, userID
+func (_m *TokenRevocationQuery) GetUserTokensRevokedBefore(ctx context.Context, userID uint64) (time.Time, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTokensRevokedBefore")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (time.Time, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) time.Time); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, jti
+func (_m *TokenRevocationQuery) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ret := _m.Called(ctx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: ctx, jti, userID, expiresAt
+func (_m *TokenRevocationQuery) RevokeToken(ctx context.Context, jti string, userID uint64, expiresAt time.Time) error {
+	ret := _m.Called(ctx, jti, userID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, time.Time) error); ok {
+		r0 = rf(ctx, jti, userID, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserTokensRevokedBefore provides a mock function with given fields: ctx, userID, revokedBefore
+func (_m *TokenRevocationQuery) SetUserTokensRevokedBefore(ctx context.Context, userID uint64, revokedBefore time.Time) error {
+	ret := _m.Called(ctx, userID, revokedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserTokensRevokedBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) error); ok {
+		r0 = rf(ctx, userID, revokedBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTokenRevocationQuery creates a new instance of TokenRevocationQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenRevocationQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenRevocationQuery {
+	mock := &TokenRevocationQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,177 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorQuery is an autogenerated mock type for the TwoFactorQuery type
+type TwoFactorQuery struct {
+	mock.Mock
+}
+
+// DisableTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *TwoFactorQuery) DisableTwoFactor(ctx context.Context, userID uint64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: ctx, userID, step, codeHashes
+func (_m *TwoFactorQuery) EnableTwoFactor(ctx context.Context, userID uint64, step int64, codeHashes []string) (bool, error) {
+	ret := _m.Called(ctx, userID, step, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTwoFactor")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int64, []string) (bool, error)); ok {
+		return rf(ctx, userID, step, codeHashes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int64, []string) bool); ok {
+		r0 = rf(ctx, userID, step, codeHashes)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int64, []string) error); ok {
+		r1 = rf(ctx, userID, step, codeHashes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTOTP provides a mock function with given fields: ctx, userID
+func (_m *TwoFactorQuery) GetUserTOTP(ctx context.Context, userID uint64) (model.UserTOTP, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTOTP")
+	}
+
+	var r0 model.UserTOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.UserTOTP, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.UserTOTP); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.UserTOTP)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveUserTOTP provides a mock function with given fields: ctx, totp
+func (_m *TwoFactorQuery) SaveUserTOTP(ctx context.Context, totp model.UserTOTP) error {
+	ret := _m.Called(ctx, totp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUserTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserTOTP) error); ok {
+		r0 = rf(ctx, totp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *TwoFactorQuery) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (bool, error)); ok {
+		return rf(ctx, userID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) bool); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = rf(ctx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *TwoFactorQuery) UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorQuery creates a new instance of TwoFactorQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorQuery {
+	mock := &TwoFactorQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
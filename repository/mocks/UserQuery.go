@@ -1,12 +1,14 @@
-// Code generated by mockery v2.42.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
-
 	model "mygram/model"
+
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserQuery is an autogenerated mock type for the UserQuery type
@@ -60,6 +62,34 @@ func (_m *UserQuery) DeleteUsersByID(ctx context.Context, id uint64) error {
 	return r0
 }
 
+// GetUserStats provides a mock function with given fields: ctx, id
+func (_m *UserQuery) GetUserStats(ctx context.Context, id uint64) (model.UserStats, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStats")
+	}
+
+	var r0 model.UserStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.UserStats, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.UserStats); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.UserStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUsers provides a mock function with given fields: ctx
 func (_m *UserQuery) GetUsers(ctx context.Context) ([]model.User, error) {
 	ret := _m.Called(ctx)
@@ -118,6 +148,144 @@ func (_m *UserQuery) GetUsersByID(ctx context.Context, id uint64) (model.User, e
 	return r0, r1
 }
 
+// GetUsersByName provides a mock function with given fields: ctx, username
+func (_m *UserQuery) GetUsersByName(ctx context.Context, username string) (model.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByName")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsersByUsername provides a mock function with given fields: ctx, email
+func (_m *UserQuery) GetUsersByUsername(ctx context.Context, email string) (model.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByUsername")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserVerifiedAt provides a mock function with given fields: ctx, id, verifiedAt
+func (_m *UserQuery) SetUserVerifiedAt(ctx context.Context, id uint64, verifiedAt *time.Time) error {
+	ret := _m.Called(ctx, id, verifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserVerifiedAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *time.Time) error); ok {
+		r0 = rf(ctx, id, verifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserByID provides a mock function with given fields: ctx, id, user
+func (_m *UserQuery) UpdateUserByID(ctx context.Context, id uint64, user model.User) (model.User, error) {
+	ret := _m.Called(ctx, id, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserByID")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.User) (model.User, error)); ok {
+		return rf(ctx, id, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.User) model.User); ok {
+		r0 = rf(ctx, id, user)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.User) error); ok {
+		r1 = rf(ctx, id, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUserPassword provides a mock function with given fields: ctx, id, passwordHash
+func (_m *UserQuery) UpdateUserPassword(ctx context.Context, id uint64, passwordHash string) error {
+	ret := _m.Called(ctx, id, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserRole provides a mock function with given fields: ctx, id, role, permissions
+func (_m *UserQuery) UpdateUserRole(ctx context.Context, id uint64, role string, permissions model.Permissions) error {
+	ret := _m.Called(ctx, id, role, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, model.Permissions) error); ok {
+		r0 = rf(ctx, id, role, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserQuery creates a new instance of UserQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserQuery(t interface {
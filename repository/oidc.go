@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type OIDCQuery interface {
+	// CreateOIDCState also drops the expired states.
+	CreateOIDCState(ctx context.Context, state model.OIDCState) error
+	// UseOIDCState deletes the state and returns it, the zero value means
+	// it is unknown, expired or was used already.
+	UseOIDCState(ctx context.Context, stateHash string) (model.OIDCState, error)
+	// GetUserIdentity returns the zero value when the identity is not
+	// linked to any user.
+	GetUserIdentity(ctx context.Context, provider, subject string) (model.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity model.UserIdentity) error
+}
+
+type oidcQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewOIDCQuery(db infrastructure.GormPostgres) OIDCQuery {
+	return &oidcQueryImpl{db: db}
+}
+
+func (o *oidcQueryImpl) CreateOIDCState(ctx context.Context, state model.OIDCState) error {
+	db := o.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("oidc_states").
+		Where("expires_at < ?", time.Now()).
+		Delete(&model.OIDCState{}).Error; err != nil {
+		return err
+	}
+	return db.
+		WithContext(ctx).
+		Table("oidc_states").
+		Create(&state).
+		Error
+}
+
+func (o *oidcQueryImpl) UseOIDCState(ctx context.Context, stateHash string) (model.OIDCState, error) {
+	db := o.db.GetConnection()
+	state := model.OIDCState{}
+	// check and delete in one statement so a state can not be used twice
+	if err := db.
+		WithContext(ctx).
+		Raw(`DELETE FROM oidc_states
+			WHERE state_hash = ? AND expires_at > ?
+			RETURNING state_hash, provider, code_verifier, nonce, expires_at, created_at`, stateHash, time.Now()).
+		Scan(&state).Error; err != nil {
+		return model.OIDCState{}, err
+	}
+	return state, nil
+}
+
+func (o *oidcQueryImpl) GetUserIdentity(ctx context.Context, provider, subject string) (model.UserIdentity, error) {
+	db := o.db.GetConnection()
+	identity := model.UserIdentity{}
+	if err := db.
+		WithContext(ctx).
+		Table("user_identities").
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.UserIdentity{}, nil
+		}
+		return model.UserIdentity{}, err
+	}
+	return identity, nil
+}
+
+func (o *oidcQueryImpl) CreateUserIdentity(ctx context.Context, identity model.UserIdentity) error {
+	db := o.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("user_identities").
+		Create(&identity).
+		Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetQuery interface {
+	CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error
+	// GetLatestPasswordReset returns the zero value when the user never
+	// asked for a reset.
+	GetLatestPasswordReset(ctx context.Context, userID uint64) (model.PasswordReset, error)
+	// UsePasswordReset marks the reset as used and returns it, the zero
+	// value means the token is unknown, expired or already used.
+	UsePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error)
+	DeleteUnusedPasswordResets(ctx context.Context, userID uint64) error
+}
+
+type passwordResetQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewPasswordResetQuery(db infrastructure.GormPostgres) PasswordResetQuery {
+	return &passwordResetQueryImpl{db: db}
+}
+
+func (p *passwordResetQueryImpl) CreatePasswordReset(ctx context.Context, reset model.PasswordReset) error {
+	db := p.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("password_resets").
+		Create(&reset).
+		Error
+}
+
+func (p *passwordResetQueryImpl) GetLatestPasswordReset(ctx context.Context, userID uint64) (model.PasswordReset, error) {
+	db := p.db.GetConnection()
+	reset := model.PasswordReset{}
+	if err := db.
+		WithContext(ctx).
+		Table("password_resets").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&reset).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.PasswordReset{}, nil
+		}
+		return model.PasswordReset{}, err
+	}
+	return reset, nil
+}
+
+func (p *passwordResetQueryImpl) UsePasswordReset(ctx context.Context, tokenHash string) (model.PasswordReset, error) {
+	db := p.db.GetConnection()
+	now := time.Now()
+	reset := model.PasswordReset{}
+	// check and mark in one statement so a token can not be used twice
+	if err := db.
+		WithContext(ctx).
+		Raw(`UPDATE password_resets SET used_at = ?
+			WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+			RETURNING id, user_id, token_hash, expires_at, used_at, created_at`, now, tokenHash, now).
+		Scan(&reset).Error; err != nil {
+		return model.PasswordReset{}, err
+	}
+	return reset, nil
+}
+
+func (p *passwordResetQueryImpl) DeleteUnusedPasswordResets(ctx context.Context, userID uint64) error {
+	db := p.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("password_resets").
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Delete(&model.PasswordReset{}).
+		Error
+}
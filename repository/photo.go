@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"mygram/infrastructure"
 	"mygram/model"
 
@@ -9,11 +10,29 @@ import (
 )
 
 type PhotosQuery interface {
-	GetAllPhotos(ctx context.Context) ([]model.Photo, error)
+	// GetAllPhotos fills in the like counts, and whether viewerID liked
+	// each photo, in the same query.
+	GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.Photo, error)
 	UpdatePhoto(ctx context.Context, currentPhoto, newPhoto *model.Photo) (*model.Photo, error)
 	DeletePhoto(ctx context.Context, photo *model.Photo) error
 	FindPhotoByID(ctx context.Context, photoId int) (*model.Photo, error)
+	// GetPhotoByID is FindPhotoByID with the owner and the likes filled in
+	// like GetAllPhotos does.
+	GetPhotoByID(ctx context.Context, photoID int, viewerID uint64) (*model.Photo, error)
 	CreatePhoto(ctx context.Context, photo *model.Photo) (*model.Photo, error)
+	// GetPhotosByUserID returns a page of the photos of a user, newest first,
+	// with the number of photos they have in total.
+	GetPhotosByUserID(ctx context.Context, userID uint64, limit, offset int) ([]model.Photo, int64, error)
+	// GetFeed returns up to limit photos of the user and of the users they
+	// follow, newest first, starting after the cursor when there is one.
+	GetFeed(ctx context.Context, userID uint64, after *model.FeedCursor, limit int) ([]model.FeedPhoto, error)
+	// UpdatePhotoVariants replaces the variants of a photo if its URL is
+	// still url, it reports false when the photo changed or was deleted
+	// while they were made.
+	UpdatePhotoVariants(ctx context.Context, photoID int, url string, variants model.PhotoVariants) (bool, error)
+	// GetPhotosWithStaleVariants returns up to limit photos after afterID,
+	// by id, whose variants were never made or were made from another URL.
+	GetPhotosWithStaleVariants(ctx context.Context, afterID, limit int) ([]model.Photo, error)
 }
 
 type PhotoCommand interface {
@@ -37,7 +56,13 @@ func (p *photoQueryImpl) CreatePhoto(ctx context.Context, photo *model.Photo) (*
 	return photo, err
 }
 
-func (p *photoQueryImpl) GetAllPhotos(ctx context.Context) ([]model.Photo, error) {
+// photoWithLikes selects the photo with its like count, without the likes
+// of deleted users, and whether the viewer liked it.
+const photoWithLikes = `photos.*,
+			(SELECT count(*) FROM photo_likes l JOIN users u ON u.id = l.user_id AND u.deleted_at IS NULL WHERE l.photo_id = photos.id) AS like_count,
+			EXISTS (SELECT 1 FROM photo_likes l WHERE l.photo_id = photos.id AND l.user_id = ?) AS liked_by_me`
+
+func (p *photoQueryImpl) GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.Photo, error) {
 	var photos []model.Photo
 
 	db := p.db.GetConnection()
@@ -45,7 +70,8 @@ func (p *photoQueryImpl) GetAllPhotos(ctx context.Context) ([]model.Photo, error
 	err :=
 		db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("ID", "Email", "Username")
-		}).Find(&photos).Error
+		}).Select(photoWithLikes, viewerID).
+			Find(&photos).Error
 
 	if err != nil {
 		return nil, err
@@ -79,6 +105,34 @@ func (p *photoQueryImpl) DeletePhoto(ctx context.Context, photo *model.Photo) er
 	return nil
 }
 
+func (p *photoQueryImpl) UpdatePhotoVariants(ctx context.Context, photoID int, url string, variants model.PhotoVariants) (bool, error) {
+	db := p.db.GetConnection()
+	result := db.
+		WithContext(ctx).
+		Model(&model.Photo{}).
+		Where("id = ? AND url = ?", photoID, url).
+		UpdateColumns(map[string]any{"variants": variants, "variants_url": url})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (p *photoQueryImpl) GetPhotosWithStaleVariants(ctx context.Context, afterID, limit int) ([]model.Photo, error) {
+	var photos []model.Photo
+
+	db := p.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Where("id > ? AND variants_url IS DISTINCT FROM url", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
 func (p *photoQueryImpl) FindPhotoByID(ctx context.Context, photoId int) (*model.Photo, error) {
 	db := p.db.GetConnection()
 	photo := &model.Photo{}
@@ -98,3 +152,92 @@ func (p *photoQueryImpl) FindPhotoByID(ctx context.Context, photoId int) (*model
 	}
 	return photo, nil
 }
+
+func (p *photoQueryImpl) GetPhotoByID(ctx context.Context, photoID int, viewerID uint64) (*model.Photo, error) {
+	db := p.db.GetConnection()
+	photo := &model.Photo{}
+
+	if err := db.
+		WithContext(ctx).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Email", "Username")
+		}).
+		Select(photoWithLikes, viewerID).
+		Where("photos.id = ?", photoID).
+		First(photo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+func (p *photoQueryImpl) GetPhotosByUserID(ctx context.Context, userID uint64, limit, offset int) ([]model.Photo, int64, error) {
+	db := p.db.GetConnection()
+	photos := []model.Photo{}
+	var total int64
+
+	if err := db.
+		WithContext(ctx).
+		Model(&model.Photo{}).
+		Where("user_id = ?", userID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return photos, 0, nil
+	}
+
+	if err := db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&photos).Error; err != nil {
+		return nil, 0, err
+	}
+	return photos, total, nil
+}
+
+func (p *photoQueryImpl) GetFeed(ctx context.Context, userID uint64, after *model.FeedCursor, limit int) ([]model.FeedPhoto, error) {
+	db := p.db.GetConnection()
+	photos := []model.FeedPhoto{}
+
+	query, args := feedQuery(userID, after, limit)
+	if err := db.WithContext(ctx).Raw(query, args...).Scan(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func feedQuery(userID uint64, after *model.FeedCursor, limit int) (string, []any) {
+	cursor := ""
+	args := []any{userID, userID}
+	if after != nil {
+		cursor = "AND (photos.created_at, photos.id) < (?::timestamp, ?::int)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	args = append(args, limit, limit)
+
+	// every author only contributes their newest limit photos, read from
+	// idx_photos_user_id_created_at, so the cost grows with the number of
+	// followed users instead of the number of photos
+	return `SELECT p.id, p.title, p.caption, p.url, p.user_id, u.username, p.created_at
+			FROM (
+				SELECT ?::int AS user_id
+				UNION
+				SELECT followee_id FROM follows WHERE follower_id = ?
+			) authors
+			JOIN users u ON u.id = authors.user_id AND u.deleted_at IS NULL
+			CROSS JOIN LATERAL (
+				SELECT photos.id, photos.title, photos.caption, photos.url, photos.user_id, photos.created_at
+				FROM photos
+				WHERE photos.user_id = authors.user_id AND photos.deleted_at IS NULL ` + cursor + `
+				ORDER BY photos.created_at DESC, photos.id DESC
+				LIMIT ?
+			) p
+			ORDER BY p.created_at DESC, p.id DESC
+			LIMIT ?`, args
+}
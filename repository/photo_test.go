@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"mygram/infrastructure/mocks"
+	"mygram/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllPhotos(t *testing.T) {
+	t.Run("success likes come with the photos", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		// one query for the photos and their likes, one for all their users
+		mock.ExpectQuery(`SELECT photos\.\*,\s+\(SELECT count\(\*\) FROM photo_likes .* AS like_count,\s+EXISTS \(.* AND l.user_id = \$1\) AS liked_by_me FROM "photos"`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "like_count", "liked_by_me"}).
+				AddRow(10, 2, 3, true).
+				AddRow(11, 3, 0, false))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","email","username" FROM "users" WHERE "users"."id" IN ($1,$2)`)).
+			WithArgs(2, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "two").AddRow(3, "three"))
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		photos, err := photoRepo.GetAllPhotos(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(photos))
+		assert.Equal(t, int64(3), photos[0].LikeCount)
+		assert.True(t, photos[0].LikedByMe)
+		assert.Equal(t, "three", photos[1].User.Username)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdatePhotoVariants(t *testing.T) {
+	variants := model.PhotoVariants{{Width: 320, Height: 240, Format: model.VARIANT_FORMAT_JPEG, URL: "http://blobs/v.jpg", Size: 10, Key: "v.jpg"}}
+
+	testCases := []struct {
+		desc    string
+		rows    int64
+		updated bool
+	}{
+		{desc: "success", rows: 1, updated: true},
+		{desc: "success url changed meanwhile", rows: 0, updated: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock := newMockGorm()
+			postgresMock := mocks.NewGormPostgres(t)
+			postgresMock.On("GetConnection").Return(db)
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET "variants"=$1,"variants_url"=$2 WHERE (id = $3 AND url = $4) AND "photos"."deleted_at" IS NULL`)).
+				WithArgs(`[{"width":320,"height":240,"format":"jpeg","url":"http://blobs/v.jpg","size":10,"key":"v.jpg"}]`, "http://blobs/photo.png", 10, "http://blobs/photo.png").
+				WillReturnResult(sqlmock.NewResult(0, tC.rows))
+			mock.ExpectCommit()
+
+			photoRepo := photoQueryImpl{db: postgresMock}
+			updated, err := photoRepo.UpdatePhotoVariants(context.Background(), 10, "http://blobs/photo.png", variants)
+			assert.Nil(t, err)
+			assert.Equal(t, tC.updated, updated)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPhotosWithStaleVariants(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE (id > $1 AND variants_url IS DISTINCT FROM url) AND "photos"."deleted_at" IS NULL ORDER BY id LIMIT $2`)).
+			WithArgs(10, 50).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).AddRow(11, "http://blobs/a.png").AddRow(14, "http://blobs/b.png"))
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		photos, err := photoRepo.GetPhotosWithStaleVariants(context.Background(), 10, 50)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(photos))
+		assert.Equal(t, 14, photos[1].ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPhotoByID(t *testing.T) {
+	t.Run("error not found", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(`SELECT photos\.\*,.* AS liked_by_me FROM "photos" WHERE photos.id = \$2 AND "photos"."deleted_at" IS NULL`).
+			WithArgs(1, 10, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		photo, err := photoRepo.GetPhotoByID(context.Background(), 10, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, photo)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
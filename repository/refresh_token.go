@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenQuery interface {
+	CreateRefreshToken(ctx context.Context, token model.RefreshToken) (model.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, hash string) (model.RefreshToken, error)
+	// MarkRefreshTokenUsed returns false when the token was already used,
+	// which means somebody else rotated it first.
+	MarkRefreshTokenUsed(ctx context.Context, id uint64) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeRefreshTokensByUserID(ctx context.Context, userID uint64) error
+}
+
+type refreshTokenQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewRefreshTokenQuery(db infrastructure.GormPostgres) RefreshTokenQuery {
+	return &refreshTokenQueryImpl{db: db}
+}
+
+func (r *refreshTokenQueryImpl) CreateRefreshToken(ctx context.Context, token model.RefreshToken) (model.RefreshToken, error) {
+	db := r.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("refresh_tokens").
+		Create(&token).Error; err != nil {
+		return model.RefreshToken{}, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenQueryImpl) GetRefreshTokenByHash(ctx context.Context, hash string) (model.RefreshToken, error) {
+	db := r.db.GetConnection()
+	token := model.RefreshToken{}
+	if err := db.
+		WithContext(ctx).
+		Table("refresh_tokens").
+		Where("token_hash = ?", hash).
+		First(&token).Error; err != nil {
+		// if token not found, return nil error
+		if err == gorm.ErrRecordNotFound {
+			return model.RefreshToken{}, nil
+		}
+		return model.RefreshToken{}, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenQueryImpl) MarkRefreshTokenUsed(ctx context.Context, id uint64) (bool, error) {
+	db := r.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("refresh_tokens").
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]any{"used_at": time.Now(), "updated_at": time.Now()})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenQueryImpl) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	db := r.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("refresh_tokens").
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]any{"revoked_at": time.Now(), "updated_at": time.Now()}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *refreshTokenQueryImpl) RevokeRefreshTokensByUserID(ctx context.Context, userID uint64) error {
+	db := r.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("refresh_tokens").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]any{"revoked_at": time.Now(), "updated_at": time.Now()}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ type SocialMediasQuery interface {
 	UpdateSocialMedia(ctx context.Context, currentsocialMedia, newsocialMedia *model.SocialMedias) (*model.SocialMedias, error)
 	DeleteSocialMedia(ctx context.Context, socialMedia *model.SocialMedias) error
 	FindSocialMediaByID(ctx context.Context, id int) (*model.SocialMedias, error)
+	GetSocialMediasByUserID(ctx context.Context, userID uint64) ([]model.SocialMedias, error)
 }
 
 type SocialMediasCommand interface {
@@ -92,3 +93,17 @@ func (sm *socialmediasQueryImpl) FindSocialMediaByID(ctx context.Context, id int
 
 	return socialMedias, nil
 }
+
+func (sm *socialmediasQueryImpl) GetSocialMediasByUserID(ctx context.Context, userID uint64) ([]model.SocialMedias, error) {
+	db := sm.db.GetConnection()
+	socialMedias := []model.SocialMedias{}
+
+	if err := db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&socialMedias).Error; err != nil {
+		return nil, err
+	}
+	return socialMedias, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRevocationQuery interface {
+	RevokeToken(ctx context.Context, jti string, userID uint64, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) error
+
+	SetUserTokensRevokedBefore(ctx context.Context, userID uint64, revokedBefore time.Time) error
+	// GetUserTokensRevokedBefore returns the zero time when the user never
+	// revoked all of their sessions.
+	GetUserTokensRevokedBefore(ctx context.Context, userID uint64) (time.Time, error)
+}
+
+type revokedToken struct {
+	Jti       string
+	UserID    uint64
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type userTokenWatermark struct {
+	UserID        uint64
+	RevokedBefore time.Time
+	UpdatedAt     time.Time
+}
+
+type tokenRevocationQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewTokenRevocationQuery(db infrastructure.GormPostgres) TokenRevocationQuery {
+	return &tokenRevocationQueryImpl{db: db}
+}
+
+func (t *tokenRevocationQueryImpl) RevokeToken(ctx context.Context, jti string, userID uint64, expiresAt time.Time) error {
+	db := t.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("revoked_tokens").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&revokedToken{Jti: jti, UserID: userID, ExpiresAt: expiresAt}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *tokenRevocationQueryImpl) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	db := t.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("revoked_tokens").
+		Where("jti = ?", jti).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (t *tokenRevocationQueryImpl) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	db := t.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("revoked_tokens").
+		Where("expires_at < ?", time.Now()).
+		Delete(&revokedToken{}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *tokenRevocationQueryImpl) SetUserTokensRevokedBefore(ctx context.Context, userID uint64, revokedBefore time.Time) error {
+	db := t.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("user_token_watermarks").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "updated_at"}),
+		}).
+		Create(&userTokenWatermark{UserID: userID, RevokedBefore: revokedBefore}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *tokenRevocationQueryImpl) GetUserTokensRevokedBefore(ctx context.Context, userID uint64) (time.Time, error) {
+	db := t.db.GetConnection()
+	watermark := userTokenWatermark{}
+	if err := db.
+		WithContext(ctx).
+		Table("user_token_watermarks").
+		Where("user_id = ?", userID).
+		First(&watermark).Error; err != nil {
+		// if user never revoked their sessions, return zero time
+		if err == gorm.ErrRecordNotFound {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return watermark.RevokedBefore, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// cachedTokenRevocationQueryImpl keeps revocation lookups in memory so the
+// bearer middleware does not hit postgres on every request. Revocations made
+// through this instance are visible immediately, revocations made by other
+// instances are picked up once the cached entry expires.
+type cachedTokenRevocationQueryImpl struct {
+	next TokenRevocationQuery
+	ttl  time.Duration
+
+	mu         sync.Mutex
+	lastSweep  time.Time
+	tokens     map[string]cacheEntry[bool]
+	watermarks map[uint64]cacheEntry[time.Time]
+}
+
+func NewCachedTokenRevocationQuery(next TokenRevocationQuery, ttl time.Duration) TokenRevocationQuery {
+	return &cachedTokenRevocationQueryImpl{
+		next:       next,
+		ttl:        ttl,
+		lastSweep:  time.Now(),
+		tokens:     map[string]cacheEntry[bool]{},
+		watermarks: map[uint64]cacheEntry[time.Time]{},
+	}
+}
+
+func (c *cachedTokenRevocationQueryImpl) RevokeToken(ctx context.Context, jti string, userID uint64, expiresAt time.Time) error {
+	if err := c.next.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// a revoked token stays revoked, keep it until the token itself expires
+	c.tokens[jti] = cacheEntry[bool]{value: true, expiresAt: expiresAt}
+	return nil
+}
+
+func (c *cachedTokenRevocationQueryImpl) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.tokens[jti]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	revoked, err := c.next.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	c.tokens[jti] = cacheEntry[bool]{value: revoked, expiresAt: time.Now().Add(c.ttl)}
+	return revoked, nil
+}
+
+func (c *cachedTokenRevocationQueryImpl) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	return c.next.DeleteExpiredRevokedTokens(ctx)
+}
+
+func (c *cachedTokenRevocationQueryImpl) SetUserTokensRevokedBefore(ctx context.Context, userID uint64, revokedBefore time.Time) error {
+	if err := c.next.SetUserTokensRevokedBefore(ctx, userID, revokedBefore); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watermarks[userID] = cacheEntry[time.Time]{value: revokedBefore, expiresAt: time.Now().Add(c.ttl)}
+	return nil
+}
+
+func (c *cachedTokenRevocationQueryImpl) GetUserTokensRevokedBefore(ctx context.Context, userID uint64) (time.Time, error) {
+	c.mu.Lock()
+	entry, ok := c.watermarks[userID]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	revokedBefore, err := c.next.GetUserTokensRevokedBefore(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	c.watermarks[userID] = cacheEntry[time.Time]{value: revokedBefore, expiresAt: time.Now().Add(c.ttl)}
+	return revokedBefore, nil
+}
+
+// sweep drops expired entries, at most once per ttl. c.mu must be held.
+func (c *cachedTokenRevocationQueryImpl) sweep() {
+	now := time.Now()
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+
+	for jti, entry := range c.tokens {
+		if now.After(entry.expiresAt) {
+			delete(c.tokens, jti)
+		}
+	}
+	for userID, entry := range c.watermarks {
+		if now.After(entry.expiresAt) {
+			delete(c.watermarks, userID)
+		}
+	}
+}
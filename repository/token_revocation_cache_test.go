@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedTokenRevocationQuery(t *testing.T) {
+	t.Run("lookup hits postgres once within ttl", func(t *testing.T) {
+		repoMock := mocks.NewTokenRevocationQuery(t)
+		repoMock.On("IsTokenRevoked", context.Background(), "jti").Return(false, nil).Once()
+
+		cache := NewCachedTokenRevocationQuery(repoMock, time.Minute)
+		for i := 0; i < 3; i++ {
+			revoked, err := cache.IsTokenRevoked(context.Background(), "jti")
+			assert.Nil(t, err)
+			assert.False(t, revoked)
+		}
+	})
+
+	t.Run("revoke is visible without hitting postgres", func(t *testing.T) {
+		repoMock := mocks.NewTokenRevocationQuery(t)
+		expiresAt := time.Now().Add(time.Hour)
+		repoMock.On("RevokeToken", context.Background(), "jti", uint64(1), expiresAt).Return(nil)
+
+		cache := NewCachedTokenRevocationQuery(repoMock, time.Minute)
+		err := cache.RevokeToken(context.Background(), "jti", 1, expiresAt)
+		assert.Nil(t, err)
+
+		revoked, err := cache.IsTokenRevoked(context.Background(), "jti")
+		assert.Nil(t, err)
+		assert.True(t, revoked)
+	})
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorQuery interface {
+	// GetUserTOTP returns the zero value when the user never enrolled.
+	GetUserTOTP(ctx context.Context, userID uint64) (model.UserTOTP, error)
+	// SaveUserTOTP starts over a pending enrollment, the secret of an
+	// enabled one is left alone.
+	SaveUserTOTP(ctx context.Context, totp model.UserTOTP) error
+	// UseTOTPStep records step as the last one used, false means a code of
+	// that step or a later one was already seen.
+	UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error)
+	// EnableTwoFactor turns two-factor on and replaces the recovery codes,
+	// false means it was enabled already.
+	EnableTwoFactor(ctx context.Context, userID uint64, step int64, codeHashes []string) (bool, error)
+	// UseRecoveryCode marks the code as used, false means it is unknown or
+	// was used before.
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error)
+	DisableTwoFactor(ctx context.Context, userID uint64) error
+}
+
+type twoFactorQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewTwoFactorQuery(db infrastructure.GormPostgres) TwoFactorQuery {
+	return &twoFactorQueryImpl{db: db}
+}
+
+func (t *twoFactorQueryImpl) GetUserTOTP(ctx context.Context, userID uint64) (model.UserTOTP, error) {
+	db := t.db.GetConnection()
+	totp := model.UserTOTP{}
+	if err := db.
+		WithContext(ctx).
+		Table("user_totps").
+		Where("user_id = ?", userID).
+		First(&totp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.UserTOTP{}, nil
+		}
+		return model.UserTOTP{}, err
+	}
+	return totp, nil
+}
+
+func (t *twoFactorQueryImpl) SaveUserTOTP(ctx context.Context, totp model.UserTOTP) error {
+	db := t.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Exec(`INSERT INTO user_totps (user_id, secret, last_used_step, created_at)
+			VALUES (?, ?, 0, now())
+			ON CONFLICT (user_id) DO UPDATE
+			SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE id = ? AND two_factor_enabled_at IS NOT NULL)`,
+			totp.UserID, totp.Secret, totp.UserID).
+		Error
+}
+
+func (t *twoFactorQueryImpl) UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error) {
+	db := t.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("user_totps").
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (t *twoFactorQueryImpl) EnableTwoFactor(ctx context.Context, userID uint64, step int64, codeHashes []string) (bool, error) {
+	db := t.db.GetConnection()
+	enabled := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Table("users").
+			Where("id = ? AND two_factor_enabled_at IS NULL", userID).
+			Update("two_factor_enabled_at", time.Now())
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		if err := tx.
+			Table("user_totps").
+			Where("user_id = ?", userID).
+			Update("last_used_step", step).Error; err != nil {
+			return err
+		}
+		if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+			return err
+		}
+		enabled = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
+func (t *twoFactorQueryImpl) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	db := t.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("recovery_codes").
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (t *twoFactorQueryImpl) DisableTwoFactor(ctx context.Context, userID uint64) error {
+	db := t.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Table("users").
+			Where("id = ?", userID).
+			Update("two_factor_enabled_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Table("user_totps").
+			Where("user_id = ?", userID).
+			Delete(&model.UserTOTP{}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, nil)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint64, codeHashes []string) error {
+	if err := tx.
+		Table("recovery_codes").
+		Where("user_id = ?", userID).
+		Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]model.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return tx.Table("recovery_codes").Create(&codes).Error
+}
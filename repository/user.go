@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"mygram/infrastructure"
 	"mygram/model"
@@ -13,10 +14,18 @@ type UserQuery interface {
 	GetUsers(ctx context.Context) ([]model.User, error)
 	GetUsersByID(ctx context.Context, id uint64) (model.User, error)
 	GetUsersByUsername(ctx context.Context, email string) (model.User, error)
+	// GetUsersByName looks up the username, unlike GetUsersByUsername which
+	// looks up the email.
+	GetUsersByName(ctx context.Context, username string) (model.User, error)
 
 	DeleteUsersByID(ctx context.Context, id uint64) error
 	CreateUser(ctx context.Context, user model.User) (model.User, error)
 	UpdateUserByID(ctx context.Context, id uint64, user model.User) (model.User, error)
+	UpdateUserRole(ctx context.Context, id uint64, role string, permissions model.Permissions) error
+	// SetUserVerifiedAt marks the email as verified, nil marks it unverified.
+	SetUserVerifiedAt(ctx context.Context, id uint64, verifiedAt *time.Time) error
+	UpdateUserPassword(ctx context.Context, id uint64, passwordHash string) error
+	GetUserStats(ctx context.Context, id uint64) (model.UserStats, error)
 }
 
 type UserCommand interface {
@@ -44,6 +53,18 @@ func (u *userQueryImpl) GetUsersByUsername(ctx context.Context, email string) (m
 	return user, nil
 }
 
+func (u *userQueryImpl) GetUsersByName(ctx context.Context, username string) (model.User, error) {
+	db := u.db.GetConnection()
+	user := model.User{}
+	if err := db.WithContext(ctx).Table("users").Where("username = ?", username).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.User{}, nil
+		}
+		return model.User{}, err
+	}
+	return user, nil
+}
+
 func (u *userQueryImpl) GetUsers(ctx context.Context) ([]model.User, error) {
 	db := u.db.GetConnection()
 	users := []model.User{}
@@ -110,3 +131,51 @@ func (u *userQueryImpl) UpdateUserByID(ctx context.Context, id uint64, user mode
 	return user, nil
 
 }
+
+func (u *userQueryImpl) UpdateUserRole(ctx context.Context, id uint64, role string, permissions model.Permissions) error {
+	db := u.db.GetConnection()
+	// a map so an empty permission list is written too
+	return db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{"role": role, "permissions": permissions}).
+		Error
+}
+
+func (u *userQueryImpl) SetUserVerifiedAt(ctx context.Context, id uint64, verifiedAt *time.Time) error {
+	db := u.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Update("verified_at", verifiedAt).
+		Error
+}
+
+func (u *userQueryImpl) UpdateUserPassword(ctx context.Context, id uint64, passwordHash string) error {
+	db := u.db.GetConnection()
+	return db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{"password": passwordHash, "updated_at": time.Now()}).
+		Error
+}
+
+func (u *userQueryImpl) GetUserStats(ctx context.Context, id uint64) (model.UserStats, error) {
+	db := u.db.GetConnection()
+	stats := model.UserStats{}
+	err := db.
+		WithContext(ctx).
+		Raw(`SELECT
+			(SELECT count(*) FROM photos WHERE user_id = ? AND deleted_at IS NULL) AS photo_count,
+			(SELECT count(*) FROM comments WHERE user_id = ? AND deleted_at IS NULL) AS comment_count,
+			(SELECT count(*) FROM social_medias WHERE user_id = ? AND deleted_at IS NULL) AS social_media_count`, id, id, id).
+		Scan(&stats).
+		Error
+	if err != nil {
+		return model.UserStats{}, err
+	}
+	return stats, nil
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"mygram/infrastructure/mocks"
+	"mygram/model"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -66,3 +67,38 @@ func TestGetUsers(t *testing.T) {
 		assert.Equal(t, 1, len(res))
 	})
 }
+
+func TestGetUserStats(t *testing.T) {
+	t.Run("success counts live rows", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		row := sqlmock.
+			NewRows([]string{"photo_count", "comment_count", "social_media_count"}).
+			AddRow(3, 5, 1)
+		mock.ExpectQuery(`SELECT .+FROM photos WHERE user_id = \$1 AND deleted_at IS NULL.+FROM comments WHERE user_id = \$2 AND deleted_at IS NULL.+FROM social_medias WHERE user_id = \$3 AND deleted_at IS NULL`).
+			WithArgs(7, 7, 7).
+			WillReturnRows(row)
+
+		userRepo := userQueryImpl{db: postgresMock}
+		stats, err := userRepo.GetUserStats(context.Background(), 7)
+		assert.Nil(t, err)
+		assert.Equal(t, model.UserStats{PhotoCount: 3, CommentCount: 5, SocialMediaCount: 1}, stats)
+	})
+}
+
+func TestGetUsersByName(t *testing.T) {
+	t.Run("success skips soft deleted users", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE username = $1 AND "users"."deleted_at" IS NULL`)).
+			WithArgs("gone", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		userRepo := userQueryImpl{db: postgresMock}
+		user, err := userRepo.GetUsersByName(context.Background(), "gone")
+		assert.Nil(t, err)
+		assert.Zero(t, user.ID)
+	})
+}
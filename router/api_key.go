@@ -0,0 +1,30 @@
+package router
+
+import (
+	"mygram/handler"
+	"mygram/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyRouter interface {
+	Mount()
+}
+
+type apiKeyRouterImpl struct {
+	v       *gin.RouterGroup
+	auth    middleware.Authorization
+	handler handler.APIKeyHandler
+}
+
+func NewAPIKeyRouter(v *gin.RouterGroup, handler handler.APIKeyHandler, auth middleware.Authorization) APIKeyRouter {
+	return &apiKeyRouterImpl{v: v, handler: handler, auth: auth}
+}
+
+func (a *apiKeyRouterImpl) Mount() {
+	// managing keys needs a real session, an API key can not mint others
+	a.v.Use(a.auth.CheckAuthBearer)
+	a.v.POST("", a.handler.CreateAPIKey)
+	a.v.GET("", a.handler.GetAPIKeys)
+	a.v.DELETE("/:keyId", a.handler.RevokeAPIKey)
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"mygram/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlobRouter interface {
+	Mount()
+}
+
+type blobRouterImpl struct {
+	v       *gin.RouterGroup
+	handler handler.BlobHandler
+}
+
+func NewBlobRouter(v *gin.RouterGroup, handler handler.BlobHandler) BlobRouter {
+	return &blobRouterImpl{v: v, handler: handler}
+}
+
+func (b *blobRouterImpl) Mount() {
+	// images are embedded in pages that can not send a token, the random
+	// keys are what keeps them private
+	b.v.GET("/*key", b.handler.GetBlob)
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"mygram/handler"
 	"mygram/middleware"
+	"mygram/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,17 +14,19 @@ type CommentsRouter interface {
 
 type commentsRouterImpl struct {
 	v       *gin.RouterGroup
+	auth    middleware.Authorization
 	handler handler.CommentsHandler
 }
 
-func NewCommentsRouter(v *gin.RouterGroup, handler handler.CommentsHandler) CommentsRouter {
-	return &commentsRouterImpl{v: v, handler: handler}
+func NewCommentsRouter(v *gin.RouterGroup, handler handler.CommentsHandler, auth middleware.Authorization) CommentsRouter {
+	return &commentsRouterImpl{v: v, handler: handler, auth: auth}
 }
 
 func (c *commentsRouterImpl) Mount() {
-	c.v.Use(middleware.CheckAuthBearer)
-	c.v.POST("", c.handler.CreateComment)
-	c.v.GET("", c.handler.GetAllComment)
-	c.v.DELETE("/:commentId", c.handler.DeleteComment)
-	c.v.PUT("/:commentId", c.handler.UpdateComment)
+	// API keys are accepted here, limited by their scopes
+	c.v.Use(c.auth.CheckAuth)
+	c.v.POST("", middleware.RequireScope(model.SCOPE_COMMENTS_WRITE), c.handler.CreateComment)
+	c.v.GET("", middleware.RequireScope(model.SCOPE_COMMENTS_READ), c.handler.GetAllComment)
+	c.v.DELETE("/:commentId", middleware.RequireScope(model.SCOPE_COMMENTS_WRITE), c.handler.DeleteComment)
+	c.v.PUT("/:commentId", middleware.RequireScope(model.SCOPE_COMMENTS_WRITE), c.handler.UpdateComment)
 }
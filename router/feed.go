@@ -0,0 +1,29 @@
+package router
+
+import (
+	"mygram/handler"
+	"mygram/middleware"
+	"mygram/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedRouter interface {
+	Mount()
+}
+
+type feedRouterImpl struct {
+	v       *gin.RouterGroup
+	auth    middleware.Authorization
+	handler handler.FeedHandler
+}
+
+func NewFeedRouter(v *gin.RouterGroup, handler handler.FeedHandler, auth middleware.Authorization) FeedRouter {
+	return &feedRouterImpl{v: v, handler: handler, auth: auth}
+}
+
+func (f *feedRouterImpl) Mount() {
+	// the feed is made of photos, API keys need their read scope
+	f.v.Use(f.auth.CheckAuth)
+	f.v.GET("", middleware.RequireScope(model.SCOPE_PHOTOS_READ), f.handler.GetFeed)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"mygram/handler"
+	"mygram/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowRouter interface {
+	Mount()
+}
+
+type followRouterImpl struct {
+	v       *gin.RouterGroup
+	auth    middleware.Authorization
+	handler handler.FollowHandler
+}
+
+func NewFollowRouter(v *gin.RouterGroup, handler handler.FollowHandler, auth middleware.Authorization) FollowRouter {
+	return &followRouterImpl{v: v, handler: handler, auth: auth}
+}
+
+func (f *followRouterImpl) Mount() {
+	// the lists are public like profiles
+	f.v.GET("/:userId/followers", f.handler.GetFollowers)
+	f.v.GET("/:userId/following", f.handler.GetFollowing)
+	f.v.POST("/:userId/follow", f.auth.CheckAuthBearer, f.handler.Follow)
+	f.v.DELETE("/:userId/follow", f.auth.CheckAuthBearer, f.handler.Unfollow)
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"mygram/handler"
+	"mygram/middleware"
+	"mygram/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikeRouter interface {
+	Mount()
+}
+
+type likeRouterImpl struct {
+	v       *gin.RouterGroup
+	auth    middleware.Authorization
+	handler handler.LikeHandler
+}
+
+func NewLikeRouter(v *gin.RouterGroup, handler handler.LikeHandler, auth middleware.Authorization) LikeRouter {
+	return &likeRouterImpl{v: v, handler: handler, auth: auth}
+}
+
+func (l *likeRouterImpl) Mount() {
+	// likes belong to photos, API keys need the photos scopes
+	l.v.Use(l.auth.CheckAuth)
+	l.v.GET("/:photoId/likes", middleware.RequireScope(model.SCOPE_PHOTOS_READ), l.handler.GetLikes)
+	l.v.PUT("/:photoId/like", middleware.RequireScope(model.SCOPE_PHOTOS_WRITE), l.handler.Like)
+	l.v.DELETE("/:photoId/like", middleware.RequireScope(model.SCOPE_PHOTOS_WRITE), l.handler.Unlike)
+}
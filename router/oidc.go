@@ -0,0 +1,26 @@
+package router
+
+import (
+	"mygram/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCRouter interface {
+	Mount()
+}
+
+type oidcRouterImpl struct {
+	v       *gin.RouterGroup
+	handler handler.OIDCHandler
+}
+
+func NewOIDCRouter(v *gin.RouterGroup, handler handler.OIDCHandler) OIDCRouter {
+	return &oidcRouterImpl{v: v, handler: handler}
+}
+
+func (o *oidcRouterImpl) Mount() {
+	// /auth/oidc/:provider
+	o.v.GET("/:provider/login", o.handler.Login)
+	o.v.GET("/:provider/callback", o.handler.Callback)
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"mygram/handler"
 	"mygram/middleware"
+	"mygram/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,17 +14,21 @@ type PhotoRouter interface {
 
 type photoRouterImpl struct {
 	v       *gin.RouterGroup
+	auth    middleware.Authorization
 	handler handler.PhotoHandler
 }
 
-func NewPhotoRouter(v *gin.RouterGroup, handler handler.PhotoHandler) PhotoRouter {
-	return &photoRouterImpl{v: v, handler: handler}
+func NewPhotoRouter(v *gin.RouterGroup, handler handler.PhotoHandler, auth middleware.Authorization) PhotoRouter {
+	return &photoRouterImpl{v: v, handler: handler, auth: auth}
 }
 
 func (p *photoRouterImpl) Mount() {
-	p.v.Use(middleware.CheckAuthBearer)
-	p.v.POST("", p.handler.CreatePhoto)
-	p.v.GET("", p.handler.GetAllPhotos)
-	p.v.DELETE("/:photoId", p.handler.DeletePhoto)
-	p.v.PUT("/:photoId", p.handler.UpdatePhoto)
+	// API keys are accepted here, limited by their scopes
+	p.v.Use(p.auth.CheckAuth)
+	p.v.POST("", middleware.RequireScope(model.SCOPE_PHOTOS_WRITE), p.handler.CreatePhoto)
+	p.v.GET("", middleware.RequireScope(model.SCOPE_PHOTOS_READ), p.handler.GetAllPhotos)
+	p.v.GET("/:photoId", middleware.RequireScope(model.SCOPE_PHOTOS_READ), p.handler.GetPhoto)
+	p.v.GET("/:photoId/comments", middleware.RequireScope(model.SCOPE_COMMENTS_READ), p.handler.GetPhotoComments)
+	p.v.DELETE("/:photoId", middleware.RequireScope(model.SCOPE_PHOTOS_WRITE), p.handler.DeletePhoto)
+	p.v.PUT("/:photoId", middleware.RequireScope(model.SCOPE_PHOTOS_WRITE), p.handler.UpdatePhoto)
 }
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"mygram/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileRouter interface {
+	Mount()
+}
+
+type profileRouterImpl struct {
+	v       *gin.RouterGroup
+	handler handler.ProfileHandler
+}
+
+func NewProfileRouter(v *gin.RouterGroup, handler handler.ProfileHandler) ProfileRouter {
+	return &profileRouterImpl{v: v, handler: handler}
+}
+
+func (p *profileRouterImpl) Mount() {
+	// profiles are public
+	p.v.GET("/:userId/profile", p.handler.GetProfile)
+	p.v.GET("/by-username/:username/profile", p.handler.GetProfileByUsername)
+}
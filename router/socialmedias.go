@@ -3,6 +3,7 @@ package router
 import (
 	"mygram/handler"
 	"mygram/middleware"
+	"mygram/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,17 +14,19 @@ type SocialMediasRouter interface {
 
 type socialmediasRouterImpl struct {
 	v       *gin.RouterGroup
+	auth    middleware.Authorization
 	handler handler.SocialMediasHandler
 }
 
-func NewSocialMediasRouter(v *gin.RouterGroup, handler handler.SocialMediasHandler) SocialMediasRouter {
-	return &socialmediasRouterImpl{v: v, handler: handler}
+func NewSocialMediasRouter(v *gin.RouterGroup, handler handler.SocialMediasHandler, auth middleware.Authorization) SocialMediasRouter {
+	return &socialmediasRouterImpl{v: v, handler: handler, auth: auth}
 }
 
 func (sm *socialmediasRouterImpl) Mount() {
-	sm.v.Use(middleware.CheckAuthBearer)
-	sm.v.POST("", sm.handler.CreateSocialMedia)
-	sm.v.GET("", sm.handler.GetAllSocialMedia)
-	sm.v.DELETE("/:socialMediaId", sm.handler.DeleteSocialMedia)
-	sm.v.PUT("/:socialMediaId", sm.handler.UpdateSocialMedia)
+	// API keys are accepted here, limited by their scopes
+	sm.v.Use(sm.auth.CheckAuth)
+	sm.v.POST("", middleware.RequireScope(model.SCOPE_SOCIAL_MEDIAS_WRITE), sm.handler.CreateSocialMedia)
+	sm.v.GET("", middleware.RequireScope(model.SCOPE_SOCIAL_MEDIAS_READ), sm.handler.GetAllSocialMedia)
+	sm.v.DELETE("/:socialMediaId", middleware.RequireScope(model.SCOPE_SOCIAL_MEDIAS_WRITE), sm.handler.DeleteSocialMedia)
+	sm.v.PUT("/:socialMediaId", middleware.RequireScope(model.SCOPE_SOCIAL_MEDIAS_WRITE), sm.handler.UpdateSocialMedia)
 }
@@ -3,6 +3,7 @@ package router
 import (
 	"mygram/handler"
 	"mygram/middleware"
+	"mygram/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,11 +14,12 @@ type UserRouter interface {
 
 type userRouterImpl struct {
 	v       *gin.RouterGroup
+	auth    middleware.Authorization
 	handler handler.UserHandler
 }
 
-func NewUserRouter(v *gin.RouterGroup, handler handler.UserHandler) UserRouter {
-	return &userRouterImpl{v: v, handler: handler}
+func NewUserRouter(v *gin.RouterGroup, handler handler.UserHandler, auth middleware.Authorization) UserRouter {
+	return &userRouterImpl{v: v, handler: handler, auth: auth}
 }
 
 func (u *userRouterImpl) Mount() {
@@ -25,13 +27,32 @@ func (u *userRouterImpl) Mount() {
 	// /users/sign-up
 	u.v.POST("/sign-up", u.handler.UserSignUp)
 	u.v.POST("/login", u.handler.UserSignIn)
+	u.v.POST("/login/2fa", u.handler.TwoFactorLogin)
+	u.v.POST("/token/refresh", u.handler.RefreshToken)
+	u.v.POST("/verify-email", u.handler.VerifyEmail)
+	u.v.POST("/password/forgot", u.handler.ForgotPassword)
+	u.v.POST("/password/reset", u.handler.ResetPassword)
 
 	// users
-	u.v.Use(middleware.CheckAuthBearer)
+	u.v.Use(u.auth.CheckAuthBearer)
+	// /users/logout
+	u.v.POST("/logout", u.handler.Logout)
+	u.v.POST("/logout-all", u.handler.LogoutAll)
+	u.v.POST("/verify-email/resend", u.handler.ResendVerificationEmail)
+	// /users/me
+	u.v.GET("/me", u.handler.GetMe)
+	u.v.PUT("/me", u.handler.UpdateMe)
+	u.v.DELETE("/me", u.handler.DeleteMe)
+	u.v.PUT("/me/password", u.handler.ChangePassword)
+	u.v.POST("/me/2fa/enroll", u.handler.EnrollTwoFactor)
+	u.v.POST("/me/2fa/confirm", u.handler.ConfirmTwoFactor)
+	u.v.POST("/me/2fa/disable", u.handler.DisableTwoFactor)
 	// /users
-	u.v.GET("", u.handler.GetUsers)
+	u.v.GET("", middleware.RequirePermission(model.PERMISSION_USERS_READ), u.handler.GetUsers)
 	// /users/:id
 	u.v.GET("/:userId", u.handler.GetUsersById)
 	u.v.DELETE("/:userId", u.handler.DeleteUsersById)
 	u.v.PUT("/:userId", u.handler.UpdateUsersById)
+	// /users/:id/role
+	u.v.PUT("/:userId/role", middleware.RequirePermission(model.PERMISSION_USERS_MANAGE), u.handler.UpdateUserRole)
 }
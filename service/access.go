@@ -0,0 +1,15 @@
+package service
+
+import (
+	"errors"
+
+	"mygram/model"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// canManage tells if principal may change a resource owned by ownerID:
+// owners always can, everybody else needs the moderation permission.
+func canManage(principal model.Principal, ownerID int, permission string) bool {
+	return uint64(ownerID) == principal.UserID || principal.HasPermission(permission)
+}
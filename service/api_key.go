@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+const (
+	MAX_API_KEYS_PER_USER = 20
+	// API_KEY_TOUCH_GAP is how stale last_used_at may get.
+	API_KEY_TOUCH_GAP = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrTooManyAPIKeys = errors.New("too many api keys, revoke one first")
+)
+
+type APIKeyService interface {
+	// Create returns the new key, it is only shown this once.
+	Create(ctx context.Context, userID uint64, req model.CreateAPIKeyRequest) (model.CreatedAPIKey, error)
+	List(ctx context.Context, userID uint64) ([]model.APIKey, error)
+	Revoke(ctx context.Context, userID, id uint64) error
+	// Authenticate returns the caller of a request made with key, acting as
+	// the owner of the key within its scopes.
+	Authenticate(ctx context.Context, key string) (model.Principal, error)
+}
+
+type apiKeyServiceImpl struct {
+	userRepo repository.UserQuery
+	repo     repository.APIKeyQuery
+	now      func() time.Time
+}
+
+func NewAPIKeyService(userRepo repository.UserQuery, repo repository.APIKeyQuery) APIKeyService {
+	return &apiKeyServiceImpl{userRepo: userRepo, repo: repo, now: time.Now}
+}
+
+func (a *apiKeyServiceImpl) Create(ctx context.Context, userID uint64, req model.CreateAPIKeyRequest) (model.CreatedAPIKey, error) {
+	keys, err := a.repo.GetAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return model.CreatedAPIKey{}, err
+	}
+	if len(keys) >= MAX_API_KEYS_PER_USER {
+		return model.CreatedAPIKey{}, ErrTooManyAPIKeys
+	}
+
+	prefix := make([]byte, 6)
+	if _, err := rand.Read(prefix); err != nil {
+		return model.CreatedAPIKey{}, err
+	}
+	secret, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return model.CreatedAPIKey{}, err
+	}
+	// mgk_<prefix>_<secret>, the prefix is hex so it never contains the _
+	key := model.API_KEY_PREFIX + hex.EncodeToString(prefix) + "_" + secret
+
+	created, err := a.repo.CreateAPIKey(ctx, model.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    hex.EncodeToString(prefix),
+		KeyHash:   helper.HashToken(key),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return model.CreatedAPIKey{}, err
+	}
+	return model.CreatedAPIKey{APIKey: created, Key: key}, nil
+}
+
+func (a *apiKeyServiceImpl) List(ctx context.Context, userID uint64) ([]model.APIKey, error) {
+	return a.repo.GetAPIKeysByUser(ctx, userID)
+}
+
+func (a *apiKeyServiceImpl) Revoke(ctx context.Context, userID, id uint64) error {
+	revoked, err := a.repo.RevokeAPIKey(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (a *apiKeyServiceImpl) Authenticate(ctx context.Context, key string) (model.Principal, error) {
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(key, model.API_KEY_PREFIX), "_")
+	if !ok || !strings.HasPrefix(key, model.API_KEY_PREFIX) {
+		return model.Principal{}, ErrInvalidAPIKey
+	}
+	apiKey, err := a.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	now := a.now()
+	if apiKey.ID == 0 || apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return model.Principal{}, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(helper.HashToken(key)), []byte(apiKey.KeyHash)) != 1 {
+		return model.Principal{}, ErrInvalidAPIKey
+	}
+
+	// keys of deleted users stop working with them
+	user, err := a.userRepo.GetUsersByID(ctx, apiKey.UserID)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	if user.ID == 0 {
+		return model.Principal{}, ErrInvalidAPIKey
+	}
+
+	if err := a.repo.TouchAPIKey(ctx, apiKey.ID, now, API_KEY_TOUCH_GAP); err != nil {
+		log.Println("cannot update api key last use", err.Error())
+	}
+
+	principal := model.Principal{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Scopes:      apiKey.Scopes,
+		Role:        user.Role,
+		Permissions: user.EffectivePermissions(),
+		IssuedAt:    apiKey.CreatedAt,
+		APIKeyID:    apiKey.ID,
+	}
+	if apiKey.ExpiresAt != nil {
+		principal.ExpiresAt = *apiKey.ExpiresAt
+	}
+	return principal, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKey(t *testing.T) {
+	now := time.Now()
+
+	// createKey runs Create and returns the key with what was stored
+	createKey := func(t *testing.T) (string, model.APIKey) {
+		repoMock := mocks.NewAPIKeyQuery(t)
+		repoMock.On("GetAPIKeysByUser", context.Background(), uint64(1)).Return([]model.APIKey{}, nil)
+		var stored model.APIKey
+		repoMock.On("CreateAPIKey", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(model.APIKey)
+		}).Return(func(_ context.Context, key model.APIKey) model.APIKey {
+			key.ID = 2
+			key.CreatedAt = now
+			return key
+		}, nil)
+
+		svc := apiKeyServiceImpl{repo: repoMock, now: time.Now}
+		created, err := svc.Create(context.Background(), 1, model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{model.SCOPE_PHOTOS_WRITE}})
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, model.API_KEY_PREFIX+stored.Prefix+"_"))
+		assert.Equal(t, helper.HashToken(created.Key), stored.KeyHash)
+		stored.ID = 2
+		return created.Key, stored
+	}
+
+	t.Run("success authenticate", func(t *testing.T) {
+		key, stored := createKey(t)
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewAPIKeyQuery(t)
+		repoMock.On("GetAPIKeyByPrefix", context.Background(), stored.Prefix).Return(stored, nil)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Username: "user1", Role: model.ROLE_USER}, nil)
+		repoMock.On("TouchAPIKey", context.Background(), uint64(2), mock.Anything, API_KEY_TOUCH_GAP).Return(nil)
+
+		svc := apiKeyServiceImpl{userRepo: userMock, repo: repoMock, now: time.Now}
+		principal, err := svc.Authenticate(context.Background(), key)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), principal.UserID)
+		assert.Equal(t, uint64(2), principal.APIKeyID)
+		assert.True(t, principal.HasScope(model.SCOPE_PHOTOS_WRITE))
+		assert.False(t, principal.HasScope(model.SCOPE_PHOTOS_READ))
+	})
+
+	t.Run("error wrong secret", func(t *testing.T) {
+		_, stored := createKey(t)
+		repoMock := mocks.NewAPIKeyQuery(t)
+		repoMock.On("GetAPIKeyByPrefix", context.Background(), stored.Prefix).Return(stored, nil)
+
+		svc := apiKeyServiceImpl{repo: repoMock, now: time.Now}
+		_, err := svc.Authenticate(context.Background(), model.API_KEY_PREFIX+stored.Prefix+"_guessed")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("error expired", func(t *testing.T) {
+		key, stored := createKey(t)
+		expiredAt := now.Add(-time.Minute)
+		stored.ExpiresAt = &expiredAt
+		repoMock := mocks.NewAPIKeyQuery(t)
+		repoMock.On("GetAPIKeyByPrefix", context.Background(), stored.Prefix).Return(stored, nil)
+
+		svc := apiKeyServiceImpl{repo: repoMock, now: time.Now}
+		_, err := svc.Authenticate(context.Background(), key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("error malformed key", func(t *testing.T) {
+		svc := apiKeyServiceImpl{now: time.Now}
+		_, err := svc.Authenticate(context.Background(), "not-a-key")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+}
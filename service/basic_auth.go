@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+var ErrBasicAuthTwoFactor = errors.New("accounts with two-factor login must use an access token")
+
+type BasicAuthService interface {
+	// Authenticate checks the credentials of a Basic request and returns the
+	// account they belong to. Failures go through the login throttle like
+	// SignIn, so Basic auth is no way around it.
+	Authenticate(ctx context.Context, email, password, ip string) (model.Principal, error)
+}
+
+type basicAuthServiceImpl struct {
+	userRepo repository.UserQuery
+	guard    LoginGuard
+	users    bool
+	// htpasswd maps service account emails to their bcrypt hash
+	htpasswd  map[string]string
+	dummyHash func() string
+}
+
+func NewBasicAuthService(userRepo repository.UserQuery, guard LoginGuard, cfg config.BasicAuthConfig, htpasswd map[string]string, password helper.PasswordPolicy) BasicAuthService {
+	return &basicAuthServiceImpl{
+		userRepo: userRepo,
+		guard:    guard,
+		users:    cfg.Users,
+		htpasswd: htpasswd,
+		dummyHash: sync.OnceValue(func() string {
+			hash, _ := password.Hash("mygram-dummy-password")
+			return hash
+		}),
+	}
+}
+
+func (b *basicAuthServiceImpl) Authenticate(ctx context.Context, email, password, ip string) (model.Principal, error) {
+	if err := b.guard.Check(ctx, email, ip); err != nil {
+		var locked *LoginLockedError
+		if errors.As(err, &locked) {
+			if err := b.guard.Failed(ctx, email, ip, nil, model.LOGIN_FAILURE_LOCKED); err != nil {
+				return model.Principal{}, err
+			}
+		}
+		return model.Principal{}, err
+	}
+
+	// service accounts are only checked against the file, the others
+	// against the users table when it is enabled. A file entry still needs
+	// its users row, it gives the principal its id and role
+	hash, isServiceAccount := b.htpasswd[email]
+	user := model.User{}
+	if isServiceAccount || b.users {
+		var err error
+		user, err = b.userRepo.GetUsersByUsername(ctx, email)
+		if err != nil {
+			return model.Principal{}, err
+		}
+	}
+	if !isServiceAccount {
+		hash = user.Password
+	}
+
+	// unknown accounts still pay for a compare
+	if user.ID == 0 {
+		if _, err := helper.CompareHash(password, b.dummyHash()); err != nil {
+			return model.Principal{}, err
+		}
+		if err := b.guard.Failed(ctx, email, ip, nil, model.LOGIN_FAILURE_UNKNOWN_EMAIL); err != nil {
+			return model.Principal{}, err
+		}
+		return model.Principal{}, ErrInvalidCredentials
+	}
+
+	match, err := helper.CompareHash(password, hash)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	if !match {
+		if err := b.guard.Failed(ctx, email, ip, &user.ID, model.LOGIN_FAILURE_WRONG_PASSWORD); err != nil {
+			return model.Principal{}, err
+		}
+		return model.Principal{}, ErrInvalidCredentials
+	}
+
+	// neither the account password nor a file entry may skip the second
+	// factor, the throttle keeps counting like in SignIn
+	if user.TwoFactorEnabledAt != nil {
+		return model.Principal{}, ErrBasicAuthTwoFactor
+	}
+	if err := b.guard.Succeeded(ctx, email); err != nil {
+		return model.Principal{}, err
+	}
+
+	return model.Principal{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Role:        user.Role,
+		Permissions: user.EffectivePermissions(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+	svcmocks "mygram/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBasicAuthenticate(t *testing.T) {
+	policy := helper.PasswordPolicy{Cost: 4}
+	hash, err := policy.Hash("password")
+	assert.Nil(t, err)
+	serviceHash, err := policy.Hash("service-password")
+	assert.Nil(t, err)
+	enabledAt := time.Now()
+
+	t.Run("success users table", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Username: "user", Password: hash, Role: model.ROLE_USER}, nil)
+		guardMock.On("Succeeded", context.Background(), "user@mail.com").Return(nil)
+
+		svc := basicAuthServiceImpl{userRepo: repoMock, guard: guardMock, users: true}
+		principal, err := svc.Authenticate(context.Background(), "user@mail.com", "password", "10.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), principal.UserID)
+		assert.True(t, principal.HasScope(model.SCOPE_PHOTOS_WRITE))
+	})
+
+	t.Run("error users table disabled", func(t *testing.T) {
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		guardMock.On("Failed", context.Background(), "user@mail.com", "10.0.0.1", (*uint64)(nil), model.LOGIN_FAILURE_UNKNOWN_EMAIL).Return(nil)
+
+		svc := basicAuthServiceImpl{guard: guardMock, dummyHash: func() string { return hash }}
+		_, err := svc.Authenticate(context.Background(), "user@mail.com", "password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("success service account ignores account password", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "bot@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "bot@mail.com").Return(model.User{ID: 2, Password: hash, Role: model.ROLE_USER}, nil)
+		guardMock.On("Succeeded", context.Background(), "bot@mail.com").Return(nil)
+		guardMock.On("Failed", context.Background(), "bot@mail.com", "10.0.0.1", mock.MatchedBy(func(id *uint64) bool {
+			return id != nil && *id == 2
+		}), model.LOGIN_FAILURE_WRONG_PASSWORD).Return(nil)
+
+		svc := basicAuthServiceImpl{userRepo: repoMock, guard: guardMock, users: true, htpasswd: map[string]string{"bot@mail.com": serviceHash}}
+		principal, err := svc.Authenticate(context.Background(), "bot@mail.com", "service-password", "10.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), principal.UserID)
+
+		_, err = svc.Authenticate(context.Background(), "bot@mail.com", "password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("error service account of two-factor user", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: hash, TwoFactorEnabledAt: &enabledAt}, nil)
+
+		svc := basicAuthServiceImpl{userRepo: repoMock, guard: guardMock, htpasswd: map[string]string{"user@mail.com": serviceHash}}
+		_, err := svc.Authenticate(context.Background(), "user@mail.com", "service-password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrBasicAuthTwoFactor)
+	})
+
+	t.Run("error service account without user", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "bot@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "bot@mail.com").Return(model.User{}, nil)
+		guardMock.On("Failed", context.Background(), "bot@mail.com", "10.0.0.1", (*uint64)(nil), model.LOGIN_FAILURE_UNKNOWN_EMAIL).Return(nil)
+
+		svc := basicAuthServiceImpl{userRepo: repoMock, guard: guardMock, htpasswd: map[string]string{"bot@mail.com": serviceHash}, dummyHash: func() string { return hash }}
+		_, err := svc.Authenticate(context.Background(), "bot@mail.com", "service-password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("error two-factor account keeps throttle", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: hash, TwoFactorEnabledAt: &enabledAt}, nil)
+
+		svc := basicAuthServiceImpl{userRepo: repoMock, guard: guardMock, users: true}
+		_, err := svc.Authenticate(context.Background(), "user@mail.com", "password", "10.0.0.1")
+		assert.ErrorIs(t, err, ErrBasicAuthTwoFactor)
+	})
+
+	t.Run("error locked", func(t *testing.T) {
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(&LoginLockedError{RetryAfter: time.Minute})
+		guardMock.On("Failed", context.Background(), "user@mail.com", "10.0.0.1", (*uint64)(nil), model.LOGIN_FAILURE_LOCKED).Return(nil)
+
+		svc := basicAuthServiceImpl{guard: guardMock, users: true}
+		_, err := svc.Authenticate(context.Background(), "user@mail.com", "password", "10.0.0.1")
+		var locked *LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+	})
+}
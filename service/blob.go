@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mygram/infrastructure"
+	"mygram/model"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type BlobService interface {
+	// Open returns a signed URL for stores that have them and the content
+	// for the others.
+	Open(ctx context.Context, key string) (model.Blob, error)
+}
+
+type blobServiceImpl struct {
+	blobs        infrastructure.BlobStore
+	signedURLTTL time.Duration
+}
+
+func NewBlobService(blobs infrastructure.BlobStore, signedURLTTL time.Duration) BlobService {
+	return &blobServiceImpl{blobs: blobs, signedURLTTL: signedURLTTL}
+}
+
+func (b *blobServiceImpl) Open(ctx context.Context, key string) (model.Blob, error) {
+	if !infrastructure.ValidBlobKey(key) {
+		return model.Blob{}, ErrBlobNotFound
+	}
+
+	signed, err := b.blobs.SignedURL(ctx, key, b.signedURLTTL)
+	if err == nil {
+		return model.Blob{RedirectURL: signed}, nil
+	}
+	if !errors.Is(err, infrastructure.ErrSignedURLUnsupported) {
+		return model.Blob{}, err
+	}
+
+	content, info, err := b.blobs.Get(ctx, key)
+	if errors.Is(err, infrastructure.ErrBlobNotFound) {
+		return model.Blob{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return model.Blob{}, err
+	}
+	return model.Blob{Content: content, ContentType: info.ContentType, Size: info.Size}, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/infrastructure"
+	imocks "mygram/infrastructure/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenBlob(t *testing.T) {
+	t.Run("success signed url", func(t *testing.T) {
+		blobMock := imocks.NewBlobStore(t)
+		blobMock.On("SignedURL", context.Background(), "photos/1/a.png", time.Minute).Return("https://s3/signed", nil)
+
+		svc := blobServiceImpl{blobs: blobMock, signedURLTTL: time.Minute}
+		blob, err := svc.Open(context.Background(), "photos/1/a.png")
+		assert.Nil(t, err)
+		assert.Equal(t, "https://s3/signed", blob.RedirectURL)
+	})
+
+	t.Run("success content without signed urls", func(t *testing.T) {
+		blobMock := imocks.NewBlobStore(t)
+		blobMock.On("SignedURL", context.Background(), "photos/1/a.png", time.Minute).Return("", infrastructure.ErrSignedURLUnsupported)
+		blobMock.On("Get", context.Background(), "photos/1/a.png").
+			Return(io.NopCloser(strings.NewReader("png")), infrastructure.BlobInfo{ContentType: "image/png", Size: 3}, nil)
+
+		svc := blobServiceImpl{blobs: blobMock, signedURLTTL: time.Minute}
+		blob, err := svc.Open(context.Background(), "photos/1/a.png")
+		assert.Nil(t, err)
+		assert.Equal(t, "image/png", blob.ContentType)
+		assert.Equal(t, int64(3), blob.Size)
+	})
+
+	t.Run("error traversal", func(t *testing.T) {
+		svc := blobServiceImpl{}
+		_, err := svc.Open(context.Background(), "../config.yaml")
+		assert.ErrorIs(t, err, ErrBlobNotFound)
+	})
+}
@@ -9,9 +9,9 @@ import (
 
 type CommentsService interface {
 	GetAllComment(ctx context.Context) ([]model.CommentGetAll, error)
-	UpdateComment(ctx context.Context, data model.UpdateComment, commentID, userID int) (*model.CommentUpdate, error)
+	UpdateComment(ctx context.Context, data model.UpdateComment, commentID int, principal model.Principal) (*model.CommentUpdate, error)
 	CreateComment(ctx context.Context, data model.CreateComment, userId int) (*model.Comments, error)
-	DeleteComment(ctx context.Context, commentID int, userID int) error
+	DeleteComment(ctx context.Context, commentID int, principal model.Principal) error
 }
 
 type commentsServiceImpl struct {
@@ -54,14 +54,14 @@ func (c *commentsServiceImpl) GetAllComment(ctx context.Context) ([]model.Commen
 	return dataComment, nil
 }
 
-func (c *commentsServiceImpl) UpdateComment(ctx context.Context, data model.UpdateComment, commentID, userID int) (*model.CommentUpdate, error) {
+func (c *commentsServiceImpl) UpdateComment(ctx context.Context, data model.UpdateComment, commentID int, principal model.Principal) (*model.CommentUpdate, error) {
 	currentComment, err := c.repo.FindCommentByID(ctx, commentID)
 	if err != nil {
 		return nil, err
 	}
 
-	if currentComment.UserID != userID {
-		return nil, fmt.Errorf("comment with id %d is not a comment owned by user with id %d.", commentID, userID)
+	if !canManage(principal, currentComment.UserID, model.PERMISSION_COMMENTS_MODERATE) {
+		return nil, fmt.Errorf("%w: comment with id %d is not a comment owned by user with id %d", ErrForbidden, commentID, principal.UserID)
 	}
 
 	newComment := &model.Comments{Message: data.Message}
@@ -83,15 +83,15 @@ func (c *commentsServiceImpl) UpdateComment(ctx context.Context, data model.Upda
 	return dataComment, nil
 }
 
-func (c *commentsServiceImpl) DeleteComment(ctx context.Context, commentID int, userID int) error {
+func (c *commentsServiceImpl) DeleteComment(ctx context.Context, commentID int, principal model.Principal) error {
 	comment, err := c.repo.FindCommentByID(ctx, commentID)
 	if err != nil {
 		return err
 
 	}
 
-	if comment.UserID != userID {
-		return fmt.Errorf("Comment with id %d is not a comment owned by user with id %d.", commentID, userID)
+	if !canManage(principal, comment.UserID, model.PERMISSION_COMMENTS_MODERATE) {
+		return fmt.Errorf("%w: comment with id %d is not a comment owned by user with id %d", ErrForbidden, commentID, principal.UserID)
 	}
 
 	err = c.repo.DeleteComment(ctx, comment)
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+var (
+	ErrInvalidVerificationToken  = errors.New("invalid or expired verification token")
+	ErrEmailAlreadyVerified      = errors.New("email is already verified")
+	ErrVerificationResendTooSoon = errors.New("a verification mail was sent recently, try again later")
+)
+
+type EmailVerificationService interface {
+	// SendVerification mails a new single use token to the user's address.
+	SendVerification(ctx context.Context, user model.User) error
+	// Resend replaces the pending tokens of the user by a new one.
+	Resend(ctx context.Context, userID uint64) error
+	Verify(ctx context.Context, token string) (model.User, error)
+}
+
+type emailVerificationServiceImpl struct {
+	userRepo repository.UserQuery
+	repo     repository.EmailVerificationQuery
+	mailer   infrastructure.Mailer
+	mail     config.MailConfig
+	jwt      config.JWTConfig
+	keys     *helper.KeySet
+}
+
+func NewEmailVerificationService(userRepo repository.UserQuery, repo repository.EmailVerificationQuery, mailer infrastructure.Mailer, mail config.MailConfig, jwt config.JWTConfig, keys *helper.KeySet) EmailVerificationService {
+	return &emailVerificationServiceImpl{userRepo: userRepo, repo: repo, mailer: mailer, mail: mail, jwt: jwt, keys: keys}
+}
+
+func (e *emailVerificationServiceImpl) SendVerification(ctx context.Context, user model.User) error {
+	jti, err := helper.GenerateRandomToken(16)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	expiresAt := now.Add(e.mail.VerificationTTL)
+
+	token, err := helper.GenerateToken(model.EmailVerificationClaim{
+		StandardClaim: model.StandardClaim{
+			Jti: jti,
+			Iss: e.jwt.Issuer,
+			Aud: e.jwt.Audience,
+			Sub: model.SUBJECT_EMAIL_VERIFICATION_TOKEN,
+			Exp: uint64(expiresAt.Unix()),
+			Iat: uint64(now.Unix()),
+			Nbf: uint64(now.Unix()),
+		},
+		UserID: user.ID,
+		Email:  user.Email,
+	}, e.keys)
+	if err != nil {
+		return err
+	}
+
+	if err := e.repo.CreateEmailVerification(ctx, model.EmailVerification{
+		Jti:       jti,
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	link := strings.ReplaceAll(e.mail.VerificationURL, "{token}", url.QueryEscape(token))
+	return e.mailer.Send(ctx, infrastructure.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nplease confirm your email address by opening the link below. It expires in %s.\n\n%s\n\nIf you did not sign up to mygram you can ignore this mail.\n",
+			user.Username, e.mail.VerificationTTL, link),
+	})
+}
+
+func (e *emailVerificationServiceImpl) Resend(ctx context.Context, userID uint64) error {
+	user, err := e.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+	if user.VerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	latest, err := e.repo.GetLatestEmailVerification(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if time.Since(latest.CreatedAt) < e.mail.ResendGap {
+		return ErrVerificationResendTooSoon
+	}
+
+	// only the newest mail stays valid
+	if err := e.repo.DeleteUnusedEmailVerifications(ctx, userID); err != nil {
+		return err
+	}
+	return e.SendVerification(ctx, user)
+}
+
+func (e *emailVerificationServiceImpl) Verify(ctx context.Context, token string) (model.User, error) {
+	claims, err := helper.ValidateToken(token, e.keys, helper.TokenExpectation{
+		Issuer:   e.jwt.Issuer,
+		Audience: e.jwt.Audience,
+		Subject:  model.SUBJECT_EMAIL_VERIFICATION_TOKEN,
+		Leeway:   e.jwt.Leeway,
+	})
+	if err != nil {
+		return model.User{}, fmt.Errorf("%w: %v", ErrInvalidVerificationToken, err)
+	}
+	claim := model.EmailVerificationClaim{}
+	if err := helper.DecodeClaim(claims, &claim); err != nil || claim.Jti == "" {
+		return model.User{}, ErrInvalidVerificationToken
+	}
+
+	used, err := e.repo.UseEmailVerification(ctx, claim.Jti)
+	if err != nil {
+		return model.User{}, err
+	}
+	if !used {
+		return model.User{}, ErrInvalidVerificationToken
+	}
+
+	user, err := e.userRepo.GetUsersByID(ctx, claim.UserID)
+	if err != nil {
+		return model.User{}, err
+	}
+	// the address changed since the mail was sent
+	if user.ID == 0 || !strings.EqualFold(user.Email, claim.Email) {
+		return model.User{}, ErrInvalidVerificationToken
+	}
+	if user.VerifiedAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+	if err := e.userRepo.SetUserVerifiedAt(ctx, user.ID, &now); err != nil {
+		return model.User{}, err
+	}
+	user.VerifiedAt = &now
+	return user, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	imocks "mygram/infrastructure/mocks"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailVerification(t *testing.T) {
+	jwtCfg := config.JWTConfig{Issuer: "project", Audience: "mygram", Leeway: time.Second}
+	mailCfg := config.MailConfig{
+		VerificationURL: "http://localhost/verify?token={token}",
+		VerificationTTL: time.Hour,
+		ResendGap:       time.Minute,
+	}
+	keys := helper.NewHMACKeySet("a-very-long-secret-used-for-testing-only")
+	user := model.User{ID: 1, Username: "user1", Email: "user@mail.com"}
+
+	// sendToken runs SendVerification and returns the mailed token
+	sendToken := func(t *testing.T) string {
+		repoMock := mocks.NewEmailVerificationQuery(t)
+		mailerMock := imocks.NewMailer(t)
+		repoMock.On("CreateEmailVerification", mock.Anything, mock.MatchedBy(func(v model.EmailVerification) bool {
+			return v.UserID == 1 && v.Jti != ""
+		})).Return(nil)
+
+		var token string
+		mailerMock.On("Send", mock.Anything, mock.MatchedBy(func(m infrastructure.Mail) bool {
+			return m.To == "user@mail.com"
+		})).Run(func(args mock.Arguments) {
+			body := args.Get(1).(infrastructure.Mail).Body
+			link := body[strings.Index(body, "http://localhost/verify?token="):]
+			link = strings.Fields(link)[0]
+			parsed, _ := url.Parse(link)
+			token = parsed.Query().Get("token")
+		}).Return(nil)
+
+		svc := emailVerificationServiceImpl{repo: repoMock, mailer: mailerMock, mail: mailCfg, jwt: jwtCfg, keys: keys}
+		assert.Nil(t, svc.SendVerification(context.Background(), user))
+		assert.NotEqual(t, "", token)
+		return token
+	}
+
+	t.Run("success verify", func(t *testing.T) {
+		token := sendToken(t)
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewEmailVerificationQuery(t)
+		repoMock.On("UseEmailVerification", mock.Anything, mock.Anything).Return(true, nil)
+		userMock.On("GetUsersByID", mock.Anything, uint64(1)).Return(user, nil)
+		userMock.On("SetUserVerifiedAt", mock.Anything, uint64(1), mock.Anything).Return(nil)
+
+		svc := emailVerificationServiceImpl{userRepo: userMock, repo: repoMock, mail: mailCfg, jwt: jwtCfg, keys: keys}
+		verified, err := svc.Verify(context.Background(), token)
+		assert.Nil(t, err)
+		assert.NotNil(t, verified.VerifiedAt)
+	})
+
+	t.Run("error token already used", func(t *testing.T) {
+		token := sendToken(t)
+		repoMock := mocks.NewEmailVerificationQuery(t)
+		repoMock.On("UseEmailVerification", mock.Anything, mock.Anything).Return(false, nil)
+
+		svc := emailVerificationServiceImpl{repo: repoMock, mail: mailCfg, jwt: jwtCfg, keys: keys}
+		_, err := svc.Verify(context.Background(), token)
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
+
+	t.Run("error email changed", func(t *testing.T) {
+		token := sendToken(t)
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewEmailVerificationQuery(t)
+		repoMock.On("UseEmailVerification", mock.Anything, mock.Anything).Return(true, nil)
+		userMock.On("GetUsersByID", mock.Anything, uint64(1)).Return(model.User{ID: 1, Email: "other@mail.com"}, nil)
+
+		svc := emailVerificationServiceImpl{userRepo: userMock, repo: repoMock, mail: mailCfg, jwt: jwtCfg, keys: keys}
+		_, err := svc.Verify(context.Background(), token)
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
+
+	t.Run("error access token is not a verification token", func(t *testing.T) {
+		svc := emailVerificationServiceImpl{mail: mailCfg, jwt: jwtCfg, keys: keys}
+		accessToken, err := (&userServiceImpl{jwt: config.JWTConfig{Issuer: "project", Audience: "mygram", AccessTTL: time.Hour}, keys: keys}).
+			GenerateUserAccessToken(context.Background(), user)
+		assert.Nil(t, err)
+
+		_, err = svc.Verify(context.Background(), accessToken)
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
+
+	t.Run("error resend too soon", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewEmailVerificationQuery(t)
+		userMock.On("GetUsersByID", mock.Anything, uint64(1)).Return(user, nil)
+		repoMock.On("GetLatestEmailVerification", mock.Anything, uint64(1)).Return(model.EmailVerification{CreatedAt: time.Now().Add(-10 * time.Second)}, nil)
+
+		svc := emailVerificationServiceImpl{userRepo: userMock, repo: repoMock, mail: mailCfg}
+		assert.ErrorIs(t, svc.Resend(context.Background(), 1), ErrVerificationResendTooSoon)
+	})
+}
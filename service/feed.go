@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"mygram/model"
+	"mygram/repository"
+)
+
+type FeedService interface {
+	// GetFeed returns model.ErrInvalidFeedCursor when the cursor was not
+	// issued by a previous page.
+	GetFeed(ctx context.Context, userID uint64, req model.FeedRequest) (model.Feed, error)
+}
+
+type feedServiceImpl struct {
+	photoRepo repository.PhotosQuery
+}
+
+func NewFeedService(photoRepo repository.PhotosQuery) FeedService {
+	return &feedServiceImpl{photoRepo: photoRepo}
+}
+
+func (f *feedServiceImpl) GetFeed(ctx context.Context, userID uint64, req model.FeedRequest) (model.Feed, error) {
+	var after *model.FeedCursor
+	if req.Cursor != "" {
+		cursor, err := model.DecodeFeedCursor(req.Cursor)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		after = &cursor
+	}
+
+	// one extra photo tells if there is a next page
+	photos, err := f.photoRepo.GetFeed(ctx, userID, after, req.Limit+1)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	if len(photos) <= req.Limit {
+		return model.Feed{Photos: photos}, nil
+	}
+
+	photos = photos[:req.Limit]
+	last := photos[len(photos)-1]
+	return model.Feed{
+		Photos:     photos,
+		NextCursor: model.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFeed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	photos := []model.FeedPhoto{
+		{ID: 3, CreatedAt: now},
+		{ID: 2, CreatedAt: now.Add(-time.Minute)},
+		{ID: 1, CreatedAt: now.Add(-time.Hour)},
+	}
+
+	t.Run("success next cursor points at last photo of the page", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetFeed", context.Background(), uint64(1), (*model.FeedCursor)(nil), 3).Return(photos, nil)
+
+		svc := feedServiceImpl{photoRepo: repoMock}
+		feed, err := svc.GetFeed(context.Background(), 1, model.FeedRequest{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, photos[:2], feed.Photos)
+		assert.Equal(t, model.FeedCursor{CreatedAt: photos[1].CreatedAt, ID: 2}.Encode(), feed.NextCursor)
+	})
+
+	t.Run("success last page has no cursor", func(t *testing.T) {
+		cursor := model.FeedCursor{CreatedAt: now, ID: 3}
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetFeed", context.Background(), uint64(1), &cursor, 3).Return(photos[1:], nil)
+
+		svc := feedServiceImpl{photoRepo: repoMock}
+		feed, err := svc.GetFeed(context.Background(), 1, model.FeedRequest{Cursor: cursor.Encode(), Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, photos[1:], feed.Photos)
+		assert.Empty(t, feed.NextCursor)
+	})
+
+	t.Run("error invalid cursor", func(t *testing.T) {
+		svc := feedServiceImpl{}
+		_, err := svc.GetFeed(context.Background(), 1, model.FeedRequest{Cursor: "bogus", Limit: 2})
+		assert.ErrorIs(t, err, model.ErrInvalidFeedCursor)
+	})
+}
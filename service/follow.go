@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"mygram/model"
+	"mygram/repository"
+)
+
+var (
+	ErrFollowSelf         = errors.New("users can not follow themselves")
+	ErrFollowUserNotFound = errors.New("user not found")
+	ErrAlreadyFollowing   = errors.New("already following this user")
+	ErrNotFollowing       = errors.New("not following this user")
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID, followeeID uint64) (model.Follow, error)
+	Unfollow(ctx context.Context, followerID, followeeID uint64) error
+	Followers(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error)
+	Following(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error)
+}
+
+type followServiceImpl struct {
+	userRepo repository.UserQuery
+	repo     repository.FollowQuery
+}
+
+func NewFollowService(userRepo repository.UserQuery, repo repository.FollowQuery) FollowService {
+	return &followServiceImpl{userRepo: userRepo, repo: repo}
+}
+
+func (f *followServiceImpl) Follow(ctx context.Context, followerID, followeeID uint64) (model.Follow, error) {
+	if followerID == followeeID {
+		return model.Follow{}, ErrFollowSelf
+	}
+	if err := f.userExists(ctx, followeeID); err != nil {
+		return model.Follow{}, err
+	}
+
+	follow := model.Follow{FollowerID: followerID, FolloweeID: followeeID}
+	created, err := f.repo.CreateFollow(ctx, follow)
+	if err != nil {
+		return model.Follow{}, err
+	}
+	if !created {
+		return model.Follow{}, ErrAlreadyFollowing
+	}
+	return follow, nil
+}
+
+func (f *followServiceImpl) Unfollow(ctx context.Context, followerID, followeeID uint64) error {
+	deleted, err := f.repo.DeleteFollow(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFollowing
+	}
+	return nil
+}
+
+func (f *followServiceImpl) Followers(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error) {
+	if err := f.userExists(ctx, userID); err != nil {
+		return model.FollowList{}, err
+	}
+	users, total, err := f.repo.GetFollowers(ctx, userID, page.PageSize, page.Offset())
+	if err != nil {
+		return model.FollowList{}, err
+	}
+	return model.FollowList{Users: users, Page: model.PageInfo{Page: page.Page, PageSize: page.PageSize, Total: total}}, nil
+}
+
+func (f *followServiceImpl) Following(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error) {
+	if err := f.userExists(ctx, userID); err != nil {
+		return model.FollowList{}, err
+	}
+	users, total, err := f.repo.GetFollowing(ctx, userID, page.PageSize, page.Offset())
+	if err != nil {
+		return model.FollowList{}, err
+	}
+	return model.FollowList{Users: users, Page: model.PageInfo{Page: page.Page, PageSize: page.PageSize, Total: total}}, nil
+}
+
+// userExists returns ErrFollowUserNotFound for unknown and deleted users.
+func (f *followServiceImpl) userExists(ctx context.Context, id uint64) error {
+	user, err := f.userRepo.GetUsersByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return ErrFollowUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewFollowQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		repoMock.On("CreateFollow", context.Background(), model.Follow{FollowerID: 1, FolloweeID: 2}).Return(true, nil)
+
+		svc := followServiceImpl{userRepo: userMock, repo: repoMock}
+		follow, err := svc.Follow(context.Background(), 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), follow.FolloweeID)
+	})
+
+	t.Run("error self follow", func(t *testing.T) {
+		svc := followServiceImpl{}
+		_, err := svc.Follow(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrFollowSelf)
+	})
+
+	t.Run("error deleted user", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{}, nil)
+
+		svc := followServiceImpl{userRepo: userMock}
+		_, err := svc.Follow(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrFollowUserNotFound)
+	})
+
+	t.Run("error duplicate edge", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewFollowQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		repoMock.On("CreateFollow", context.Background(), model.Follow{FollowerID: 1, FolloweeID: 2}).Return(false, nil)
+
+		svc := followServiceImpl{userRepo: userMock, repo: repoMock}
+		_, err := svc.Follow(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrAlreadyFollowing)
+	})
+}
+
+func TestUnfollow(t *testing.T) {
+	t.Run("error not following", func(t *testing.T) {
+		repoMock := mocks.NewFollowQuery(t)
+		repoMock.On("DeleteFollow", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+
+		svc := followServiceImpl{repo: repoMock}
+		assert.ErrorIs(t, svc.Unfollow(context.Background(), 1, 2), ErrNotFollowing)
+	})
+}
+
+func TestFollowers(t *testing.T) {
+	t.Run("success second page", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewFollowQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		repoMock.On("GetFollowers", context.Background(), uint64(1), 5, 5).Return([]model.FollowUser{{ID: 7}}, int64(6), nil)
+
+		svc := followServiceImpl{userRepo: userMock, repo: repoMock}
+		list, err := svc.Followers(context.Background(), 1, model.PageRequest{Page: 2, PageSize: 5})
+		assert.Nil(t, err)
+		assert.Equal(t, model.FollowList{
+			Users: []model.FollowUser{{ID: 7}},
+			Page:  model.PageInfo{Page: 2, PageSize: 5, Total: 6},
+		}, list)
+	})
+}
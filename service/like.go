@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"mygram/model"
+	"mygram/repository"
+)
+
+var ErrPhotoNotFound = errors.New("photo not found")
+
+type LikeService interface {
+	// Like and Unlike are idempotent, both return the state of the photo
+	// afterwards.
+	Like(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error)
+	Unlike(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error)
+	Likers(ctx context.Context, photoID int, page model.PageRequest) (model.PhotoLikeList, error)
+}
+
+type likeServiceImpl struct {
+	photoRepo repository.PhotosQuery
+	repo      repository.LikeQuery
+}
+
+func NewLikeService(photoRepo repository.PhotosQuery, repo repository.LikeQuery) LikeService {
+	return &likeServiceImpl{photoRepo: photoRepo, repo: repo}
+}
+
+func (l *likeServiceImpl) Like(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error) {
+	if err := l.photoExists(ctx, photoID); err != nil {
+		return model.PhotoLikeStatus{}, err
+	}
+	if _, err := l.repo.CreateLike(ctx, model.PhotoLike{UserID: userID, PhotoID: photoID}); err != nil {
+		return model.PhotoLikeStatus{}, err
+	}
+	return l.repo.GetLikeStatus(ctx, photoID, userID)
+}
+
+func (l *likeServiceImpl) Unlike(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error) {
+	if err := l.photoExists(ctx, photoID); err != nil {
+		return model.PhotoLikeStatus{}, err
+	}
+	if _, err := l.repo.DeleteLike(ctx, userID, photoID); err != nil {
+		return model.PhotoLikeStatus{}, err
+	}
+	return l.repo.GetLikeStatus(ctx, photoID, userID)
+}
+
+func (l *likeServiceImpl) Likers(ctx context.Context, photoID int, page model.PageRequest) (model.PhotoLikeList, error) {
+	if err := l.photoExists(ctx, photoID); err != nil {
+		return model.PhotoLikeList{}, err
+	}
+	users, total, err := l.repo.GetLikers(ctx, photoID, page.PageSize, page.Offset())
+	if err != nil {
+		return model.PhotoLikeList{}, err
+	}
+	return model.PhotoLikeList{Users: users, Page: model.PageInfo{Page: page.Page, PageSize: page.PageSize, Total: total}}, nil
+}
+
+// photoExists returns ErrPhotoNotFound for unknown and deleted photos.
+func (l *likeServiceImpl) photoExists(ctx context.Context, photoID int) error {
+	photo, err := l.photoRepo.FindPhotoByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	if photo == nil {
+		return ErrPhotoNotFound
+	}
+	return nil
+}
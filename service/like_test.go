@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLike(t *testing.T) {
+	t.Run("success liking twice keeps one like", func(t *testing.T) {
+		photoMock := mocks.NewPhotosQuery(t)
+		repoMock := mocks.NewLikeQuery(t)
+		photoMock.On("FindPhotoByID", context.Background(), 10).Return(&model.Photo{ID: 10}, nil)
+		repoMock.On("CreateLike", context.Background(), model.PhotoLike{UserID: 1, PhotoID: 10}).Return(false, nil)
+		repoMock.On("GetLikeStatus", context.Background(), 10, uint64(1)).Return(model.PhotoLikeStatus{PhotoID: 10, LikeCount: 1, LikedByMe: true}, nil)
+
+		svc := likeServiceImpl{photoRepo: photoMock, repo: repoMock}
+		status, err := svc.Like(context.Background(), 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, model.PhotoLikeStatus{PhotoID: 10, LikeCount: 1, LikedByMe: true}, status)
+	})
+
+	t.Run("error photo not found", func(t *testing.T) {
+		photoMock := mocks.NewPhotosQuery(t)
+		photoMock.On("FindPhotoByID", context.Background(), 10).Return(nil, nil)
+
+		svc := likeServiceImpl{photoRepo: photoMock}
+		_, err := svc.Like(context.Background(), 1, 10)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestUnlike(t *testing.T) {
+	t.Run("success without a like", func(t *testing.T) {
+		photoMock := mocks.NewPhotosQuery(t)
+		repoMock := mocks.NewLikeQuery(t)
+		photoMock.On("FindPhotoByID", context.Background(), 10).Return(&model.Photo{ID: 10}, nil)
+		repoMock.On("DeleteLike", context.Background(), uint64(1), 10).Return(false, nil)
+		repoMock.On("GetLikeStatus", context.Background(), 10, uint64(1)).Return(model.PhotoLikeStatus{PhotoID: 10, LikeCount: 3}, nil)
+
+		svc := likeServiceImpl{photoRepo: photoMock, repo: repoMock}
+		status, err := svc.Unlike(context.Background(), 1, 10)
+		assert.Nil(t, err)
+		assert.False(t, status.LikedByMe)
+	})
+}
+
+func TestLikers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		photoMock := mocks.NewPhotosQuery(t)
+		repoMock := mocks.NewLikeQuery(t)
+		photoMock.On("FindPhotoByID", context.Background(), 10).Return(&model.Photo{ID: 10}, nil)
+		repoMock.On("GetLikers", context.Background(), 10, 2, 2).Return([]model.PhotoLiker{{ID: 7, Username: "liker"}}, int64(3), nil)
+
+		svc := likeServiceImpl{photoRepo: photoMock, repo: repoMock}
+		list, err := svc.Likers(context.Background(), 10, model.PageRequest{Page: 2, PageSize: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, model.PageInfo{Page: 2, PageSize: 2, Total: 3}, list.Page)
+		assert.Equal(t, []model.PhotoLiker{{ID: 7, Username: "liker"}}, list.Users)
+	})
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/repository"
+)
+
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// LoginLockedError is returned while an account or an IP is locked out.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginGuard tracks failed logins per account and per IP. Unknown emails are
+// tracked like existing ones so lockouts do not reveal which accounts exist.
+type LoginGuard interface {
+	// Check returns a *LoginLockedError when the email or the ip is locked.
+	Check(ctx context.Context, email, ip string) error
+	// Failed records the attempt for auditing and extends the lockouts.
+	// userID is nil when no account uses the email.
+	Failed(ctx context.Context, email, ip string, userID *uint64, reason string) error
+	// Succeeded clears the failures of the account, not the ones of the ip.
+	Succeeded(ctx context.Context, email string) error
+}
+
+type loginGuardImpl struct {
+	repo repository.LoginAttemptQuery
+	cfg  config.LoginConfig
+	now  func() time.Time
+}
+
+func NewLoginGuard(repo repository.LoginAttemptQuery, cfg config.LoginConfig) LoginGuard {
+	return &loginGuardImpl{repo: repo, cfg: cfg, now: time.Now}
+}
+
+func (l *loginGuardImpl) Check(ctx context.Context, email, ip string) error {
+	throttles, err := l.repo.GetLoginThrottles(ctx, []string{accountKey(email), ipKey(ip)})
+	if err != nil {
+		return err
+	}
+
+	now := l.now()
+	var retryAfter time.Duration
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, throttle.LockedUntil.Sub(now))
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (l *loginGuardImpl) Failed(ctx context.Context, email, ip string, userID *uint64, reason string) error {
+	if err := l.repo.CreateLoginAttempt(ctx, model.LoginAttempt{
+		Email:  normalizeEmail(email),
+		UserID: userID,
+		IP:     ip,
+		Reason: reason,
+	}); err != nil {
+		return err
+	}
+	// attempts made while locked are only audited, counting them would let
+	// anyone keep a legitimate user locked out forever
+	if reason == model.LOGIN_FAILURE_LOCKED {
+		return nil
+	}
+
+	if err := l.registerFailure(ctx, accountKey(email), l.cfg.MaxAccountAttempts); err != nil {
+		return err
+	}
+	return l.registerFailure(ctx, ipKey(ip), l.cfg.MaxIPAttempts)
+}
+
+func (l *loginGuardImpl) Succeeded(ctx context.Context, email string) error {
+	return l.repo.DeleteLoginThrottle(ctx, accountKey(email))
+}
+
+func (l *loginGuardImpl) registerFailure(ctx context.Context, key string, maxAttempts int) error {
+	now := l.now()
+	throttle, err := l.repo.RegisterLoginFailure(ctx, key, now, now.Add(-l.cfg.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if throttle.Failures < maxAttempts {
+		return nil
+	}
+	return l.repo.LockLogin(ctx, key, now.Add(l.lockout(throttle.Failures-maxAttempts)))
+}
+
+// lockout doubles BaseLockout for every failure past the free attempts.
+func (l *loginGuardImpl) lockout(extraFailures int) time.Duration {
+	lockout := l.cfg.BaseLockout
+	for i := 0; i < extraFailures && lockout < l.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, l.cfg.MaxLockout)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func accountKey(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginGuard(t *testing.T) {
+	cfg := config.LoginConfig{
+		MaxAccountAttempts: 3,
+		MaxIPAttempts:      10,
+		BaseLockout:        time.Minute,
+		MaxLockout:         10 * time.Minute,
+		FailureWindow:      15 * time.Minute,
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("error locked account", func(t *testing.T) {
+		lockedUntil := now.Add(90 * time.Second)
+		repoMock := mocks.NewLoginAttemptQuery(t)
+		repoMock.On("GetLoginThrottles", context.Background(), []string{"account:user@mail.com", "ip:10.0.0.1"}).
+			Return([]model.LoginThrottle{{Key: "account:user@mail.com", Failures: 3, LockedUntil: &lockedUntil}}, nil)
+
+		guard := loginGuardImpl{repo: repoMock, cfg: cfg, now: func() time.Time { return now }}
+		err := guard.Check(context.Background(), " User@Mail.com", "10.0.0.1")
+
+		var locked *LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, 90*time.Second, locked.RetryAfter)
+	})
+
+	t.Run("success lock expired", func(t *testing.T) {
+		lockedUntil := now.Add(-time.Second)
+		repoMock := mocks.NewLoginAttemptQuery(t)
+		repoMock.On("GetLoginThrottles", context.Background(), mock.Anything).
+			Return([]model.LoginThrottle{{Key: "ip:10.0.0.1", Failures: 10, LockedUntil: &lockedUntil}}, nil)
+
+		guard := loginGuardImpl{repo: repoMock, cfg: cfg, now: func() time.Time { return now }}
+		assert.Nil(t, guard.Check(context.Background(), "user@mail.com", "10.0.0.1"))
+	})
+
+	testCases := []struct {
+		desc     string
+		failures int
+		lockout  time.Duration
+	}{
+		{desc: "no lockout below max attempts", failures: 2},
+		{desc: "base lockout at max attempts", failures: 3, lockout: time.Minute},
+		{desc: "lockout doubles", failures: 5, lockout: 4 * time.Minute},
+		{desc: "lockout capped", failures: 40, lockout: 10 * time.Minute},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			userID := uint64(1)
+			repoMock := mocks.NewLoginAttemptQuery(t)
+			repoMock.On("CreateLoginAttempt", context.Background(), model.LoginAttempt{
+				Email:  "user@mail.com",
+				UserID: &userID,
+				IP:     "10.0.0.1",
+				Reason: model.LOGIN_FAILURE_WRONG_PASSWORD,
+			}).Return(nil)
+			repoMock.On("RegisterLoginFailure", context.Background(), "account:user@mail.com", now, now.Add(-cfg.FailureWindow)).
+				Return(model.LoginThrottle{Key: "account:user@mail.com", Failures: tC.failures}, nil)
+			repoMock.On("RegisterLoginFailure", context.Background(), "ip:10.0.0.1", now, now.Add(-cfg.FailureWindow)).
+				Return(model.LoginThrottle{Key: "ip:10.0.0.1", Failures: 1}, nil)
+			if tC.lockout > 0 {
+				repoMock.On("LockLogin", context.Background(), "account:user@mail.com", now.Add(tC.lockout)).Return(nil)
+			}
+
+			guard := loginGuardImpl{repo: repoMock, cfg: cfg, now: func() time.Time { return now }}
+			err := guard.Failed(context.Background(), "user@mail.com", "10.0.0.1", &userID, model.LOGIN_FAILURE_WRONG_PASSWORD)
+			assert.Nil(t, err)
+		})
+	}
+}
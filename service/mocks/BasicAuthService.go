@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BasicAuthService is an autogenerated mock type for the BasicAuthService type
+type BasicAuthService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, email, password, ip
+func (_m *BasicAuthService) Authenticate(ctx context.Context, email string, password string, ip string) (model.Principal, error) {
+	ret := _m.Called(ctx, email, password, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 model.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (model.Principal, error)); ok {
+		return rf(ctx, email, password, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) model.Principal); ok {
+		r0 = rf(ctx, email, password, ip)
+	} else {
+		r0 = ret.Get(0).(model.Principal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, email, password, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBasicAuthService creates a new instance of BasicAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBasicAuthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BasicAuthService {
+	mock := &BasicAuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FeedService is an autogenerated mock type for the FeedService type
+type FeedService struct {
+	mock.Mock
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID, req
+func (_m *FeedService) GetFeed(ctx context.Context, userID uint64, req model.FeedRequest) (model.Feed, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 model.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.FeedRequest) (model.Feed, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.FeedRequest) model.Feed); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(model.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.FeedRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFeedService creates a new instance of FeedService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFeedService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FeedService {
+	mock := &FeedService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,131 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FollowService is an autogenerated mock type for the FollowService type
+type FollowService struct {
+	mock.Mock
+}
+
+// Follow provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *FollowService) Follow(ctx context.Context, followerID uint64, followeeID uint64) (model.Follow, error) {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Follow")
+	}
+
+	var r0 model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.Follow, error)); ok {
+		return rf(ctx, followerID, followeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.Follow); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Get(0).(model.Follow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, followerID, followeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Followers provides a mock function with given fields: ctx, userID, page
+func (_m *FollowService) Followers(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Followers")
+	}
+
+	var r0 model.FollowList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) (model.FollowList, error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) model.FollowList); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		r0 = ret.Get(0).(model.FollowList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.PageRequest) error); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Following provides a mock function with given fields: ctx, userID, page
+func (_m *FollowService) Following(ctx context.Context, userID uint64, page model.PageRequest) (model.FollowList, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Following")
+	}
+
+	var r0 model.FollowList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) (model.FollowList, error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) model.FollowList); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		r0 = ret.Get(0).(model.FollowList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.PageRequest) error); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfollow provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *FollowService) Unfollow(ctx context.Context, followerID uint64, followeeID uint64) error {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFollowService creates a new instance of FollowService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFollowService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FollowService {
+	mock := &FollowService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
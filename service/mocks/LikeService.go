@@ -0,0 +1,113 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LikeService is an autogenerated mock type for the LikeService type
+type LikeService struct {
+	mock.Mock
+}
+
+// Like provides a mock function with given fields: ctx, userID, photoID
+func (_m *LikeService) Like(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Like")
+	}
+
+	var r0 model.PhotoLikeStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) (model.PhotoLikeStatus, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) model.PhotoLikeStatus); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(model.PhotoLikeStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Likers provides a mock function with given fields: ctx, photoID, page
+func (_m *LikeService) Likers(ctx context.Context, photoID int, page model.PageRequest) (model.PhotoLikeList, error) {
+	ret := _m.Called(ctx, photoID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Likers")
+	}
+
+	var r0 model.PhotoLikeList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) (model.PhotoLikeList, error)); ok {
+		return rf(ctx, photoID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) model.PhotoLikeList); ok {
+		r0 = rf(ctx, photoID, page)
+	} else {
+		r0 = ret.Get(0).(model.PhotoLikeList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.PageRequest) error); ok {
+		r1 = rf(ctx, photoID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlike provides a mock function with given fields: ctx, userID, photoID
+func (_m *LikeService) Unlike(ctx context.Context, userID uint64, photoID int) (model.PhotoLikeStatus, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlike")
+	}
+
+	var r0 model.PhotoLikeStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) (model.PhotoLikeStatus, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) model.PhotoLikeStatus); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(model.PhotoLikeStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLikeService creates a new instance of LikeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLikeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LikeService {
+	mock := &LikeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,51 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetService is an autogenerated mock type for the PasswordResetService type
+type PasswordResetService struct {
+	mock.Mock
+}
+
+// Forgot provides a mock function with given fields: ctx, email
+func (_m *PasswordResetService) Forgot(ctx context.Context, email string) {
+	_m.Called(ctx, email)
+}
+
+// Reset provides a mock function with given fields: ctx, token, password
+func (_m *PasswordResetService) Reset(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordResetService creates a new instance of PasswordResetService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetService {
+	mock := &PasswordResetService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
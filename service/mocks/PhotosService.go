@@ -0,0 +1,226 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotosService is an autogenerated mock type for the PhotosService type
+type PhotosService struct {
+	mock.Mock
+}
+
+// CreatePhoto provides a mock function with given fields: ctx, photo, userId
+func (_m *PhotosService) CreatePhoto(ctx context.Context, photo model.CreatePhoto, userId int) (*model.Photo, error) {
+	ret := _m.Called(ctx, photo, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePhoto")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.CreatePhoto, int) (*model.Photo, error)); ok {
+		return rf(ctx, photo, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.CreatePhoto, int) *model.Photo); ok {
+		r0 = rf(ctx, photo, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.CreatePhoto, int) error); ok {
+		r1 = rf(ctx, photo, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePhoto provides a mock function with given fields: ctx, photoID, principal
+func (_m *PhotosService) DeletePhoto(ctx context.Context, photoID int, principal model.Principal) error {
+	ret := _m.Called(ctx, photoID, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePhoto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.Principal) error); ok {
+		r0 = rf(ctx, photoID, principal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllPhotos provides a mock function with given fields: ctx, viewerID
+func (_m *PhotosService) GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.PhotoGet, error) {
+	ret := _m.Called(ctx, viewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPhotos")
+	}
+
+	var r0 []model.PhotoGet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.PhotoGet, error)); ok {
+		return rf(ctx, viewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.PhotoGet); ok {
+		r0 = rf(ctx, viewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PhotoGet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhoto provides a mock function with given fields: ctx, photoID, principal
+func (_m *PhotosService) GetPhoto(ctx context.Context, photoID int, principal model.Principal) (*model.PhotoDetail, error) {
+	ret := _m.Called(ctx, photoID, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhoto")
+	}
+
+	var r0 *model.PhotoDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.Principal) (*model.PhotoDetail, error)); ok {
+		return rf(ctx, photoID, principal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.Principal) *model.PhotoDetail); ok {
+		r0 = rf(ctx, photoID, principal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PhotoDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.Principal) error); ok {
+		r1 = rf(ctx, photoID, principal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotoComments provides a mock function with given fields: ctx, photoID, req
+func (_m *PhotosService) GetPhotoComments(ctx context.Context, photoID int, req model.CommentsRequest) (model.PhotoComments, error) {
+	ret := _m.Called(ctx, photoID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotoComments")
+	}
+
+	var r0 model.PhotoComments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.CommentsRequest) (model.PhotoComments, error)); ok {
+		return rf(ctx, photoID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.CommentsRequest) model.PhotoComments); ok {
+		r0 = rf(ctx, photoID, req)
+	} else {
+		r0 = ret.Get(0).(model.PhotoComments)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.CommentsRequest) error); ok {
+		r1 = rf(ctx, photoID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePhoto provides a mock function with given fields: ctx, req, photoId, principal
+func (_m *PhotosService) UpdatePhoto(ctx context.Context, req model.UpdatePhoto, photoId int, principal model.Principal) (*model.PhotoUpdate, error) {
+	ret := _m.Called(ctx, req, photoId, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePhoto")
+	}
+
+	var r0 *model.PhotoUpdate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.UpdatePhoto, int, model.Principal) (*model.PhotoUpdate, error)); ok {
+		return rf(ctx, req, photoId, principal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.UpdatePhoto, int, model.Principal) *model.PhotoUpdate); ok {
+		r0 = rf(ctx, req, photoId, principal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PhotoUpdate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.UpdatePhoto, int, model.Principal) error); ok {
+		r1 = rf(ctx, req, photoId, principal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadPhoto provides a mock function with given fields: ctx, req, image, size, userId
+func (_m *PhotosService) UploadPhoto(ctx context.Context, req model.UploadPhoto, image io.Reader, size int64, userId int) (*model.Photo, error) {
+	ret := _m.Called(ctx, req, image, size, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadPhoto")
+	}
+
+	var r0 *model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.UploadPhoto, io.Reader, int64, int) (*model.Photo, error)); ok {
+		return rf(ctx, req, image, size, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.UploadPhoto, io.Reader, int64, int) *model.Photo); ok {
+		r0 = rf(ctx, req, image, size, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.UploadPhoto, io.Reader, int64, int) error); ok {
+		r1 = rf(ctx, req, image, size, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPhotosService creates a new instance of PhotosService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhotosService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhotosService {
+	mock := &PhotosService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProfileService is an autogenerated mock type for the ProfileService type
+type ProfileService struct {
+	mock.Mock
+}
+
+// GetPublicProfile provides a mock function with given fields: ctx, userID, page
+func (_m *ProfileService) GetPublicProfile(ctx context.Context, userID uint64, page model.PageRequest) (model.PublicProfile, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicProfile")
+	}
+
+	var r0 model.PublicProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) (model.PublicProfile, error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.PageRequest) model.PublicProfile); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		r0 = ret.Get(0).(model.PublicProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.PageRequest) error); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicProfileByUsername provides a mock function with given fields: ctx, username, page
+func (_m *ProfileService) GetPublicProfileByUsername(ctx context.Context, username string, page model.PageRequest) (model.PublicProfile, error) {
+	ret := _m.Called(ctx, username, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicProfileByUsername")
+	}
+
+	var r0 model.PublicProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PageRequest) (model.PublicProfile, error)); ok {
+		return rf(ctx, username, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PageRequest) model.PublicProfile); ok {
+		r0 = rf(ctx, username, page)
+	} else {
+		r0 = ret.Get(0).(model.PublicProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.PageRequest) error); ok {
+		r1 = rf(ctx, username, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewProfileService creates a new instance of ProfileService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProfileService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProfileService {
+	mock := &ProfileService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,11 +1,11 @@
-// Code generated by mockery v2.42.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
-
 	model "mygram/model"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +14,24 @@ type UserService struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: ctx, id, req
+func (_m *UserService) ChangePassword(ctx context.Context, id uint64, req model.ChangePasswordRequest) error {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.ChangePasswordRequest) error); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteUsersById provides a mock function with given fields: ctx, id
 func (_m *UserService) DeleteUsersById(ctx context.Context, id uint64) (model.User, error) {
 	ret := _m.Called(ctx, id)
@@ -70,6 +88,62 @@ func (_m *UserService) GenerateUserAccessToken(ctx context.Context, user model.U
 	return r0, r1
 }
 
+// GenerateUserRefreshToken provides a mock function with given fields: ctx, user
+func (_m *UserService) GenerateUserRefreshToken(ctx context.Context, user model.User) (string, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateUserRefreshToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.User) (string, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.User) string); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfile provides a mock function with given fields: ctx, id
+func (_m *UserService) GetProfile(ctx context.Context, id uint64) (model.UserProfile, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 model.UserProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.UserProfile, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.UserProfile); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.UserProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUsers provides a mock function with given fields: ctx
 func (_m *UserService) GetUsers(ctx context.Context) ([]model.User, error) {
 	ret := _m.Called(ctx)
@@ -128,6 +202,97 @@ func (_m *UserService) GetUsersById(ctx context.Context, id uint64) (model.User,
 	return r0, r1
 }
 
+// GetUsersByUsername provides a mock function with given fields: ctx, username
+func (_m *UserService) GetUsersByUsername(ctx context.Context, username string) (model.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByUsername")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshUserToken provides a mock function with given fields: ctx, refreshToken
+func (_m *UserService) RefreshUserToken(ctx context.Context, refreshToken string) (string, string, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshUserToken")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, string, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, refreshToken)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SignIn provides a mock function with given fields: ctx, userSignIn, ip
+func (_m *UserService) SignIn(ctx context.Context, userSignIn model.UserSignIn, ip string) (model.User, error) {
+	ret := _m.Called(ctx, userSignIn, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignIn")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserSignIn, string) (model.User, error)); ok {
+		return rf(ctx, userSignIn, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserSignIn, string) model.User); ok {
+		r0 = rf(ctx, userSignIn, ip)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.UserSignIn, string) error); ok {
+		r1 = rf(ctx, userSignIn, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SignUp provides a mock function with given fields: ctx, userSignUp
 func (_m *UserService) SignUp(ctx context.Context, userSignUp model.UserSignUp) (model.User, error) {
 	ret := _m.Called(ctx, userSignUp)
@@ -156,6 +321,62 @@ func (_m *UserService) SignUp(ctx context.Context, userSignUp model.UserSignUp)
 	return r0, r1
 }
 
+// UpdateUserByID provides a mock function with given fields: ctx, id, user
+func (_m *UserService) UpdateUserByID(ctx context.Context, id uint64, user model.User) (model.User, error) {
+	ret := _m.Called(ctx, id, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserByID")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.User) (model.User, error)); ok {
+		return rf(ctx, id, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.User) model.User); ok {
+		r0 = rf(ctx, id, user)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.User) error); ok {
+		r1 = rf(ctx, id, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUserRole provides a mock function with given fields: ctx, id, req
+func (_m *UserService) UpdateUserRole(ctx context.Context, id uint64, req model.UpdateUserRole) (model.User, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserRole")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.UpdateUserRole) (model.User, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.UpdateUserRole) model.User); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.UpdateUserRole) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
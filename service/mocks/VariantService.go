@@ -0,0 +1,88 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "mygram/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VariantService is an autogenerated mock type for the VariantService type
+type VariantService struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: photo
+func (_m *VariantService) Enqueue(photo model.Photo) bool {
+	ret := _m.Called(photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(model.Photo) bool); ok {
+		r0 = rf(photo)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Generate provides a mock function with given fields: ctx, photo
+func (_m *VariantService) Generate(ctx context.Context, photo model.Photo) error {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Generate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo) error); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Start provides a mock function with no fields
+func (_m *VariantService) Start() {
+	_m.Called()
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *VariantService) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewVariantService creates a new instance of VariantService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVariantService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VariantService {
+	mock := &VariantService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+var (
+	ErrUnknownOIDCProvider  = errors.New("unknown login provider")
+	ErrInvalidOIDCState     = errors.New("invalid or expired login state")
+	ErrOIDCLoginFailed      = errors.New("login with the provider failed")
+	ErrOIDCEmailNotVerified = errors.New("the provider did not verify the email address")
+	// ErrOIDCAccountNotVerified keeps whoever registered an address without
+	// owning it from getting the provider account linked to theirs.
+	ErrOIDCAccountNotVerified = errors.New("an account with this email exists but the email is not verified, log in with the password and verify it first")
+)
+
+type OIDCService interface {
+	// Begin starts a login with the provider, the browser has to come back
+	// to the callback with the returned state.
+	Begin(ctx context.Context, provider string) (authURL, state string, err error)
+	// Complete finishes the login and returns the user. Unknown identities
+	// are linked to the account with the same verified email, or get a new
+	// account.
+	Complete(ctx context.Context, provider, state, code string) (model.User, error)
+}
+
+type oidcServiceImpl struct {
+	userRepo  repository.UserQuery
+	repo      repository.OIDCQuery
+	providers map[string]infrastructure.OIDCProvider
+	cfg       config.OIDCConfig
+	password  helper.PasswordPolicy
+	now       func() time.Time
+}
+
+func NewOIDCService(userRepo repository.UserQuery, repo repository.OIDCQuery, providers map[string]infrastructure.OIDCProvider, cfg config.OIDCConfig, password helper.PasswordPolicy) OIDCService {
+	return &oidcServiceImpl{userRepo: userRepo, repo: repo, providers: providers, cfg: cfg, password: password, now: time.Now}
+}
+
+func (o *oidcServiceImpl) Begin(ctx context.Context, name string) (string, string, error) {
+	provider, ok := o.providers[name]
+	if !ok {
+		return "", "", ErrUnknownOIDCProvider
+	}
+
+	state, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, helper.PKCEChallenge(verifier))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	if err := o.repo.CreateOIDCState(ctx, model.OIDCState{
+		StateHash:    helper.HashToken(state),
+		Provider:     name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    o.now().Add(o.cfg.StateTTL),
+	}); err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+func (o *oidcServiceImpl) Complete(ctx context.Context, name, state, code string) (model.User, error) {
+	provider, ok := o.providers[name]
+	if !ok {
+		return model.User{}, ErrUnknownOIDCProvider
+	}
+
+	pending, err := o.repo.UseOIDCState(ctx, helper.HashToken(state))
+	if err != nil {
+		return model.User{}, err
+	}
+	if pending.StateHash == "" || pending.Provider != name {
+		return model.User{}, ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return model.User{}, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	if claims.Nonce != pending.Nonce {
+		return model.User{}, fmt.Errorf("%w: nonce mismatch", ErrOIDCLoginFailed)
+	}
+
+	identity, err := o.repo.GetUserIdentity(ctx, name, claims.Subject)
+	if err != nil {
+		return model.User{}, err
+	}
+	if identity.ID != 0 {
+		user, err := o.userRepo.GetUsersByID(ctx, identity.UserID)
+		if err != nil {
+			return model.User{}, err
+		}
+		if user.ID == 0 {
+			return model.User{}, fmt.Errorf("%w: the linked account was deleted", ErrOIDCLoginFailed)
+		}
+		return user, nil
+	}
+
+	// first login with this identity, link it by email
+	if claims.Email == "" || !claims.EmailVerified {
+		return model.User{}, ErrOIDCEmailNotVerified
+	}
+	user, err := o.userRepo.GetUsersByUsername(ctx, claims.Email)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID != 0 && user.VerifiedAt == nil {
+		return model.User{}, ErrOIDCAccountNotVerified
+	}
+	if user.ID == 0 {
+		user, err = o.createUser(ctx, claims)
+		if err != nil {
+			return model.User{}, err
+		}
+	}
+
+	if err := o.repo.CreateUserIdentity(ctx, model.UserIdentity{
+		UserID:   user.ID,
+		Provider: name,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (o *oidcServiceImpl) createUser(ctx context.Context, claims model.OIDCClaims) (model.User, error) {
+	// nobody knows this password, a real one can be set with a reset
+	password, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return model.User{}, err
+	}
+	hash, err := o.password.Hash(password)
+	if err != nil {
+		return model.User{}, err
+	}
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return model.User{}, err
+	}
+
+	verifiedAt := o.now()
+	return o.userRepo.CreateUser(ctx, model.User{
+		Username:   oidcUsername(claims) + "-" + hex.EncodeToString(suffix),
+		Email:      claims.Email,
+		Password:   hash,
+		Role:       model.ROLE_USER,
+		VerifiedAt: &verifiedAt,
+	})
+}
+
+// oidcUsername picks a username base from the claims, keeping letters,
+// digits, '.', '_' and '-'.
+func oidcUsername(claims model.OIDCClaims) string {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	username := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, base)
+	if runes := []rune(username); len(runes) > 32 {
+		username = string(runes[:32])
+	}
+	if username == "" {
+		return "user"
+	}
+	return username
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/infrastructure/oidctest"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	server := oidctest.NewServer("mygram")
+	defer server.Close()
+	provider := infrastructure.NewOIDCProvider(config.OIDCProviderConfig{
+		Name:        "stub",
+		Issuer:      server.URL,
+		ClientID:    "mygram",
+		RedirectURL: "http://localhost:3000/auth/oidc/stub/callback",
+	}, server.Client())
+	verifiedAt := time.Now()
+
+	// login runs Begin, lets the stub redirect back and returns the service
+	// with the repository mock ready for Complete, with state and code
+	login := func(t *testing.T, claims map[string]any) (oidcServiceImpl, *mocks.OIDCQuery, *mocks.UserQuery, string, string) {
+		server.Claims = claims
+		repoMock := mocks.NewOIDCQuery(t)
+		userMock := mocks.NewUserQuery(t)
+		var pending model.OIDCState
+		repoMock.On("CreateOIDCState", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+			pending = args.Get(1).(model.OIDCState)
+		}).Return(nil)
+
+		svc := oidcServiceImpl{
+			userRepo:  userMock,
+			repo:      repoMock,
+			providers: map[string]infrastructure.OIDCProvider{"stub": provider},
+			cfg:       config.OIDCConfig{StateTTL: time.Minute},
+			password:  helper.PasswordPolicy{Cost: 4},
+			now:       time.Now,
+		}
+		authURL, state, err := svc.Begin(context.Background(), "stub")
+		assert.Nil(t, err)
+		assert.Equal(t, helper.HashToken(state), pending.StateHash)
+
+		client := server.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		res, err := client.Get(authURL)
+		assert.Nil(t, err)
+		location, err := url.Parse(res.Header.Get("Location"))
+		assert.Nil(t, err)
+
+		repoMock.On("UseOIDCState", context.Background(), helper.HashToken(state)).Return(pending, nil)
+		return svc, repoMock, userMock, state, location.Query().Get("code")
+	}
+
+	t.Run("success known identity", func(t *testing.T) {
+		svc, repoMock, userMock, state, code := login(t, map[string]any{"sub": "123"})
+		repoMock.On("GetUserIdentity", context.Background(), "stub", "123").Return(model.UserIdentity{ID: 1, UserID: 7}, nil)
+		userMock.On("GetUsersByID", context.Background(), uint64(7)).Return(model.User{ID: 7}, nil)
+
+		user, err := svc.Complete(context.Background(), "stub", state, code)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(7), user.ID)
+	})
+
+	t.Run("success link by verified email", func(t *testing.T) {
+		svc, repoMock, userMock, state, code := login(t, map[string]any{"sub": "123", "email": "user@mail.com", "email_verified": true})
+		repoMock.On("GetUserIdentity", context.Background(), "stub", "123").Return(model.UserIdentity{}, nil)
+		userMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 7, VerifiedAt: &verifiedAt}, nil)
+		repoMock.On("CreateUserIdentity", context.Background(), model.UserIdentity{UserID: 7, Provider: "stub", Subject: "123", Email: "user@mail.com"}).Return(nil)
+
+		user, err := svc.Complete(context.Background(), "stub", state, code)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(7), user.ID)
+	})
+
+	t.Run("success create user", func(t *testing.T) {
+		svc, repoMock, userMock, state, code := login(t, map[string]any{"sub": "123", "email": "new.user@mail.com", "email_verified": true})
+		repoMock.On("GetUserIdentity", context.Background(), "stub", "123").Return(model.UserIdentity{}, nil)
+		userMock.On("GetUsersByUsername", context.Background(), "new.user@mail.com").Return(model.User{}, nil)
+		userMock.On("CreateUser", context.Background(), mock.MatchedBy(func(u model.User) bool {
+			return u.Email == "new.user@mail.com" && u.VerifiedAt != nil && u.Password != "" && len(u.Username) == len("new.user-")+6
+		})).Return(model.User{ID: 8, Email: "new.user@mail.com"}, nil)
+		repoMock.On("CreateUserIdentity", context.Background(), mock.MatchedBy(func(i model.UserIdentity) bool {
+			return i.UserID == 8 && i.Subject == "123"
+		})).Return(nil)
+
+		user, err := svc.Complete(context.Background(), "stub", state, code)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(8), user.ID)
+	})
+
+	t.Run("error local account not verified", func(t *testing.T) {
+		svc, repoMock, userMock, state, code := login(t, map[string]any{"sub": "123", "email": "user@mail.com", "email_verified": true})
+		repoMock.On("GetUserIdentity", context.Background(), "stub", "123").Return(model.UserIdentity{}, nil)
+		userMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 7}, nil)
+
+		_, err := svc.Complete(context.Background(), "stub", state, code)
+		assert.ErrorIs(t, err, ErrOIDCAccountNotVerified)
+	})
+
+	t.Run("error provider email not verified", func(t *testing.T) {
+		svc, repoMock, _, state, code := login(t, map[string]any{"sub": "123", "email": "user@mail.com", "email_verified": false})
+		repoMock.On("GetUserIdentity", context.Background(), "stub", "123").Return(model.UserIdentity{}, nil)
+
+		_, err := svc.Complete(context.Background(), "stub", state, code)
+		assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+	})
+
+	t.Run("error state of another provider", func(t *testing.T) {
+		repoMock := mocks.NewOIDCQuery(t)
+		repoMock.On("UseOIDCState", context.Background(), helper.HashToken("state")).Return(model.OIDCState{StateHash: "hash", Provider: "other"}, nil)
+
+		svc := oidcServiceImpl{repo: repoMock, providers: map[string]infrastructure.OIDCProvider{"stub": provider}, now: time.Now}
+		_, err := svc.Complete(context.Background(), "stub", "state", "code")
+		assert.ErrorIs(t, err, ErrInvalidOIDCState)
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// passwordResetTimeout bounds the background work started by Forgot.
+const passwordResetTimeout = 30 * time.Second
+
+type PasswordResetService interface {
+	// Forgot mails a reset link when an account uses email. The work runs
+	// in the background so callers can not tell whether the email exists,
+	// neither from the result nor from the response time.
+	Forgot(ctx context.Context, email string)
+	// Reset sets the new password, logs out every session of the user and
+	// revokes their api keys.
+	Reset(ctx context.Context, token, password string) error
+}
+
+type passwordResetServiceImpl struct {
+	userRepo repository.UserQuery
+	repo     repository.PasswordResetQuery
+	apiKeys  repository.APIKeyQuery
+	session  SessionService
+	mailer   infrastructure.Mailer
+	mail     config.MailConfig
+	password helper.PasswordPolicy
+}
+
+func NewPasswordResetService(userRepo repository.UserQuery, repo repository.PasswordResetQuery, apiKeys repository.APIKeyQuery, session SessionService, mailer infrastructure.Mailer, mail config.MailConfig, password helper.PasswordPolicy) PasswordResetService {
+	return &passwordResetServiceImpl{userRepo: userRepo, repo: repo, apiKeys: apiKeys, session: session, mailer: mailer, mail: mail, password: password}
+}
+
+func (p *passwordResetServiceImpl) Forgot(ctx context.Context, email string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetTimeout)
+	go func() {
+		defer cancel()
+		if err := p.sendReset(ctx, email); err != nil {
+			log.Println("cannot send password reset mail", err.Error())
+		}
+	}()
+}
+
+func (p *passwordResetServiceImpl) sendReset(ctx context.Context, email string) error {
+	user, err := p.userRepo.GetUsersByUsername(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return nil
+	}
+
+	latest, err := p.repo.GetLatestPasswordReset(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if time.Since(latest.CreatedAt) < p.mail.ResendGap {
+		return nil
+	}
+
+	token, err := helper.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	// only the newest link stays valid
+	if err := p.repo.DeleteUnusedPasswordResets(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := p.repo.CreatePasswordReset(ctx, model.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: helper.HashToken(token),
+		ExpiresAt: time.Now().Add(p.mail.PasswordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := strings.ReplaceAll(p.mail.PasswordResetURL, "{token}", url.QueryEscape(token))
+	return p.mailer.Send(ctx, infrastructure.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nsomebody asked to reset the password of your mygram account. Open the link below to choose a new one, it expires in %s and can be used once.\n\n%s\n\nIf it was not you, you can ignore this mail, your password is unchanged.\n",
+			user.Username, p.mail.PasswordResetTTL, link),
+	})
+}
+
+func (p *passwordResetServiceImpl) Reset(ctx context.Context, token, password string) error {
+	// checked first so a rejected password does not burn the token
+	if err := p.password.Validate(password); err != nil {
+		return err
+	}
+
+	reset, err := p.repo.UsePasswordReset(ctx, helper.HashToken(token))
+	if err != nil {
+		return err
+	}
+	if reset.ID == 0 {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := p.password.Hash(password)
+	if err != nil {
+		return err
+	}
+	if err := p.userRepo.UpdateUserPassword(ctx, reset.UserID, hash); err != nil {
+		return err
+	}
+	if err := p.repo.DeleteUnusedPasswordResets(ctx, reset.UserID); err != nil {
+		return err
+	}
+	// whoever knew the old password must not stay logged in, nor keep the
+	// api keys they could have made with it
+	if err := p.apiKeys.RevokeAPIKeysByUser(ctx, reset.UserID); err != nil {
+		return err
+	}
+	return p.session.RevokeAll(ctx, reset.UserID)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	imocks "mygram/infrastructure/mocks"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+	svcmocks "mygram/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordReset(t *testing.T) {
+	mailCfg := config.MailConfig{
+		PasswordResetURL: "http://localhost/reset?token={token}",
+		PasswordResetTTL: time.Hour,
+		ResendGap:        time.Minute,
+	}
+
+	t.Run("success unknown email sends nothing", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByUsername", context.Background(), "unknown@mail.com").Return(model.User{}, nil)
+
+		svc := passwordResetServiceImpl{userRepo: userMock, mail: mailCfg}
+		assert.Nil(t, svc.sendReset(context.Background(), "unknown@mail.com"))
+	})
+
+	t.Run("success mail stores only the token hash", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewPasswordResetQuery(t)
+		mailerMock := imocks.NewMailer(t)
+		userMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Email: "user@mail.com"}, nil)
+		repoMock.On("GetLatestPasswordReset", context.Background(), uint64(1)).Return(model.PasswordReset{}, nil)
+		repoMock.On("DeleteUnusedPasswordResets", context.Background(), uint64(1)).Return(nil)
+
+		var stored model.PasswordReset
+		repoMock.On("CreatePasswordReset", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(model.PasswordReset)
+		}).Return(nil)
+		var mailed infrastructure.Mail
+		mailerMock.On("Send", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+			mailed = args.Get(1).(infrastructure.Mail)
+		}).Return(nil)
+
+		svc := passwordResetServiceImpl{userRepo: userMock, repo: repoMock, mailer: mailerMock, mail: mailCfg}
+		assert.Nil(t, svc.sendReset(context.Background(), "user@mail.com"))
+
+		link := mailed.Body[strings.Index(mailed.Body, "http://localhost/reset?token="):]
+		token := strings.TrimPrefix(strings.Fields(link)[0], "http://localhost/reset?token=")
+		assert.Equal(t, helper.HashToken(token), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, token)
+	})
+
+	t.Run("success recent reset is not mailed again", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewPasswordResetQuery(t)
+		userMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Email: "user@mail.com"}, nil)
+		repoMock.On("GetLatestPasswordReset", context.Background(), uint64(1)).Return(model.PasswordReset{ID: 1, CreatedAt: time.Now()}, nil)
+
+		svc := passwordResetServiceImpl{userRepo: userMock, repo: repoMock, mail: mailCfg}
+		assert.Nil(t, svc.sendReset(context.Background(), "user@mail.com"))
+	})
+
+	t.Run("error invalid token", func(t *testing.T) {
+		repoMock := mocks.NewPasswordResetQuery(t)
+		repoMock.On("UsePasswordReset", context.Background(), helper.HashToken("unknown")).Return(model.PasswordReset{}, nil)
+
+		svc := passwordResetServiceImpl{repo: repoMock, mail: mailCfg, password: helper.DefaultPasswordPolicy}
+		assert.ErrorIs(t, svc.Reset(context.Background(), "unknown", "new-password"), ErrInvalidResetToken)
+	})
+
+	t.Run("success reset revokes sessions and api keys", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewPasswordResetQuery(t)
+		apiKeyMock := mocks.NewAPIKeyQuery(t)
+		sessionMock := svcmocks.NewSessionService(t)
+		repoMock.On("UsePasswordReset", context.Background(), helper.HashToken("valid")).Return(model.PasswordReset{ID: 1, UserID: 1}, nil)
+		userMock.On("UpdateUserPassword", context.Background(), uint64(1), mock.MatchedBy(func(hash string) bool {
+			match, _ := helper.CompareHash("new-password", hash)
+			return match
+		})).Return(nil)
+		repoMock.On("DeleteUnusedPasswordResets", context.Background(), uint64(1)).Return(nil)
+		apiKeyMock.On("RevokeAPIKeysByUser", context.Background(), uint64(1)).Return(nil)
+		sessionMock.On("RevokeAll", context.Background(), uint64(1)).Return(nil)
+
+		svc := passwordResetServiceImpl{userRepo: userMock, repo: repoMock, apiKeys: apiKeyMock, session: sessionMock, mail: mailCfg, password: helper.DefaultPasswordPolicy}
+		assert.Nil(t, svc.Reset(context.Background(), "valid", "new-password"))
+	})
+}
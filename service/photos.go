@@ -1,29 +1,69 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"mygram/config"
+	"mygram/infrastructure"
 	"mygram/model"
+	"mygram/pkg/helper"
 	"mygram/repository"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// SNIFF_BYTES is how much of an upload is read to detect its type.
+const SNIFF_BYTES = 3072
+
+var (
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrUnsupportedImageType = errors.New("unsupported image type")
 )
 
 type PhotosService interface {
-	GetAllPhotos(ctx context.Context) ([]model.PhotoGet, error)
-	UpdatePhoto(ctx context.Context, req model.UpdatePhoto, photoId, userID int) (*model.PhotoUpdate, error)
-	DeletePhoto(ctx context.Context, photoID int, userID int) error
+	GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.PhotoGet, error)
+	// GetPhoto returns the photo with its owner, likes and, when the
+	// principal may read them, first page of comments, or ErrPhotoNotFound.
+	GetPhoto(ctx context.Context, photoID int, principal model.Principal) (*model.PhotoDetail, error)
+	// GetPhotoComments returns model.ErrInvalidCommentCursor when the
+	// cursor was not issued by a previous page.
+	GetPhotoComments(ctx context.Context, photoID int, req model.CommentsRequest) (model.PhotoComments, error)
+	UpdatePhoto(ctx context.Context, req model.UpdatePhoto, photoId int, principal model.Principal) (*model.PhotoUpdate, error)
+	// DeletePhoto also removes the uploaded image and its variants from the
+	// blob store, a blob that cannot be removed is only logged.
+	DeletePhoto(ctx context.Context, photoID int, principal model.Principal) error
 	CreatePhoto(ctx context.Context, photo model.CreatePhoto, userId int) (*model.Photo, error)
+	// UploadPhoto stores the image in the blob store and creates a photo
+	// pointing to it. The type is detected from the content, the name and
+	// headers sent by the client are not trusted. The EXIF fields worth
+	// showing are kept on the photo, and the GPS position is removed from
+	// the image unless the owner asks to keep it.
+	UploadPhoto(ctx context.Context, req model.UploadPhoto, image io.Reader, size int64, userId int) (*model.Photo, error)
 }
 
 type photosServiceImpl struct {
-	repo repository.PhotosQuery
+	repo        repository.PhotosQuery
+	commentRepo repository.CommentsQuery
+	blobs       infrastructure.BlobStore
+	storage     config.StorageConfig
+	variants    VariantService
 }
 
-func NewPhotosService(repo repository.PhotosQuery) PhotosService {
-	return &photosServiceImpl{repo: repo}
+func NewPhotosService(repo repository.PhotosQuery, commentRepo repository.CommentsQuery, blobs infrastructure.BlobStore, storage config.StorageConfig, variants VariantService) PhotosService {
+	return &photosServiceImpl{repo: repo, commentRepo: commentRepo, blobs: blobs, storage: storage, variants: variants}
 }
 
-func (p *photosServiceImpl) GetAllPhotos(ctx context.Context) ([]model.PhotoGet, error) {
-	photos, err := p.repo.GetAllPhotos(ctx)
+func (p *photosServiceImpl) GetAllPhotos(ctx context.Context, viewerID uint64) ([]model.PhotoGet, error) {
+	photos, err := p.repo.GetAllPhotos(ctx, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -32,14 +72,93 @@ func (p *photosServiceImpl) GetAllPhotos(ctx context.Context) ([]model.PhotoGet,
 	return respPhotos, nil
 }
 
-func (p *photosServiceImpl) UpdatePhoto(ctx context.Context, req model.UpdatePhoto, photoId, userID int) (*model.PhotoUpdate, error) {
+func (p *photosServiceImpl) GetPhoto(ctx context.Context, photoID int, principal model.Principal) (*model.PhotoDetail, error) {
+	photo, err := p.repo.GetPhotoByID(ctx, photoID, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if photo == nil {
+		return nil, ErrPhotoNotFound
+	}
+
+	var comments *model.PhotoComments
+	if principal.HasScope(model.SCOPE_COMMENTS_READ) {
+		page, err := p.commentPage(ctx, photoID, nil, model.DEFAULT_PAGE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		comments = &page
+	}
+
+	return &model.PhotoDetail{
+		ID:      photo.ID,
+		Title:   photo.Title,
+		Caption: photo.Caption,
+		URL:     photo.URL,
+		UserID:  photo.UserID,
+		User: model.PhotoUserGet{
+			Email:    photo.User.Email,
+			Username: photo.User.Username,
+		},
+		Variants:  photo.Variants,
+		Metadata:  photo.Metadata,
+		LikeCount: photo.LikeCount,
+		LikedByMe: photo.LikedByMe,
+		Comments:  comments,
+		CreatedAt: photo.CreatedAt,
+		UpdatedAt: photo.UpdatedAt,
+	}, nil
+}
+
+func (p *photosServiceImpl) GetPhotoComments(ctx context.Context, photoID int, req model.CommentsRequest) (model.PhotoComments, error) {
+	var after *model.CommentCursor
+	if req.Cursor != "" {
+		cursor, err := model.DecodeCommentCursor(req.Cursor)
+		if err != nil {
+			return model.PhotoComments{}, err
+		}
+		after = &cursor
+	}
+
+	photo, err := p.repo.FindPhotoByID(ctx, photoID)
+	if err != nil {
+		return model.PhotoComments{}, err
+	}
+	if photo == nil {
+		return model.PhotoComments{}, ErrPhotoNotFound
+	}
+	return p.commentPage(ctx, photoID, after, req.Limit)
+}
+
+func (p *photosServiceImpl) commentPage(ctx context.Context, photoID int, after *model.CommentCursor, limit int) (model.PhotoComments, error) {
+	// one extra comment tells if there is a next page
+	comments, err := p.commentRepo.GetCommentsByPhotoID(ctx, photoID, after, limit+1)
+	if err != nil {
+		return model.PhotoComments{}, err
+	}
+	if len(comments) <= limit {
+		return model.PhotoComments{Comments: comments}, nil
+	}
+
+	comments = comments[:limit]
+	last := comments[len(comments)-1]
+	return model.PhotoComments{
+		Comments:   comments,
+		NextCursor: model.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(),
+	}, nil
+}
+
+func (p *photosServiceImpl) UpdatePhoto(ctx context.Context, req model.UpdatePhoto, photoId int, principal model.Principal) (*model.PhotoUpdate, error) {
 	currentPhoto, err := p.repo.FindPhotoByID(ctx, photoId)
 	if err != nil {
 		return nil, err
 	}
+	if currentPhoto == nil {
+		return nil, ErrPhotoNotFound
+	}
 
-	if currentPhoto.UserID != userID {
-		return nil, fmt.Errorf("Photo with id %d is not a photo owned by user with id %d.", photoId, userID)
+	if !canManage(principal, currentPhoto.UserID, model.PERMISSION_PHOTOS_MODERATE) {
+		return nil, fmt.Errorf("%w: photo with id %d is not a photo owned by user with id %d", ErrForbidden, photoId, principal.UserID)
 	}
 
 	newPhoto := &model.Photo{
@@ -52,21 +171,30 @@ func (p *photosServiceImpl) UpdatePhoto(ctx context.Context, req model.UpdatePho
 	if err != nil {
 		return nil, err
 	}
+	// the old variants are replaced once the new ones are made
+	if req.URL != "" && req.URL != currentPhoto.URL {
+		changed := *currentPhoto
+		changed.URL = req.URL
+		p.variants.Enqueue(changed)
+	}
 
 	responsePhoto := parseUpdatePhoto(updatedPhoto)
 
 	return responsePhoto, nil
 }
 
-func (p *photosServiceImpl) DeletePhoto(ctx context.Context, photoID int, userID int) error {
+func (p *photosServiceImpl) DeletePhoto(ctx context.Context, photoID int, principal model.Principal) error {
 	photo, err := p.repo.FindPhotoByID(ctx, photoID)
 	if err != nil {
 		return err
 
 	}
+	if photo == nil {
+		return ErrPhotoNotFound
+	}
 
-	if photo.UserID != userID {
-		return fmt.Errorf("Photo with id %d is not a photo owned by user with id %d.", photoID, userID)
+	if !canManage(principal, photo.UserID, model.PERMISSION_PHOTOS_MODERATE) {
+		return fmt.Errorf("%w: photo with id %d is not a photo owned by user with id %d", ErrForbidden, photoID, principal.UserID)
 	}
 
 	err = p.repo.DeletePhoto(ctx, photo)
@@ -74,7 +202,21 @@ func (p *photosServiceImpl) DeletePhoto(ctx context.Context, photoID int, userID
 		return fmt.Errorf("Error deleting photo: %v", err)
 	}
 
-	return err
+	// the row is only soft deleted, the blobs must not stay readable
+	keys := []string{}
+	if photo.StorageKey != "" {
+		keys = append(keys, photo.StorageKey)
+	}
+	for _, variant := range photo.Variants {
+		keys = append(keys, variant.Key)
+	}
+	for _, key := range keys {
+		if err := p.blobs.Delete(context.WithoutCancel(ctx), key); err != nil {
+			log.Println("cannot delete blob of a deleted photo", key, err.Error())
+		}
+	}
+
+	return nil
 }
 
 func (p *photosServiceImpl) CreatePhoto(ctx context.Context, req model.CreatePhoto, userId int) (*model.Photo, error) {
@@ -89,10 +231,85 @@ func (p *photosServiceImpl) CreatePhoto(ctx context.Context, req model.CreatePho
 	if err != nil {
 		return nil, err
 	}
+	p.variants.Enqueue(*resPhoto)
 
 	return resPhoto, nil
 }
 
+func (p *photosServiceImpl) UploadPhoto(ctx context.Context, req model.UploadPhoto, image io.Reader, size int64, userId int) (*model.Photo, error) {
+	if size > p.storage.MaxUploadBytes {
+		return nil, fmt.Errorf("%w: at most %d bytes", ErrImageTooLarge, p.storage.MaxUploadBytes)
+	}
+
+	// the whole image is needed to read and rewrite its metadata
+	content, err := io.ReadAll(io.LimitReader(image, size))
+	if err != nil {
+		return nil, err
+	}
+	contentType := mimetype.Detect(content[:min(len(content), SNIFF_BYTES)])
+	if !p.allowedType(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImageType, contentType.String())
+	}
+
+	metadata := helper.ReadImageMetadata(content)
+	if !req.KeepLocation {
+		content, err = helper.StripImageLocation(content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedImageType, err)
+		}
+	}
+
+	key, err := newBlobKey(userId, contentType.Extension())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.blobs.Put(ctx, key, bytes.NewReader(content), int64(len(content)), contentType.String()); err != nil {
+		return nil, err
+	}
+
+	photo, err := p.repo.CreatePhoto(ctx, &model.Photo{
+		Title:      req.Title,
+		Caption:    req.Caption,
+		URL:        strings.TrimSuffix(p.storage.PublicURL, "/") + "/" + key,
+		UserID:     userId,
+		StorageKey: key,
+		Metadata: model.PhotoMetadata{
+			TakenAt:     metadata.TakenAt,
+			CameraMake:  metadata.Make,
+			CameraModel: metadata.Model,
+			Orientation: metadata.Orientation,
+			Width:       metadata.Width,
+			Height:      metadata.Height,
+		},
+	})
+	if err != nil {
+		if err := p.blobs.Delete(ctx, key); err != nil {
+			log.Println("cannot delete blob of a failed upload", key, err.Error())
+		}
+		return nil, err
+	}
+	p.variants.Enqueue(*photo)
+	return photo, nil
+}
+
+func (p *photosServiceImpl) allowedType(contentType *mimetype.MIME) bool {
+	for _, allowed := range p.storage.AllowedTypes {
+		if contentType.Is(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBlobKey spreads the images by user and makes their URLs unguessable.
+func newBlobKey(userId int, extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return "photos/" + strconv.Itoa(userId) + "/" + hex.EncodeToString(random) + extension, nil
+}
+
 func parseGetAllPhotos(photos []model.Photo) []model.PhotoGet {
 	var parsedPhotos []model.PhotoGet
 	for _, photo := range photos {
@@ -106,6 +323,9 @@ func parseGetAllPhotos(photos []model.Photo) []model.PhotoGet {
 				Email:    photo.User.Email,
 				Username: photo.User.Username,
 			},
+			Variants:  photo.Variants,
+			LikeCount: photo.LikeCount,
+			LikedByMe: photo.LikedByMe,
 			CreatedAt: photo.CreatedAt,
 			UpdatedAt: photo.UpdatedAt,
 		}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	imocks "mygram/infrastructure/mocks"
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeletePhoto(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		principal model.Principal
+		err       error
+	}{
+		{desc: "success owner", principal: model.Principal{UserID: 1}},
+		{desc: "success moderator", principal: model.Principal{UserID: 2, Permissions: []string{model.PERMISSION_PHOTOS_MODERATE}}},
+		{desc: "error not owner", principal: model.Principal{UserID: 2, Permissions: []string{model.PERMISSION_COMMENTS_MODERATE}}, err: ErrForbidden},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			repoMock := mocks.NewPhotosQuery(t)
+			photo := &model.Photo{ID: 10, UserID: 1}
+			repoMock.On("FindPhotoByID", mock.Anything, 10).Return(photo, nil)
+			if tC.err == nil {
+				repoMock.On("DeletePhoto", mock.Anything, photo).Return(nil)
+			}
+
+			svc := photosServiceImpl{repo: repoMock}
+			err := svc.DeletePhoto(context.Background(), 10, tC.principal)
+			if tC.err != nil {
+				assert.ErrorIs(t, err, tC.err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	t.Run("success deletes blobs", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		blobMock := imocks.NewBlobStore(t)
+		photo := &model.Photo{ID: 10, UserID: 1, StorageKey: "photos/1/a.png", Variants: model.PhotoVariants{
+			{Width: 320, Format: model.VARIANT_FORMAT_JPEG, Key: "variants/10/320w-a.jpeg"},
+			{Width: 640, Format: model.VARIANT_FORMAT_JPEG, Key: "variants/10/640w-b.jpeg"},
+		}}
+		repoMock.On("FindPhotoByID", mock.Anything, 10).Return(photo, nil)
+		repoMock.On("DeletePhoto", mock.Anything, photo).Return(nil)
+		blobMock.On("Delete", mock.Anything, "photos/1/a.png").Return(nil)
+		blobMock.On("Delete", mock.Anything, "variants/10/320w-a.jpeg").Return(errors.New("store down"))
+		blobMock.On("Delete", mock.Anything, "variants/10/640w-b.jpeg").Return(nil)
+
+		svc := photosServiceImpl{repo: repoMock, blobs: blobMock}
+		err := svc.DeletePhoto(context.Background(), 10, model.Principal{UserID: 1})
+		assert.Nil(t, err)
+	})
+
+	t.Run("error not found", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("FindPhotoByID", mock.Anything, 10).Return(nil, nil)
+
+		svc := photosServiceImpl{repo: repoMock}
+		err := svc.DeletePhoto(context.Background(), 10, model.Principal{UserID: 1})
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestUpdatePhoto(t *testing.T) {
+	t.Run("error not found", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("FindPhotoByID", mock.Anything, 10).Return(nil, nil)
+
+		svc := photosServiceImpl{repo: repoMock}
+		_, err := svc.UpdatePhoto(context.Background(), model.UpdatePhoto{Title: "title"}, 10, model.Principal{UserID: 1})
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestGetPhoto(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success with owner likes and comments", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		commentMock := mocks.NewCommentsQuery(t)
+		repoMock.On("GetPhotoByID", mock.Anything, 10, uint64(2)).Return(&model.Photo{
+			ID:        10,
+			UserID:    1,
+			User:      model.User{Username: "owner"},
+			Metadata:  model.PhotoMetadata{CameraModel: "EOS 5D", Orientation: 6, Width: 2000, Height: 3000},
+			LikeCount: 3,
+			LikedByMe: true,
+		}, nil)
+		comments := make([]model.PhotoComment, model.DEFAULT_PAGE_SIZE+1)
+		for i := range comments {
+			comments[i] = model.PhotoComment{ID: i + 1, CreatedAt: createdAt.Add(time.Duration(i) * time.Second)}
+		}
+		commentMock.On("GetCommentsByPhotoID", mock.Anything, 10, (*model.CommentCursor)(nil), model.DEFAULT_PAGE_SIZE+1).Return(comments, nil)
+
+		svc := photosServiceImpl{repo: repoMock, commentRepo: commentMock}
+		photo, err := svc.GetPhoto(context.Background(), 10, model.Principal{UserID: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, "owner", photo.User.Username)
+		assert.Equal(t, "EOS 5D", photo.Metadata.CameraModel)
+		assert.Equal(t, int64(3), photo.LikeCount)
+		assert.True(t, photo.LikedByMe)
+		assert.Equal(t, model.DEFAULT_PAGE_SIZE, len(photo.Comments.Comments))
+		last := comments[model.DEFAULT_PAGE_SIZE-1]
+		assert.Equal(t, model.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(), photo.Comments.NextCursor)
+	})
+
+	t.Run("success api key without comments scope", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetPhotoByID", mock.Anything, 10, uint64(2)).Return(&model.Photo{ID: 10, UserID: 1}, nil)
+
+		svc := photosServiceImpl{repo: repoMock}
+		photo, err := svc.GetPhoto(context.Background(), 10, model.Principal{UserID: 2, APIKeyID: 5, Scopes: []string{model.SCOPE_PHOTOS_READ}})
+		assert.Nil(t, err)
+		assert.Equal(t, 10, photo.ID)
+		assert.Nil(t, photo.Comments)
+	})
+
+	t.Run("error not found", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetPhotoByID", mock.Anything, 10, uint64(2)).Return(nil, nil)
+
+		svc := photosServiceImpl{repo: repoMock}
+		_, err := svc.GetPhoto(context.Background(), 10, model.Principal{UserID: 2})
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestGetPhotoComments(t *testing.T) {
+	cursor := model.CommentCursor{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 7}
+
+	t.Run("success last page after cursor", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		commentMock := mocks.NewCommentsQuery(t)
+		repoMock.On("FindPhotoByID", mock.Anything, 10).Return(&model.Photo{ID: 10}, nil)
+		commentMock.On("GetCommentsByPhotoID", mock.Anything, 10, &cursor, 3).Return([]model.PhotoComment{{ID: 8}}, nil)
+
+		svc := photosServiceImpl{repo: repoMock, commentRepo: commentMock}
+		page, err := svc.GetPhotoComments(context.Background(), 10, model.CommentsRequest{Cursor: cursor.Encode(), Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []model.PhotoComment{{ID: 8}}, page.Comments)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("error invalid cursor", func(t *testing.T) {
+		svc := photosServiceImpl{}
+		_, err := svc.GetPhotoComments(context.Background(), 10, model.CommentsRequest{Cursor: "!", Limit: 2})
+		assert.ErrorIs(t, err, model.ErrInvalidCommentCursor)
+	})
+
+	t.Run("error photo not found", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("FindPhotoByID", mock.Anything, 10).Return(nil, nil)
+
+		svc := photosServiceImpl{repo: repoMock}
+		_, err := svc.GetPhotoComments(context.Background(), 10, model.CommentsRequest{Limit: 2})
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
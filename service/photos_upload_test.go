@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	pngenc "image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"mygram/config"
+	imocks "mygram/infrastructure/mocks"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+	svcmocks "mygram/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUploadPhoto(t *testing.T) {
+	storage := config.StorageConfig{
+		PublicURL:      "http://localhost:3000/blobs/",
+		MaxUploadBytes: 1024,
+		AllowedTypes:   []string{"image/png", "image/jpeg"},
+	}
+	encoded := &bytes.Buffer{}
+	assert.Nil(t, pngenc.Encode(encoded, image.NewGray(image.Rect(0, 0, 3, 2))))
+	png := encoded.Bytes()
+	isKey := mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "photos/1/") && strings.HasSuffix(key, ".png")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		blobMock := imocks.NewBlobStore(t)
+		repoMock := mocks.NewPhotosQuery(t)
+		blobMock.On("Put", mock.Anything, isKey, mock.Anything, int64(len(png)), "image/png").
+			Run(func(args mock.Arguments) {
+				content, _ := io.ReadAll(args.Get(2).(io.Reader))
+				assert.Equal(t, png, content)
+			}).
+			Return(nil)
+		repoMock.On("CreatePhoto", mock.Anything, mock.Anything).Return(func(ctx context.Context, photo *model.Photo) (*model.Photo, error) {
+			return photo, nil
+		})
+		variantMock := svcmocks.NewVariantService(t)
+		variantMock.On("Enqueue", mock.MatchedBy(func(photo model.Photo) bool {
+			return strings.HasPrefix(photo.StorageKey, "photos/1/")
+		})).Return(true)
+
+		svc := photosServiceImpl{repo: repoMock, blobs: blobMock, storage: storage, variants: variantMock}
+		photo, err := svc.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title"}, bytes.NewReader(png), int64(len(png)), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "http://localhost:3000/blobs/"+photo.StorageKey, photo.URL)
+		assert.Equal(t, 1, photo.UserID)
+	})
+
+	// a 3x2 png taken with "Cam" at a GPS position
+	le := binary.LittleEndian
+	entry := func(b []byte, tag, typ uint16, count uint32, value []byte) []byte {
+		b = le.AppendUint16(le.AppendUint16(b, tag), typ)
+		return append(le.AppendUint32(b, count), value...)
+	}
+	tiff := le.AppendUint32([]byte("II*\x00"), 8)
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = entry(tiff, 0x0110, 2, 4, []byte("Cam\x00"))
+	tiff = entry(tiff, 0x8825, 4, 1, le.AppendUint32(nil, 38))
+	tiff = le.AppendUint32(tiff, 0)
+	tiff = le.AppendUint16(tiff, 1)
+	tiff = entry(tiff, 1, 2, 2, []byte("N\x00\x00\x00"))
+	tiff = le.AppendUint32(tiff, 0)
+	exifChunk := binary.BigEndian.AppendUint32(nil, uint32(len(tiff)))
+	exifChunk = append(append(exifChunk, "eXIf"...), tiff...)
+	exifChunk = binary.BigEndian.AppendUint32(exifChunk, crc32.ChecksumIEEE(exifChunk[4:]))
+	// right after the header chunk
+	located := append(append(bytes.Clone(png[:33]), exifChunk...), png[33:]...)
+
+	testCases := []struct {
+		desc         string
+		keepLocation bool
+	}{
+		{desc: "success location removed"},
+		{desc: "success location kept", keepLocation: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			blobMock := imocks.NewBlobStore(t)
+			repoMock := mocks.NewPhotosQuery(t)
+			blobMock.On("Put", mock.Anything, isKey, mock.Anything, int64(len(located)), "image/png").
+				Run(func(args mock.Arguments) {
+					content, _ := io.ReadAll(args.Get(2).(io.Reader))
+					_, err := pngenc.Decode(bytes.NewReader(content))
+					assert.Nil(t, err)
+					assert.Equal(t, tC.keepLocation, helper.ReadImageMetadata(content).HasGPS)
+				}).
+				Return(nil)
+			repoMock.On("CreatePhoto", mock.Anything, mock.Anything).Return(func(ctx context.Context, photo *model.Photo) (*model.Photo, error) {
+				return photo, nil
+			})
+			variantMock := svcmocks.NewVariantService(t)
+			variantMock.On("Enqueue", mock.Anything).Return(true)
+
+			svc := photosServiceImpl{repo: repoMock, blobs: blobMock, storage: storage, variants: variantMock}
+			photo, err := svc.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title", KeepLocation: tC.keepLocation}, bytes.NewReader(located), int64(len(located)), 1)
+			assert.Nil(t, err)
+			assert.Equal(t, model.PhotoMetadata{CameraModel: "Cam", Width: 3, Height: 2}, photo.Metadata)
+		})
+	}
+
+	t.Run("error type not allowed", func(t *testing.T) {
+		svc := photosServiceImpl{storage: storage}
+		_, err := svc.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title"}, strings.NewReader("<svg></svg>"), 11, 1)
+		assert.ErrorIs(t, err, ErrUnsupportedImageType)
+	})
+
+	t.Run("error too large", func(t *testing.T) {
+		svc := photosServiceImpl{storage: storage}
+		_, err := svc.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title"}, bytes.NewReader(png), 2048, 1)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("error insert removes the blob", func(t *testing.T) {
+		blobMock := imocks.NewBlobStore(t)
+		repoMock := mocks.NewPhotosQuery(t)
+		blobMock.On("Put", mock.Anything, isKey, mock.Anything, int64(len(png)), "image/png").Return(nil)
+		blobMock.On("Delete", mock.Anything, isKey).Return(nil)
+		repoMock.On("CreatePhoto", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		svc := photosServiceImpl{repo: repoMock, blobs: blobMock, storage: storage}
+		_, err := svc.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title"}, bytes.NewReader(png), int64(len(png)), 1)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"mygram/model"
+	"mygram/repository"
+)
+
+type ProfileService interface {
+	// GetPublicProfile returns a zero profile when the user does not exist
+	// or was deleted.
+	GetPublicProfile(ctx context.Context, userID uint64, page model.PageRequest) (model.PublicProfile, error)
+	GetPublicProfileByUsername(ctx context.Context, username string, page model.PageRequest) (model.PublicProfile, error)
+}
+
+type profileServiceImpl struct {
+	userRepo        repository.UserQuery
+	photoRepo       repository.PhotosQuery
+	socialMediaRepo repository.SocialMediasQuery
+	followRepo      repository.FollowQuery
+}
+
+func NewProfileService(userRepo repository.UserQuery, photoRepo repository.PhotosQuery, socialMediaRepo repository.SocialMediasQuery, followRepo repository.FollowQuery) ProfileService {
+	return &profileServiceImpl{userRepo: userRepo, photoRepo: photoRepo, socialMediaRepo: socialMediaRepo, followRepo: followRepo}
+}
+
+func (p *profileServiceImpl) GetPublicProfile(ctx context.Context, userID uint64, page model.PageRequest) (model.PublicProfile, error) {
+	user, err := p.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return model.PublicProfile{}, err
+	}
+	return p.profile(ctx, user, page)
+}
+
+func (p *profileServiceImpl) GetPublicProfileByUsername(ctx context.Context, username string, page model.PageRequest) (model.PublicProfile, error) {
+	user, err := p.userRepo.GetUsersByName(ctx, username)
+	if err != nil {
+		return model.PublicProfile{}, err
+	}
+	return p.profile(ctx, user, page)
+}
+
+func (p *profileServiceImpl) profile(ctx context.Context, user model.User, page model.PageRequest) (model.PublicProfile, error) {
+	if user.ID == 0 {
+		return model.PublicProfile{}, nil
+	}
+
+	counts, err := p.followRepo.CountFollows(ctx, user.ID)
+	if err != nil {
+		return model.PublicProfile{}, err
+	}
+	photos, total, err := p.photoRepo.GetPhotosByUserID(ctx, user.ID, page.PageSize, page.Offset())
+	if err != nil {
+		return model.PublicProfile{}, err
+	}
+	socialMedias, err := p.socialMediaRepo.GetSocialMediasByUserID(ctx, user.ID)
+	if err != nil {
+		return model.PublicProfile{}, err
+	}
+
+	return model.PublicProfile{
+		User: model.PublicUser{
+			ID:        user.ID,
+			Username:  user.Username,
+			CreatedAt: user.CreatedAt,
+		},
+		FollowCounts: counts,
+		Photos:       photos,
+		PhotosPage:   model.PageInfo{Page: page.Page, PageSize: page.PageSize, Total: total},
+		SocialMedias: socialMedias,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mygram/model"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPublicProfile(t *testing.T) {
+	page := model.PageRequest{Page: 2, PageSize: 10}
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success by username", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		photoMock := mocks.NewPhotosQuery(t)
+		socialMediaMock := mocks.NewSocialMediasQuery(t)
+		followMock := mocks.NewFollowQuery(t)
+		userMock.On("GetUsersByName", context.Background(), "user").Return(model.User{ID: 1, Username: "user", Email: "user@mail.com", CreatedAt: createdAt}, nil)
+		followMock.On("CountFollows", context.Background(), uint64(1)).Return(model.FollowCounts{FollowerCount: 2, FollowingCount: 1}, nil)
+		photoMock.On("GetPhotosByUserID", context.Background(), uint64(1), 10, 10).Return([]model.Photo{{ID: 3}}, int64(11), nil)
+		socialMediaMock.On("GetSocialMediasByUserID", context.Background(), uint64(1)).Return([]model.SocialMedias{{ID: 4}}, nil)
+
+		svc := profileServiceImpl{userRepo: userMock, photoRepo: photoMock, socialMediaRepo: socialMediaMock, followRepo: followMock}
+		profile, err := svc.GetPublicProfileByUsername(context.Background(), "user", page)
+		assert.Nil(t, err)
+		assert.Equal(t, model.PublicProfile{
+			User:         model.PublicUser{ID: 1, Username: "user", CreatedAt: createdAt},
+			FollowCounts: model.FollowCounts{FollowerCount: 2, FollowingCount: 1},
+			Photos:       []model.Photo{{ID: 3}},
+			PhotosPage:   model.PageInfo{Page: 2, PageSize: 10, Total: 11},
+			SocialMedias: []model.SocialMedias{{ID: 4}},
+		}, profile)
+	})
+
+	t.Run("success deleted user", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{}, nil)
+
+		svc := profileServiceImpl{userRepo: userMock}
+		profile, err := svc.GetPublicProfile(context.Background(), 1, page)
+		assert.Nil(t, err)
+		assert.Zero(t, profile.User.ID)
+	})
+
+	t.Run("error photos", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		photoMock := mocks.NewPhotosQuery(t)
+		followMock := mocks.NewFollowQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		followMock.On("CountFollows", context.Background(), uint64(1)).Return(model.FollowCounts{}, nil)
+		photoMock.On("GetPhotosByUserID", context.Background(), uint64(1), 10, 10).Return(nil, int64(0), errors.New("some error"))
+
+		svc := profileServiceImpl{userRepo: userMock, photoRepo: photoMock, followRepo: followMock}
+		_, err := svc.GetPublicProfile(context.Background(), 1, page)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+type SessionService interface {
+	// Revoke logs out a single access token and, when given, the refresh
+	// token family it was issued with.
+	Revoke(ctx context.Context, jti string, userID uint64, expiresAt time.Time, refreshToken string) error
+	// RevokeAll logs out every session of the user issued until now.
+	RevokeAll(ctx context.Context, userID uint64) error
+	IsRevoked(ctx context.Context, jti string, userID uint64, issuedAt time.Time) (bool, error)
+}
+
+type sessionServiceImpl struct {
+	revocationRepo repository.TokenRevocationQuery
+	refreshRepo    repository.RefreshTokenQuery
+}
+
+func NewSessionService(revocationRepo repository.TokenRevocationQuery, refreshRepo repository.RefreshTokenQuery) SessionService {
+	return &sessionServiceImpl{revocationRepo: revocationRepo, refreshRepo: refreshRepo}
+}
+
+func (s *sessionServiceImpl) Revoke(ctx context.Context, jti string, userID uint64, expiresAt time.Time, refreshToken string) error {
+	if err := s.revocationRepo.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		token, err := s.refreshRepo.GetRefreshTokenByHash(ctx, helper.HashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+		// never let a user revoke somebody else's refresh token
+		if token.ID != 0 && token.UserID == userID {
+			if err := s.refreshRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// expired tokens are rejected anyway, no need to remember them
+	return s.revocationRepo.DeleteExpiredRevokedTokens(ctx)
+}
+
+func (s *sessionServiceImpl) RevokeAll(ctx context.Context, userID uint64) error {
+	if err := s.revocationRepo.SetUserTokensRevokedBefore(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+	return s.refreshRepo.RevokeRefreshTokensByUserID(ctx, userID)
+}
+
+func (s *sessionServiceImpl) IsRevoked(ctx context.Context, jti string, userID uint64, issuedAt time.Time) (bool, error) {
+	revokedBefore, err := s.revocationRepo.GetUserTokensRevokedBefore(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	// iat only has second precision, compare on whole seconds
+	if issuedAt.Unix() < revokedBefore.Unix() {
+		return true, nil
+	}
+
+	return s.revocationRepo.IsTokenRevoked(ctx, jti)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRevoked(t *testing.T) {
+	now := time.Now()
+
+	t.Run("revoked by logout all watermark", func(t *testing.T) {
+		revocationMock := mocks.NewTokenRevocationQuery(t)
+		revocationMock.On("GetUserTokensRevokedBefore", context.Background(), uint64(1)).Return(now, nil)
+
+		svc := sessionServiceImpl{revocationRepo: revocationMock}
+		revoked, err := svc.IsRevoked(context.Background(), "jti", 1, now.Add(-time.Minute))
+		assert.Nil(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("revoked by jti", func(t *testing.T) {
+		revocationMock := mocks.NewTokenRevocationQuery(t)
+		revocationMock.On("GetUserTokensRevokedBefore", context.Background(), uint64(1)).Return(time.Time{}, nil)
+		revocationMock.On("IsTokenRevoked", context.Background(), "jti").Return(true, nil)
+
+		svc := sessionServiceImpl{revocationRepo: revocationMock}
+		revoked, err := svc.IsRevoked(context.Background(), "jti", 1, now)
+		assert.Nil(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("token issued after watermark", func(t *testing.T) {
+		revocationMock := mocks.NewTokenRevocationQuery(t)
+		revocationMock.On("GetUserTokensRevokedBefore", context.Background(), uint64(1)).Return(now.Add(-time.Hour), nil)
+		revocationMock.On("IsTokenRevoked", context.Background(), "jti").Return(false, nil)
+
+		svc := sessionServiceImpl{revocationRepo: revocationMock}
+		revoked, err := svc.IsRevoked(context.Background(), "jti", 1, now)
+		assert.Nil(t, err)
+		assert.False(t, revoked)
+	})
+}
@@ -10,8 +10,8 @@ import (
 type SocialMediasService interface {
 	CreateSocialMedia(ctx context.Context, data model.SocialMediaCreate, userID int) (*model.SocialMedias, error)
 	GetAllSocialMedia(ctx context.Context) ([]model.SocialMediaGet, error)
-	UpdateSocialMedia(ctx context.Context, data model.UpdateSocialMedia, smID int, userID int) (*model.SocialMediaUpdate, error)
-	DeleteSocialMedia(ctx context.Context, smID, userID int) error
+	UpdateSocialMedia(ctx context.Context, data model.UpdateSocialMedia, smID int, principal model.Principal) (*model.SocialMediaUpdate, error)
+	DeleteSocialMedia(ctx context.Context, smID int, principal model.Principal) error
 }
 
 type socialmediasServiceImpl struct {
@@ -32,14 +32,14 @@ func (sm *socialmediasServiceImpl) GetAllSocialMedia(ctx context.Context) ([]mod
 	return respSocialMedias, nil
 }
 
-func (sm *socialmediasServiceImpl) UpdateSocialMedia(ctx context.Context, data model.UpdateSocialMedia, smID int, userID int) (*model.SocialMediaUpdate, error) {
+func (sm *socialmediasServiceImpl) UpdateSocialMedia(ctx context.Context, data model.UpdateSocialMedia, smID int, principal model.Principal) (*model.SocialMediaUpdate, error) {
 	currentSocialMedia, err := sm.repo.FindSocialMediaByID(ctx, smID)
 	if err != nil {
 		return nil, err
 	}
 
-	if currentSocialMedia.UserID != userID {
-		return nil, fmt.Errorf("Social Media with id %d is not a Social Media owned by user with id %d.", smID, userID)
+	if !canManage(principal, currentSocialMedia.UserID, model.PERMISSION_SOCIAL_MEDIAS_MODERATE) {
+		return nil, fmt.Errorf("%w: social media with id %d is not a social media owned by user with id %d", ErrForbidden, smID, principal.UserID)
 	}
 
 	newSocialMedia := &model.SocialMedias{
@@ -63,15 +63,15 @@ func (sm *socialmediasServiceImpl) UpdateSocialMedia(ctx context.Context, data m
 	return respData, nil
 }
 
-func (sm *socialmediasServiceImpl) DeleteSocialMedia(ctx context.Context, smID, userID int) error {
+func (sm *socialmediasServiceImpl) DeleteSocialMedia(ctx context.Context, smID int, principal model.Principal) error {
 	socialmedia, err := sm.repo.FindSocialMediaByID(ctx, smID)
 	if err != nil {
 		return err
 
 	}
 
-	if socialmedia.UserID != userID {
-		return fmt.Errorf("Social Media  with id %d is not a social media owned by user with id %d.", socialmedia.UserID, userID)
+	if !canManage(principal, socialmedia.UserID, model.PERMISSION_SOCIAL_MEDIAS_MODERATE) {
+		return fmt.Errorf("%w: social media with id %d is not a social media owned by user with id %d", ErrForbidden, smID, principal.UserID)
 	}
 
 	err = sm.repo.DeleteSocialMedia(ctx, socialmedia)
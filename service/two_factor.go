@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("start a two-factor enrollment first")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrInvalidChallengeToken   = errors.New("invalid or expired two-factor challenge")
+)
+
+type TwoFactorService interface {
+	// Enroll creates a new TOTP secret, two-factor stays off until Confirm.
+	Enroll(ctx context.Context, userID uint64) (model.TOTPEnrollment, error)
+	// Confirm turns two-factor on with a first code of the enrolled secret
+	// and returns the recovery codes, they are not shown again.
+	Confirm(ctx context.Context, userID uint64, code string) (recoveryCodes []string, err error)
+	// Challenge returns the token a login with a correct password gets
+	// instead of a session when two-factor is on.
+	Challenge(ctx context.Context, user model.User) (token string, err error)
+	// Login finishes a login started with a challenge. Wrong codes count
+	// against the login throttle of the account.
+	Login(ctx context.Context, req model.TwoFactorLoginRequest, ip string) (model.User, error)
+	// Disable turns two-factor off, the password and a second factor are
+	// both required.
+	Disable(ctx context.Context, userID uint64, req model.DisableTwoFactorRequest) error
+}
+
+type twoFactorServiceImpl struct {
+	userRepo repository.UserQuery
+	repo     repository.TwoFactorQuery
+	guard    LoginGuard
+	cfg      config.TwoFactorConfig
+	jwt      config.JWTConfig
+	keys     *helper.KeySet
+	now      func() time.Time
+}
+
+func NewTwoFactorService(userRepo repository.UserQuery, repo repository.TwoFactorQuery, guard LoginGuard, cfg config.TwoFactorConfig, jwt config.JWTConfig, keys *helper.KeySet) TwoFactorService {
+	return &twoFactorServiceImpl{userRepo: userRepo, repo: repo, guard: guard, cfg: cfg, jwt: jwt, keys: keys, now: time.Now}
+}
+
+func (t *twoFactorServiceImpl) Enroll(ctx context.Context, userID uint64) (model.TOTPEnrollment, error) {
+	user, err := t.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return model.TOTPEnrollment{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := helper.GenerateTOTPSecret()
+	if err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+	if err := t.repo.SaveUserTOTP(ctx, model.UserTOTP{UserID: userID, Secret: secret}); err != nil {
+		return model.TOTPEnrollment{}, err
+	}
+	return model.TOTPEnrollment{
+		Secret: secret,
+		URI:    helper.TOTPURI(t.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (t *twoFactorServiceImpl) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	user, err := t.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	totp, err := t.repo.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.UserID == 0 {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	step, ok := helper.ValidateTOTP(totp.Secret, code, t.now(), t.cfg.Skew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, 0, t.cfg.RecoveryCodes)
+	hashes := make([]string, 0, t.cfg.RecoveryCodes)
+	for i := 0; i < t.cfg.RecoveryCodes; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, helper.HashToken(normalizeRecoveryCode(code)))
+	}
+	enabled, err := t.repo.EnableTwoFactor(ctx, userID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	return codes, nil
+}
+
+func (t *twoFactorServiceImpl) Challenge(ctx context.Context, user model.User) (string, error) {
+	jti, err := helper.GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := t.now()
+	return helper.GenerateToken(model.TwoFactorChallengeClaim{
+		StandardClaim: model.StandardClaim{
+			Jti: jti,
+			Iss: t.jwt.Issuer,
+			Aud: t.jwt.Audience,
+			Sub: model.SUBJECT_TWO_FACTOR_CHALLENGE_TOKEN,
+			Exp: uint64(now.Add(t.cfg.ChallengeTTL).Unix()),
+			Iat: uint64(now.Unix()),
+			Nbf: uint64(now.Unix()),
+		},
+		UserID: user.ID,
+	}, t.keys)
+}
+
+func (t *twoFactorServiceImpl) Login(ctx context.Context, req model.TwoFactorLoginRequest, ip string) (model.User, error) {
+	claims, err := helper.ValidateToken(req.ChallengeToken, t.keys, helper.TokenExpectation{
+		Issuer:   t.jwt.Issuer,
+		Audience: t.jwt.Audience,
+		Subject:  model.SUBJECT_TWO_FACTOR_CHALLENGE_TOKEN,
+		Leeway:   t.jwt.Leeway,
+	})
+	if err != nil {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+	claim := model.TwoFactorChallengeClaim{}
+	if err := helper.DecodeClaim(claims, &claim); err != nil || claim.UserID == 0 {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+
+	user, err := t.userRepo.GetUsersByID(ctx, claim.UserID)
+	if err != nil {
+		return model.User{}, err
+	}
+	// two-factor may have been turned off since the challenge was issued
+	if user.ID == 0 || user.TwoFactorEnabledAt == nil {
+		return model.User{}, ErrInvalidChallengeToken
+	}
+
+	if err := t.guard.Check(ctx, user.Email, ip); err != nil {
+		return model.User{}, err
+	}
+	ok, err := t.verify(ctx, user.ID, req.SecondFactor)
+	if err != nil {
+		return model.User{}, err
+	}
+	if !ok {
+		if err := t.guard.Failed(ctx, user.Email, ip, &user.ID, model.LOGIN_FAILURE_SECOND_FACTOR); err != nil {
+			return model.User{}, err
+		}
+		return model.User{}, ErrInvalidTwoFactorCode
+	}
+	if err := t.guard.Succeeded(ctx, user.Email); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (t *twoFactorServiceImpl) Disable(ctx context.Context, userID uint64, req model.DisableTwoFactorRequest) error {
+	user, err := t.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	match, err := helper.CompareHash(req.Password, user.Password)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return ErrWrongPassword
+	}
+	ok, err := t.verify(ctx, userID, req.SecondFactor)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return t.repo.DisableTwoFactor(ctx, userID)
+}
+
+// verify checks a TOTP code or else a recovery code, each can only be
+// used once.
+func (t *twoFactorServiceImpl) verify(ctx context.Context, userID uint64, factor model.SecondFactor) (bool, error) {
+	if factor.Code != "" {
+		totp, err := t.repo.GetUserTOTP(ctx, userID)
+		if err != nil || totp.UserID == 0 {
+			return false, err
+		}
+		step, ok := helper.ValidateTOTP(totp.Secret, factor.Code, t.now(), t.cfg.Skew)
+		if !ok {
+			return false, nil
+		}
+		return t.repo.UseTOTPStep(ctx, userID, step)
+	}
+	if factor.RecoveryCode != "" {
+		return t.repo.UseRecoveryCode(ctx, userID, helper.HashToken(normalizeRecoveryCode(factor.RecoveryCode)))
+	}
+	return false, nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns 50 random bits as "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode lets users type codes with or without the dash and
+// in any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
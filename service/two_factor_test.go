@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+	svcmocks "mygram/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTwoFactor(t *testing.T) {
+	jwtCfg := config.JWTConfig{Issuer: "project", Audience: "mygram", Leeway: time.Second}
+	cfg := config.TwoFactorConfig{Issuer: "mygram", Skew: 1, ChallengeTTL: 5 * time.Minute, RecoveryCodes: 3}
+	keys := helper.NewHMACKeySet("a-very-long-secret-used-for-testing-only")
+	now := time.Unix(1700000000, 0)
+	secret, err := helper.GenerateTOTPSecret()
+	assert.Nil(t, err)
+	code, err := helper.TOTPCode(secret, helper.TOTPStep(now))
+	assert.Nil(t, err)
+	hash, err := helper.GenerateHash("password")
+	assert.Nil(t, err)
+	enabledAt := now
+	user := model.User{ID: 1, Email: "user@mail.com", Password: hash, TwoFactorEnabledAt: &enabledAt}
+
+	newService := func(userMock *mocks.UserQuery, repoMock *mocks.TwoFactorQuery, guardMock *svcmocks.LoginGuard) twoFactorServiceImpl {
+		return twoFactorServiceImpl{userRepo: userMock, repo: repoMock, guard: guardMock, cfg: cfg, jwt: jwtCfg, keys: keys, now: func() time.Time { return now }}
+	}
+
+	t.Run("success confirm stores only recovery code hashes", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewTwoFactorQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		repoMock.On("GetUserTOTP", context.Background(), uint64(1)).Return(model.UserTOTP{UserID: 1, Secret: secret}, nil)
+
+		var hashes []string
+		repoMock.On("EnableTwoFactor", context.Background(), uint64(1), helper.TOTPStep(now), mock.Anything).Run(func(args mock.Arguments) {
+			hashes = args.Get(3).([]string)
+		}).Return(true, nil)
+
+		svc := newService(userMock, repoMock, nil)
+		codes, err := svc.Confirm(context.Background(), 1, code)
+		assert.Nil(t, err)
+		assert.Len(t, codes, 3)
+		assert.Len(t, hashes, 3)
+		for i, code := range codes {
+			assert.Equal(t, helper.HashToken(normalizeRecoveryCode(code)), hashes[i])
+		}
+	})
+
+	t.Run("error confirm wrong code", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewTwoFactorQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		repoMock.On("GetUserTOTP", context.Background(), uint64(1)).Return(model.UserTOTP{UserID: 1, Secret: secret}, nil)
+
+		svc := newService(userMock, repoMock, nil)
+		_, err := svc.Confirm(context.Background(), 1, "000000")
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("success login with challenge", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewTwoFactorQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(user, nil)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUserTOTP", context.Background(), uint64(1)).Return(model.UserTOTP{UserID: 1, Secret: secret}, nil)
+		repoMock.On("UseTOTPStep", context.Background(), uint64(1), helper.TOTPStep(now)).Return(true, nil)
+		guardMock.On("Succeeded", context.Background(), "user@mail.com").Return(nil)
+
+		svc := newService(userMock, repoMock, guardMock)
+		// the challenge must still be valid for the real clock
+		svc.now = time.Now
+		challenge, err := svc.Challenge(context.Background(), user)
+		assert.Nil(t, err)
+		svc.now = func() time.Time { return now }
+
+		res, err := svc.Login(context.Background(), model.TwoFactorLoginRequest{ChallengeToken: challenge, SecondFactor: model.SecondFactor{Code: code}}, "10.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), res.ID)
+	})
+
+	t.Run("error login replayed code counts as failure", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewTwoFactorQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(user, nil)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUserTOTP", context.Background(), uint64(1)).Return(model.UserTOTP{UserID: 1, Secret: secret}, nil)
+		repoMock.On("UseTOTPStep", context.Background(), uint64(1), helper.TOTPStep(now)).Return(false, nil)
+		guardMock.On("Failed", context.Background(), "user@mail.com", "10.0.0.1", mock.Anything, model.LOGIN_FAILURE_SECOND_FACTOR).Return(nil)
+
+		svc := newService(userMock, repoMock, guardMock)
+		svc.now = time.Now
+		challenge, err := svc.Challenge(context.Background(), user)
+		assert.Nil(t, err)
+		svc.now = func() time.Time { return now }
+
+		_, err = svc.Login(context.Background(), model.TwoFactorLoginRequest{ChallengeToken: challenge, SecondFactor: model.SecondFactor{Code: code}}, "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("error login with access token as challenge", func(t *testing.T) {
+		token, err := (&userServiceImpl{jwt: jwtCfg, keys: keys}).GenerateUserAccessToken(context.Background(), user)
+		assert.Nil(t, err)
+
+		svc := newService(nil, nil, nil)
+		_, err = svc.Login(context.Background(), model.TwoFactorLoginRequest{ChallengeToken: token, SecondFactor: model.SecondFactor{Code: code}}, "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidChallengeToken)
+	})
+
+	t.Run("success disable with recovery code", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		repoMock := mocks.NewTwoFactorQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(user, nil)
+		repoMock.On("UseRecoveryCode", context.Background(), uint64(1), helper.HashToken("abcde12345")).Return(true, nil)
+		repoMock.On("DisableTwoFactor", context.Background(), uint64(1)).Return(nil)
+
+		svc := newService(userMock, repoMock, nil)
+		err := svc.Disable(context.Background(), 1, model.DisableTwoFactorRequest{Password: "password", SecondFactor: model.SecondFactor{RecoveryCode: " ABCDE-12345 "}})
+		assert.Nil(t, err)
+	})
+
+	t.Run("error disable wrong password", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(user, nil)
+
+		svc := newService(userMock, nil, nil)
+		err := svc.Disable(context.Background(), 1, model.DisableTwoFactorRequest{Password: "wrong", SecondFactor: model.SecondFactor{Code: code}})
+		assert.ErrorIs(t, err, ErrWrongPassword)
+	})
+}
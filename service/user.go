@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
+	"mygram/config"
 	"mygram/model"
 	"mygram/pkg/helper"
 	"mygram/repository"
@@ -13,24 +18,63 @@ import (
 type UserService interface {
 	GetUsers(ctx context.Context) ([]model.User, error)
 	GetUsersById(ctx context.Context, id uint64) (model.User, error)
+	// GetProfile returns a zero profile when the user does not exist.
+	GetProfile(ctx context.Context, id uint64) (model.UserProfile, error)
 	DeleteUsersById(ctx context.Context, id uint64) (model.User, error)
 	UpdateUserByID(ctx context.Context, id uint64, user model.User) (model.User, error)
+	UpdateUserRole(ctx context.Context, id uint64, req model.UpdateUserRole) (model.User, error)
 	GetUsersByUsername(ctx context.Context, username string) (model.User, error)
 
 	// activity
 	SignUp(ctx context.Context, userSignUp model.UserSignUp) (model.User, error)
-	SignIn(ctx context.Context, userSignIn model.UserSignIn) (model.User, error)
+	// SignIn returns ErrInvalidCredentials for unknown emails and wrong
+	// passwords alike, and a *LoginLockedError while locked out. Users with
+	// two-factor on still have to pass TwoFactorService.Login.
+	SignIn(ctx context.Context, userSignIn model.UserSignIn, ip string) (model.User, error)
+	// ChangePassword logs out every session of the user once the password
+	// changed.
+	ChangePassword(ctx context.Context, id uint64, req model.ChangePasswordRequest) error
 
 	// misc
 	GenerateUserAccessToken(ctx context.Context, user model.User) (token string, err error)
+	GenerateUserRefreshToken(ctx context.Context, user model.User) (token string, err error)
+	RefreshUserToken(ctx context.Context, refreshToken string) (accessToken, newRefreshToken string, err error)
 }
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used, all sessions of this login were revoked")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrSamePassword        = errors.New("new password must differ from the current one")
+)
+
 type userServiceImpl struct {
-	repo repository.UserQuery
+	repo        repository.UserQuery
+	refreshRepo repository.RefreshTokenQuery
+	session     SessionService
+	guard       LoginGuard
+	password    helper.PasswordPolicy
+	jwt         config.JWTConfig
+	keys        *helper.KeySet
+	// dummyHash is compared against when the email is unknown, so those
+	// logins take as long as the ones with a wrong password.
+	dummyHash func() string
 }
 
-func NewUserService(repo repository.UserQuery) UserService {
-	return &userServiceImpl{repo: repo}
+func NewUserService(repo repository.UserQuery, refreshRepo repository.RefreshTokenQuery, session SessionService, guard LoginGuard, password helper.PasswordPolicy, jwt config.JWTConfig, keys *helper.KeySet) UserService {
+	return &userServiceImpl{
+		repo:        repo,
+		refreshRepo: refreshRepo,
+		session:     session,
+		guard:       guard,
+		password:    password,
+		jwt:         jwt,
+		keys:        keys,
+		dummyHash: sync.OnceValue(func() string {
+			hash, _ := password.Hash("mygram-dummy-password")
+			return hash
+		}),
+	}
 }
 
 func (u *userServiceImpl) GetUsersByUsername(ctx context.Context, email string) (model.User, error) {
@@ -57,6 +101,22 @@ func (u *userServiceImpl) GetUsersById(ctx context.Context, id uint64) (model.Us
 	return user, err
 }
 
+func (u *userServiceImpl) GetProfile(ctx context.Context, id uint64) (model.UserProfile, error) {
+	user, err := u.repo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.UserProfile{}, err
+	}
+	if user.ID == 0 {
+		return model.UserProfile{}, nil
+	}
+
+	stats, err := u.repo.GetUserStats(ctx, id)
+	if err != nil {
+		return model.UserProfile{}, err
+	}
+	return model.UserProfile{User: user, UserStats: stats}, nil
+}
+
 func (u *userServiceImpl) DeleteUsersById(ctx context.Context, id uint64) (model.User, error) {
 	user, err := u.repo.GetUsersByID(ctx, id)
 	if err != nil {
@@ -73,6 +133,12 @@ func (u *userServiceImpl) DeleteUsersById(ctx context.Context, id uint64) (model
 		return model.User{}, err
 	}
 
+	// tokens of a deleted account must stop working right away
+	err = u.session.RevokeAll(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+
 	return user, err
 }
 
@@ -88,6 +154,14 @@ func (u *userServiceImpl) UpdateUserByID(ctx context.Context, id uint64, user mo
 		return model.User{}, fmt.Errorf("user with ID %d not found", id)
 	}
 
+	// a new address has to be verified again
+	if user.Email != "" && !strings.EqualFold(user.Email, existingUser.Email) && existingUser.VerifiedAt != nil {
+		if err := u.repo.SetUserVerifiedAt(ctx, id, nil); err != nil {
+			return model.User{}, err
+		}
+		existingUser.VerifiedAt = nil
+	}
+
 	// Update user fields
 	existingUser.Username = user.Username
 	existingUser.Email = user.Email
@@ -102,19 +176,45 @@ func (u *userServiceImpl) UpdateUserByID(ctx context.Context, id uint64, user mo
 	return updatedUser, nil
 }
 
+func (u *userServiceImpl) UpdateUserRole(ctx context.Context, id uint64, req model.UpdateUserRole) (model.User, error) {
+	user, err := u.repo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, nil
+	}
+
+	if err := u.repo.UpdateUserRole(ctx, id, req.Role, req.Permissions); err != nil {
+		return model.User{}, err
+	}
+	// tokens carry the old permissions, make the user log in again
+	if err := u.session.RevokeAll(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	user.Role = req.Role
+	user.Permissions = req.Permissions
+	return user, nil
+}
+
 func (u *userServiceImpl) SignUp(ctx context.Context, userSignUp model.UserSignUp) (model.User, error) {
 	// assumption: semua user adalah user baru
 	user := model.User{
 		Username: userSignUp.Username,
 		Email:    userSignUp.Email,
 		DoB:      userSignUp.DoB,
+		Role:     model.ROLE_USER,
 		// FirstName: userSignUp.FirstName,
 		// LastName:  userSignUp.LastName,
 	}
 
 	// encryption password
+	if err := u.password.Validate(userSignUp.Password); err != nil {
+		return model.User{}, err
+	}
 	// hashing
-	pass, err := helper.GenerateHash(userSignUp.Password)
+	pass, err := u.password.Hash(userSignUp.Password)
 	if err != nil {
 		return model.User{}, err
 	}
@@ -128,16 +228,32 @@ func (u *userServiceImpl) SignUp(ctx context.Context, userSignUp model.UserSignU
 	return res, err
 }
 
-func (u *userServiceImpl) SignIn(ctx context.Context, userSignIn model.UserSignIn) (model.User, error) {
-	// Get user by username
+func (u *userServiceImpl) SignIn(ctx context.Context, userSignIn model.UserSignIn, ip string) (model.User, error) {
+	if err := u.guard.Check(ctx, userSignIn.Email, ip); err != nil {
+		var locked *LoginLockedError
+		if errors.As(err, &locked) {
+			if err := u.guard.Failed(ctx, userSignIn.Email, ip, nil, model.LOGIN_FAILURE_LOCKED); err != nil {
+				return model.User{}, err
+			}
+		}
+		return model.User{}, err
+	}
+
+	// Get user by email
 	user, err := u.repo.GetUsersByUsername(ctx, userSignIn.Email)
 	if err != nil {
 		return model.User{}, err
 	}
 
-	// Check if user exists
+	// Check if user exists, still paying for a compare
 	if user.ID == 0 {
-		return model.User{}, fmt.Errorf("user with username %s not found", userSignIn.Email)
+		if _, err := helper.CompareHash(userSignIn.Password, u.dummyHash()); err != nil {
+			return model.User{}, err
+		}
+		if err := u.guard.Failed(ctx, userSignIn.Email, ip, nil, model.LOGIN_FAILURE_UNKNOWN_EMAIL); err != nil {
+			return model.User{}, err
+		}
+		return model.User{}, ErrInvalidCredentials
 	}
 
 	// Check if password matches
@@ -146,22 +262,81 @@ func (u *userServiceImpl) SignIn(ctx context.Context, userSignIn model.UserSignI
 		return model.User{}, err
 	}
 	if !match {
-		return model.User{}, fmt.Errorf("invalid password")
+		if err := u.guard.Failed(ctx, userSignIn.Email, ip, &user.ID, model.LOGIN_FAILURE_WRONG_PASSWORD); err != nil {
+			return model.User{}, err
+		}
+		return model.User{}, ErrInvalidCredentials
+	}
+
+	// with two-factor on the login is only done after the second step, so
+	// the throttle keeps counting wrong codes until then
+	if user.TwoFactorEnabledAt == nil {
+		if err := u.guard.Succeeded(ctx, userSignIn.Email); err != nil {
+			return model.User{}, err
+		}
 	}
 
+	// the plain password is only known now, upgrade hashes made with an
+	// older bcrypt cost
+	if u.password.NeedsRehash(user.Password) {
+		if hash, err := u.password.Hash(userSignIn.Password); err == nil {
+			if err := u.repo.UpdateUserPassword(ctx, user.ID, hash); err != nil {
+				log.Println("cannot rehash password", err.Error())
+			} else {
+				user.Password = hash
+			}
+		}
+	}
 	return user, nil
 }
 
+func (u *userServiceImpl) ChangePassword(ctx context.Context, id uint64, req model.ChangePasswordRequest) error {
+	user, err := u.repo.GetUsersByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return fmt.Errorf("user with ID %d not found", id)
+	}
+
+	match, err := helper.CompareHash(req.CurrentPassword, user.Password)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return ErrWrongPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return ErrSamePassword
+	}
+	if err := u.password.Validate(req.NewPassword); err != nil {
+		return err
+	}
+
+	hash, err := u.password.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.UpdateUserPassword(ctx, id, hash); err != nil {
+		return err
+	}
+	return u.session.RevokeAll(ctx, id)
+}
+
 func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user model.User) (token string, err error) {
-	// generate claim
+	// the jti is the revocation key, it must never repeat
+	jti, err := helper.GenerateRandomToken(16)
+	if err != nil {
+		return
+	}
 	now := time.Now()
 
 	claim := model.StandardClaim{
-		Jti: fmt.Sprintf("%v", time.Now().UnixNano()),
-		Iss: "project",
-		Aud: "mygram",
-		Sub: "access-token",
-		Exp: uint64(now.Add(time.Hour).Unix()),
+		Jti: jti,
+		Iss: u.jwt.Issuer,
+		Aud: u.jwt.Audience,
+		Sub: model.SUBJECT_ACCESS_TOKEN,
+		Exp: uint64(now.Add(u.jwt.AccessTTL).Unix()),
 		Iat: uint64(now.Unix()),
 		Nbf: uint64(now.Unix()),
 	}
@@ -171,8 +346,81 @@ func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user mode
 		UserID:        user.ID,
 		Username:      user.Username,
 		Dob:           user.DoB,
+		Role:          user.Role,
+		Permissions:   user.EffectivePermissions(),
 	}
 
-	token, err = helper.GenerateToken(userClaim)
+	token, err = helper.GenerateToken(userClaim, u.keys)
 	return
 }
+
+func (u *userServiceImpl) GenerateUserRefreshToken(ctx context.Context, user model.User) (token string, err error) {
+	// every login starts a new token family
+	familyID, err := helper.GenerateRandomToken(16)
+	if err != nil {
+		return
+	}
+	return u.issueRefreshToken(ctx, user.ID, familyID)
+}
+
+func (u *userServiceImpl) RefreshUserToken(ctx context.Context, refreshToken string) (accessToken, newRefreshToken string, err error) {
+	current, err := u.refreshRepo.GetRefreshTokenByHash(ctx, helper.HashToken(refreshToken))
+	if err != nil {
+		return
+	}
+	if current.ID == 0 || current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	// a refresh token can only be used once, seeing it again means it leaked
+	if current.UsedAt != nil {
+		return "", "", u.revokeFamily(ctx, current.FamilyID)
+	}
+	marked, err := u.refreshRepo.MarkRefreshTokenUsed(ctx, current.ID)
+	if err != nil {
+		return
+	}
+	if !marked {
+		return "", "", u.revokeFamily(ctx, current.FamilyID)
+	}
+
+	user, err := u.repo.GetUsersByID(ctx, current.UserID)
+	if err != nil {
+		return
+	}
+	if user.ID == 0 {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	accessToken, err = u.GenerateUserAccessToken(ctx, user)
+	if err != nil {
+		return
+	}
+	newRefreshToken, err = u.issueRefreshToken(ctx, user.ID, current.FamilyID)
+	return
+}
+
+func (u *userServiceImpl) issueRefreshToken(ctx context.Context, userID uint64, familyID string) (token string, err error) {
+	token, err = helper.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	_, err = u.refreshRepo.CreateRefreshToken(ctx, model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: helper.HashToken(token),
+		ExpiresAt: time.Now().Add(u.jwt.RefreshTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return
+}
+
+func (u *userServiceImpl) revokeFamily(ctx context.Context, familyID string) error {
+	if err := u.refreshRepo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
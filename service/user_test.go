@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"mygram/config"
 	"mygram/model"
+	"mygram/pkg/helper"
 	"mygram/repository/mocks"
+	svcmocks "mygram/service/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetUsers(t *testing.T) {
@@ -105,3 +110,235 @@ func TestGetUserById(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateUserAccessToken(t *testing.T) {
+	jwtCfg := config.JWTConfig{Issuer: "project", Audience: "mygram", AccessTTL: time.Hour}
+	keys := helper.NewHMACKeySet("a-very-long-secret-used-for-testing-only")
+
+	t.Run("success every token has its own jti", func(t *testing.T) {
+		svc := userServiceImpl{jwt: jwtCfg, keys: keys}
+		jtis := map[string]bool{}
+		for i := 0; i < 100; i++ {
+			token, err := svc.GenerateUserAccessToken(context.Background(), model.User{ID: 1})
+			assert.Nil(t, err)
+			claims, err := helper.ValidateToken(token, keys, helper.TokenExpectation{Subject: model.SUBJECT_ACCESS_TOKEN})
+			assert.Nil(t, err)
+			claim := model.AccessClaim{}
+			assert.Nil(t, helper.DecodeClaim(claims, &claim))
+			// 16 random bytes
+			assert.Equal(t, 22, len(claim.Jti))
+			jtis[claim.Jti] = true
+		}
+		assert.Equal(t, 100, len(jtis))
+	})
+}
+
+func TestRefreshUserToken(t *testing.T) {
+	jwtCfg := config.JWTConfig{
+		Secret:     "a-very-long-secret-used-for-testing-only",
+		Issuer:     "project",
+		Audience:   "mygram",
+		AccessTTL:  time.Hour,
+		RefreshTTL: 24 * time.Hour,
+	}
+
+	t.Run("error unknown refresh token", func(t *testing.T) {
+		refreshMock := mocks.NewRefreshTokenQuery(t)
+		refreshMock.On("GetRefreshTokenByHash", context.Background(), helper.HashToken("unknown")).Return(model.RefreshToken{}, nil)
+
+		svc := userServiceImpl{refreshRepo: refreshMock, jwt: jwtCfg}
+		_, _, err := svc.RefreshUserToken(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("error reused refresh token revokes family", func(t *testing.T) {
+		usedAt := time.Now().Add(-time.Minute)
+		refreshMock := mocks.NewRefreshTokenQuery(t)
+		refreshMock.On("GetRefreshTokenByHash", context.Background(), helper.HashToken("used")).Return(model.RefreshToken{
+			ID:        1,
+			UserID:    1,
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(time.Hour),
+			UsedAt:    &usedAt,
+		}, nil)
+		refreshMock.On("RevokeRefreshTokenFamily", context.Background(), "family").Return(nil)
+
+		svc := userServiceImpl{refreshRepo: refreshMock, jwt: jwtCfg}
+		_, _, err := svc.RefreshUserToken(context.Background(), "used")
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("success rotate refresh token", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		refreshMock := mocks.NewRefreshTokenQuery(t)
+		refreshMock.On("GetRefreshTokenByHash", context.Background(), helper.HashToken("valid")).Return(model.RefreshToken{
+			ID:        1,
+			UserID:    1,
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		refreshMock.On("MarkRefreshTokenUsed", context.Background(), uint64(1)).Return(true, nil)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Username: "user1"}, nil)
+		refreshMock.On("CreateRefreshToken", context.Background(), mock.MatchedBy(func(token model.RefreshToken) bool {
+			return token.UserID == 1 && token.FamilyID == "family"
+		})).Return(model.RefreshToken{ID: 2}, nil)
+
+		svc := userServiceImpl{repo: repoMock, refreshRepo: refreshMock, jwt: jwtCfg, keys: helper.NewHMACKeySet(jwtCfg.Secret)}
+		accessToken, refreshToken, err := svc.RefreshUserToken(context.Background(), "valid")
+		assert.Nil(t, err)
+		assert.NotEqual(t, "", accessToken)
+		assert.NotEqual(t, "valid", refreshToken)
+	})
+}
+
+func TestSignIn(t *testing.T) {
+	hash, err := helper.GenerateHash("password")
+	assert.Nil(t, err)
+
+	t.Run("error unknown email", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "unknown@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "unknown@mail.com").Return(model.User{}, nil)
+		guardMock.On("Failed", context.Background(), "unknown@mail.com", "10.0.0.1", (*uint64)(nil), model.LOGIN_FAILURE_UNKNOWN_EMAIL).Return(nil)
+
+		svc := userServiceImpl{repo: repoMock, guard: guardMock, dummyHash: func() string { return hash }}
+		_, err := svc.SignIn(context.Background(), model.UserSignIn{Email: "unknown@mail.com", Password: "password"}, "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: hash}, nil)
+		guardMock.On("Failed", context.Background(), "user@mail.com", "10.0.0.1", mock.MatchedBy(func(id *uint64) bool {
+			return id != nil && *id == 1
+		}), model.LOGIN_FAILURE_WRONG_PASSWORD).Return(nil)
+
+		svc := userServiceImpl{repo: repoMock, guard: guardMock}
+		_, err := svc.SignIn(context.Background(), model.UserSignIn{Email: "user@mail.com", Password: "wrong"}, "10.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("error locked", func(t *testing.T) {
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(&LoginLockedError{RetryAfter: time.Minute})
+		guardMock.On("Failed", context.Background(), "user@mail.com", "10.0.0.1", (*uint64)(nil), model.LOGIN_FAILURE_LOCKED).Return(nil)
+
+		svc := userServiceImpl{guard: guardMock}
+		_, err := svc.SignIn(context.Background(), model.UserSignIn{Email: "user@mail.com", Password: "password"}, "10.0.0.1")
+		var locked *LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: hash}, nil)
+		guardMock.On("Succeeded", context.Background(), "user@mail.com").Return(nil)
+
+		svc := userServiceImpl{repo: repoMock, guard: guardMock}
+		user, err := svc.SignIn(context.Background(), model.UserSignIn{Email: "user@mail.com", Password: "password"}, "10.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), user.ID)
+	})
+
+	t.Run("success with two-factor keeps throttle", func(t *testing.T) {
+		enabledAt := time.Now()
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: hash, TwoFactorEnabledAt: &enabledAt}, nil)
+
+		svc := userServiceImpl{repo: repoMock, guard: guardMock}
+		user, err := svc.SignIn(context.Background(), model.UserSignIn{Email: "user@mail.com", Password: "password"}, "10.0.0.1")
+		assert.Nil(t, err)
+		assert.NotNil(t, user.TwoFactorEnabledAt)
+		guardMock.AssertNotCalled(t, "Succeeded", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success rehash with new cost", func(t *testing.T) {
+		oldHash, err := helper.PasswordPolicy{Cost: 4}.Hash("password")
+		assert.Nil(t, err)
+
+		repoMock := mocks.NewUserQuery(t)
+		guardMock := svcmocks.NewLoginGuard(t)
+		guardMock.On("Check", context.Background(), "user@mail.com", "10.0.0.1").Return(nil)
+		repoMock.On("GetUsersByUsername", context.Background(), "user@mail.com").Return(model.User{ID: 1, Password: oldHash}, nil)
+		guardMock.On("Succeeded", context.Background(), "user@mail.com").Return(nil)
+		repoMock.On("UpdateUserPassword", context.Background(), uint64(1), mock.MatchedBy(func(hash string) bool {
+			return !(helper.PasswordPolicy{Cost: 5}).NeedsRehash(hash)
+		})).Return(nil)
+
+		svc := userServiceImpl{repo: repoMock, guard: guardMock, password: helper.PasswordPolicy{Cost: 5}}
+		_, err = svc.SignIn(context.Background(), model.UserSignIn{Email: "user@mail.com", Password: "password"}, "10.0.0.1")
+		assert.Nil(t, err)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := helper.GenerateHash("current-password")
+	assert.Nil(t, err)
+	policy := helper.PasswordPolicy{MinLength: 8, RejectCommon: true, Cost: 4}
+
+	testCases := []struct {
+		desc string
+		req  model.ChangePasswordRequest
+		err  error
+	}{
+		{desc: "error wrong current password", req: model.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "brand-new-password"}, err: ErrWrongPassword},
+		{desc: "error same password", req: model.ChangePasswordRequest{CurrentPassword: "current-password", NewPassword: "current-password"}, err: ErrSamePassword},
+		{desc: "error common password", req: model.ChangePasswordRequest{CurrentPassword: "current-password", NewPassword: "password123"}, err: &helper.PasswordPolicyError{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			repoMock := mocks.NewUserQuery(t)
+			repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Password: hash}, nil)
+
+			svc := userServiceImpl{repo: repoMock, password: policy}
+			err := svc.ChangePassword(context.Background(), 1, tC.req)
+			if policyErr, ok := tC.err.(*helper.PasswordPolicyError); ok {
+				assert.ErrorAs(t, err, &policyErr)
+			} else {
+				assert.ErrorIs(t, err, tC.err)
+			}
+		})
+	}
+
+	t.Run("success revokes sessions", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		sessionMock := svcmocks.NewSessionService(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Password: hash}, nil)
+		repoMock.On("UpdateUserPassword", context.Background(), uint64(1), mock.Anything).Return(nil)
+		sessionMock.On("RevokeAll", context.Background(), uint64(1)).Return(nil)
+
+		svc := userServiceImpl{repo: repoMock, session: sessionMock, password: policy}
+		err := svc.ChangePassword(context.Background(), 1, model.ChangePasswordRequest{CurrentPassword: "current-password", NewPassword: "brand-new-password"})
+		assert.Nil(t, err)
+	})
+}
+
+func TestGetProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		repoMock.On("GetUserStats", context.Background(), uint64(1)).Return(model.UserStats{PhotoCount: 2}, nil)
+
+		svc := userServiceImpl{repo: repoMock}
+		profile, err := svc.GetProfile(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, model.UserProfile{User: model.User{ID: 1}, UserStats: model.UserStats{PhotoCount: 2}}, profile)
+	})
+
+	t.Run("success deleted user skips stats", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{}, nil)
+
+		svc := userServiceImpl{repo: repoMock}
+		profile, err := svc.GetProfile(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Zero(t, profile.ID)
+	})
+}
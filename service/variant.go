@@ -0,0 +1,408 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository"
+
+	_ "golang.org/x/image/webp"
+)
+
+// VARIANT_JOB_TIMEOUT bounds the download, resizing and upload of the
+// variants of one photo.
+const VARIANT_JOB_TIMEOUT = 2 * time.Minute
+
+// VARIANT_CATCH_UP_BATCH is how many photos the catch-up pass reads at once.
+const VARIANT_CATCH_UP_BATCH = 100
+
+var ErrImageTooManyPixels = errors.New("image has too many pixels")
+
+type VariantService interface {
+	// Enqueue schedules the variants of a photo to be made in the
+	// background. It never blocks and reports false when the queue is full,
+	// the photo is then picked up by the next catch-up pass.
+	Enqueue(photo model.Photo) bool
+	// Generate makes the variants of a photo now and stores them on it,
+	// replacing the ones it had.
+	Generate(ctx context.Context, photo model.Photo) error
+	// Start runs the workers and the catch-up pass, once now and then every
+	// catch-up interval. The pass queues the photos whose variants are
+	// missing or were made from another URL, waiting for room in the queue.
+	Start()
+	// Stop waits for the queued photos to be done until ctx ends, the
+	// running jobs are then cancelled and ctx.Err() returned. What is left
+	// is picked up by the catch-up pass of the next start.
+	Stop(ctx context.Context) error
+}
+
+type variantServiceImpl struct {
+	repo    repository.PhotosQuery
+	blobs   infrastructure.BlobStore
+	fetcher infrastructure.Fetcher
+	storage config.StorageConfig
+	cfg     config.VariantsConfig
+
+	jobs chan model.Photo
+	// quit wakes the catch-up pass when it waits for room in the queue
+	quit chan struct{}
+	// ctx is the parent of every job, cancelled when Stop gives up
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	stopped  bool
+	stopOnce sync.Once
+
+	// pending holds the URL of the queued and running photos, so the
+	// catch-up pass does not queue them again meanwhile
+	pendingMu sync.Mutex
+	pending   map[int]string
+}
+
+func NewVariantService(repo repository.PhotosQuery, blobs infrastructure.BlobStore, fetcher infrastructure.Fetcher, storage config.StorageConfig) VariantService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &variantServiceImpl{
+		repo:    repo,
+		blobs:   blobs,
+		fetcher: fetcher,
+		storage: storage,
+		cfg:     storage.Variants,
+		jobs:    make(chan model.Photo, storage.Variants.QueueSize),
+		quit:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: map[int]string{},
+	}
+}
+
+func (v *variantServiceImpl) Enqueue(photo model.Photo) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.stopped {
+		return false
+	}
+	if !v.markPending(photo) {
+		return true
+	}
+	select {
+	case v.jobs <- photo:
+		return true
+	default:
+		v.unmarkPending(photo)
+		log.Println("variant queue is full, leaving photo to the catch-up pass", photo.ID)
+		return false
+	}
+}
+
+// enqueueWait is Enqueue waiting for room in the queue, it reports false
+// when the service stops first.
+func (v *variantServiceImpl) enqueueWait(photo model.Photo) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.stopped {
+		return false
+	}
+	if !v.markPending(photo) {
+		return true
+	}
+	select {
+	case v.jobs <- photo:
+		return true
+	case <-v.quit:
+		v.unmarkPending(photo)
+		return false
+	}
+}
+
+// markPending reports false when the photo is already queued with its URL.
+func (v *variantServiceImpl) markPending(photo model.Photo) bool {
+	v.pendingMu.Lock()
+	defer v.pendingMu.Unlock()
+	if url, ok := v.pending[photo.ID]; ok && url == photo.URL {
+		return false
+	}
+	v.pending[photo.ID] = photo.URL
+	return true
+}
+
+func (v *variantServiceImpl) unmarkPending(photo model.Photo) {
+	v.pendingMu.Lock()
+	defer v.pendingMu.Unlock()
+	if v.pending[photo.ID] == photo.URL {
+		delete(v.pending, photo.ID)
+	}
+}
+
+func (v *variantServiceImpl) Start() {
+	for i := 0; i < v.cfg.Workers; i++ {
+		v.wg.Add(1)
+		go v.work()
+	}
+	v.wg.Add(1)
+	go v.catchUpLoop()
+}
+
+func (v *variantServiceImpl) Stop(ctx context.Context) error {
+	v.stopOnce.Do(func() {
+		// the catch-up pass lets go of the lock before the queue is closed
+		close(v.quit)
+		v.mu.Lock()
+		v.stopped = true
+		close(v.jobs)
+		v.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		v.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		v.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (v *variantServiceImpl) work() {
+	defer v.wg.Done()
+	for photo := range v.jobs {
+		v.process(photo)
+		v.unmarkPending(photo)
+	}
+}
+
+func (v *variantServiceImpl) process(photo model.Photo) {
+	// the photos left after Stop gave up are only drained
+	if v.ctx.Err() != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(v.ctx, VARIANT_JOB_TIMEOUT)
+	defer cancel()
+	if err := v.Generate(ctx, photo); err != nil {
+		log.Println("cannot make variants of photo", photo.ID, err.Error())
+		if variantsImpossible(err) {
+			v.giveUp(ctx, photo)
+		}
+	}
+}
+
+// variantsImpossible reports whether trying again can not help.
+func variantsImpossible(err error) bool {
+	return errors.Is(err, ErrUnsupportedImageType) ||
+		errors.Is(err, ErrImageTooManyPixels) ||
+		errors.Is(err, ErrImageTooLarge) ||
+		errors.Is(err, infrastructure.ErrFetchTooLarge) ||
+		errors.Is(err, infrastructure.ErrFetchForbiddenAddress)
+}
+
+// giveUp stores no variants for the URL of the photo, so the catch-up pass
+// does not try it again until the URL changes.
+func (v *variantServiceImpl) giveUp(ctx context.Context, photo model.Photo) {
+	updated, err := v.repo.UpdatePhotoVariants(ctx, photo.ID, photo.URL, model.PhotoVariants{})
+	if err != nil {
+		log.Println("cannot clear variants of photo", photo.ID, err.Error())
+		return
+	}
+	if updated {
+		v.deleteVariants(ctx, photo.Variants)
+	}
+}
+
+func (v *variantServiceImpl) catchUpLoop() {
+	defer v.wg.Done()
+	ticker := time.NewTicker(v.cfg.CatchUpInterval)
+	defer ticker.Stop()
+	for {
+		if err := v.catchUp(v.ctx); err != nil {
+			log.Println("cannot catch up on photo variants", err.Error())
+		}
+		select {
+		case <-ticker.C:
+		case <-v.quit:
+			return
+		}
+	}
+}
+
+// catchUp queues every photo whose variants are missing or stale, it
+// returns early when the service stops.
+func (v *variantServiceImpl) catchUp(ctx context.Context) error {
+	afterID := 0
+	for {
+		photos, err := v.repo.GetPhotosWithStaleVariants(ctx, afterID, VARIANT_CATCH_UP_BATCH)
+		if err != nil {
+			return err
+		}
+		for _, photo := range photos {
+			if !v.enqueueWait(photo) {
+				return nil
+			}
+			afterID = photo.ID
+		}
+		if len(photos) < VARIANT_CATCH_UP_BATCH {
+			return nil
+		}
+	}
+}
+
+func (v *variantServiceImpl) Generate(ctx context.Context, photo model.Photo) error {
+	src, err := v.source(ctx, photo)
+	if err != nil {
+		return err
+	}
+	// check the size before decoding, a small file can claim huge dimensions
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImageType, err)
+	}
+	if imgCfg.Width*imgCfg.Height > v.cfg.MaxPixels {
+		return fmt.Errorf("%w: %dx%d", ErrImageTooManyPixels, imgCfg.Width, imgCfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImageType, err)
+	}
+	// the variants carry no exif, they are turned the way they are shown
+	img = helper.OrientImage(img, helper.ReadImageMetadata(src).Orientation)
+
+	variants := model.PhotoVariants{}
+
+	widths := slices.Clone(v.cfg.Widths)
+	slices.Sort(widths)
+	for _, width := range slices.Compact(widths) {
+		// never upscale
+		if width >= img.Bounds().Dx() {
+			break
+		}
+		resized := helper.ResizeImage(img, width)
+		for _, format := range v.cfg.Formats {
+			// a very tall photo has no webp variant at the larger widths
+			if format == model.VARIANT_FORMAT_WEBP && resized.Bounds().Dy() > helper.WEBP_MAX_SIZE {
+				continue
+			}
+			variant, err := v.store(ctx, photo.ID, resized, format)
+			if err != nil {
+				// the blobs of a failed run are not referenced by anything
+				v.deleteVariants(ctx, variants)
+				return err
+			}
+			variants = append(variants, variant)
+		}
+	}
+
+	updated, err := v.repo.UpdatePhotoVariants(ctx, photo.ID, photo.URL, variants)
+	if err != nil {
+		v.deleteVariants(ctx, variants)
+		return err
+	}
+	if !updated {
+		// the photo got another image or was deleted meanwhile
+		v.deleteVariants(ctx, variants)
+		return nil
+	}
+	v.deleteVariants(ctx, photo.Variants)
+	return nil
+}
+
+func (v *variantServiceImpl) deleteVariants(ctx context.Context, variants model.PhotoVariants) {
+	for _, variant := range variants {
+		if err := v.blobs.Delete(context.WithoutCancel(ctx), variant.Key); err != nil {
+			log.Println("cannot delete variant blob", variant.Key, err.Error())
+		}
+	}
+}
+
+// source reads the image of the photo, from the blob store when it was
+// uploaded and its URL was not changed since.
+func (v *variantServiceImpl) source(ctx context.Context, photo model.Photo) ([]byte, error) {
+	if photo.StorageKey != "" && photo.URL == strings.TrimSuffix(v.storage.PublicURL, "/")+"/"+photo.StorageKey {
+		content, _, err := v.blobs.Get(ctx, photo.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+		src, err := io.ReadAll(io.LimitReader(content, v.storage.MaxUploadBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(src)) > v.storage.MaxUploadBytes {
+			return nil, ErrImageTooLarge
+		}
+		return src, nil
+	}
+	return v.fetcher.Fetch(ctx, photo.URL)
+}
+
+func (v *variantServiceImpl) store(ctx context.Context, photoID int, img *image.RGBA, format string) (model.PhotoVariant, error) {
+	buf := &bytes.Buffer{}
+	contentType := ""
+	switch format {
+	case model.VARIANT_FORMAT_JPEG:
+		// jpeg has no alpha, transparent pixels would turn black
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		if err := jpeg.Encode(buf, flat, &jpeg.Options{Quality: v.cfg.JPEGQuality}); err != nil {
+			return model.PhotoVariant{}, err
+		}
+		contentType = "image/jpeg"
+	case model.VARIANT_FORMAT_PNG:
+		if err := png.Encode(buf, img); err != nil {
+			return model.PhotoVariant{}, err
+		}
+		contentType = "image/png"
+	case model.VARIANT_FORMAT_WEBP:
+		// lossless, the standard library has no webp encoder to build on
+		if err := helper.EncodeWebP(buf, img); err != nil {
+			return model.PhotoVariant{}, err
+		}
+		contentType = "image/webp"
+	default:
+		return model.PhotoVariant{}, fmt.Errorf("unknown variant format %q", format)
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return model.PhotoVariant{}, err
+	}
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	key := "variants/" + strconv.Itoa(photoID) + "/" + strconv.Itoa(width) + "w-" + hex.EncodeToString(random) + "." + format
+	size := int64(buf.Len())
+	if err := v.blobs.Put(ctx, key, buf, size, contentType); err != nil {
+		return model.PhotoVariant{}, err
+	}
+	return model.PhotoVariant{
+		Width:  width,
+		Height: height,
+		Format: format,
+		URL:    strings.TrimSuffix(v.storage.PublicURL, "/") + "/" + key,
+		Size:   size,
+		Key:    key,
+	}, nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"mygram/config"
+	"mygram/infrastructure"
+	imocks "mygram/infrastructure/mocks"
+	"mygram/model"
+	"mygram/pkg/helper"
+	"mygram/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGenerateVariants(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	for i := range src.Pix {
+		src.Pix[i] = 0xff
+	}
+	source := &bytes.Buffer{}
+	assert.Nil(t, png.Encode(source, src))
+
+	storage := config.StorageConfig{
+		PublicURL:      "http://localhost:3000/blobs",
+		MaxUploadBytes: 1 << 20,
+		Variants: config.VariantsConfig{
+			Widths:      []int{200, 40, 40},
+			Formats:     []string{model.VARIANT_FORMAT_JPEG, model.VARIANT_FORMAT_PNG, model.VARIANT_FORMAT_WEBP},
+			JPEGQuality: 80,
+			MaxPixels:   10_000,
+		},
+	}
+	newStore := func(t *testing.T) infrastructure.BlobStore {
+		blobs, err := infrastructure.NewLocalBlobStore(t.TempDir())
+		assert.Nil(t, err)
+		assert.Nil(t, blobs.Put(context.Background(), "photos/1/a.png", bytes.NewReader(source.Bytes()), int64(source.Len()), "image/png"))
+		assert.Nil(t, blobs.Put(context.Background(), "variants/10/old.jpeg", bytes.NewReader([]byte("old")), 3, "image/jpeg"))
+		return blobs
+	}
+	photo := model.Photo{
+		ID:         10,
+		URL:        "http://localhost:3000/blobs/photos/1/a.png",
+		StorageKey: "photos/1/a.png",
+		Variants:   model.PhotoVariants{{Width: 40, Key: "variants/10/old.jpeg"}},
+	}
+
+	t.Run("success uploaded photo", func(t *testing.T) {
+		blobs := newStore(t)
+		repoMock := mocks.NewPhotosQuery(t)
+		var stored model.PhotoVariants
+		repoMock.On("UpdatePhotoVariants", mock.Anything, 10, photo.URL, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(3).(model.PhotoVariants) }).
+			Return(true, nil)
+
+		svc := NewVariantService(repoMock, blobs, nil, storage)
+		assert.Nil(t, svc.Generate(context.Background(), photo))
+
+		// nothing wider than the photo, one per format for the rest
+		assert.Equal(t, 3, len(stored))
+		for i, format := range []string{model.VARIANT_FORMAT_JPEG, model.VARIANT_FORMAT_PNG, model.VARIANT_FORMAT_WEBP} {
+			assert.Equal(t, format, stored[i].Format)
+			assert.Equal(t, 40, stored[i].Width)
+			assert.Equal(t, 20, stored[i].Height)
+			assert.Equal(t, "http://localhost:3000/blobs/"+stored[i].Key, stored[i].URL)
+
+			content, info, err := blobs.Get(context.Background(), stored[i].Key)
+			assert.Nil(t, err)
+			assert.Equal(t, stored[i].Size, info.Size)
+			decoded, decodedFormat, err := image.Decode(content)
+			content.Close()
+			assert.Nil(t, err)
+			assert.Equal(t, format, decodedFormat)
+			r, g, b, _ := decoded.At(20, 10).RGBA()
+			assert.Greater(t, r+g+b, uint32(3*0xf000))
+		}
+
+		_, _, err := blobs.Get(context.Background(), "variants/10/old.jpeg")
+		assert.ErrorIs(t, err, infrastructure.ErrBlobNotFound)
+	})
+
+	t.Run("success photo changed meanwhile keeps old variants", func(t *testing.T) {
+		blobs := newStore(t)
+		repoMock := mocks.NewPhotosQuery(t)
+		var stored model.PhotoVariants
+		repoMock.On("UpdatePhotoVariants", mock.Anything, 10, photo.URL, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(3).(model.PhotoVariants) }).
+			Return(false, nil)
+
+		svc := NewVariantService(repoMock, blobs, nil, storage)
+		assert.Nil(t, svc.Generate(context.Background(), photo))
+
+		_, _, err := blobs.Get(context.Background(), stored[0].Key)
+		assert.ErrorIs(t, err, infrastructure.ErrBlobNotFound)
+		_, _, err = blobs.Get(context.Background(), "variants/10/old.jpeg")
+		assert.Nil(t, err)
+	})
+
+	t.Run("success transparent pixels are white in jpeg", func(t *testing.T) {
+		transparent := &bytes.Buffer{}
+		assert.Nil(t, png.Encode(transparent, image.NewNRGBA(image.Rect(0, 0, 100, 50))))
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/a.png").Return(transparent.Bytes(), nil)
+		blobs := newStore(t)
+		repoMock := mocks.NewPhotosQuery(t)
+		var stored model.PhotoVariants
+		repoMock.On("UpdatePhotoVariants", mock.Anything, 11, "https://example.com/a.png", mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(3).(model.PhotoVariants) }).
+			Return(true, nil)
+
+		svc := NewVariantService(repoMock, blobs, fetcherMock, storage)
+		assert.Nil(t, svc.Generate(context.Background(), model.Photo{ID: 11, URL: "https://example.com/a.png"}))
+
+		content, _, err := blobs.Get(context.Background(), stored[0].Key)
+		assert.Nil(t, err)
+		defer content.Close()
+		decoded, err := jpeg.Decode(content)
+		assert.Nil(t, err)
+		assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBAModel.Convert(decoded.At(20, 10)))
+	})
+
+	t.Run("error too many pixels", func(t *testing.T) {
+		large := &bytes.Buffer{}
+		assert.Nil(t, png.Encode(large, image.NewGray(image.Rect(0, 0, 200, 100))))
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/large.png").Return(large.Bytes(), nil)
+
+		svc := NewVariantService(nil, nil, fetcherMock, storage)
+		err := svc.Generate(context.Background(), model.Photo{ID: 12, URL: "https://example.com/large.png"})
+		assert.ErrorIs(t, err, ErrImageTooManyPixels)
+	})
+
+	t.Run("error not an image", func(t *testing.T) {
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/a.txt").Return([]byte("text"), nil)
+
+		svc := NewVariantService(nil, nil, fetcherMock, storage)
+		err := svc.Generate(context.Background(), model.Photo{ID: 13, URL: "https://example.com/a.txt"})
+		assert.ErrorIs(t, err, ErrUnsupportedImageType)
+	})
+}
+
+func TestVariantWorkers(t *testing.T) {
+	storage := config.StorageConfig{Variants: config.VariantsConfig{Workers: 2, QueueSize: 1, CatchUpInterval: time.Hour}}
+
+	t.Run("success queued photos are done before stop", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetPhotosWithStaleVariants", mock.Anything, 0, VARIANT_CATCH_UP_BATCH).Return([]model.Photo{}, nil)
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/a.txt").Return(nil, io.ErrUnexpectedEOF).Once()
+
+		svc := NewVariantService(repoMock, nil, fetcherMock, storage)
+		assert.True(t, svc.Enqueue(model.Photo{ID: 1, URL: "https://example.com/a.txt"}))
+		// the queue holds one photo until the workers run
+		assert.False(t, svc.Enqueue(model.Photo{ID: 2, URL: "https://example.com/a.txt"}))
+		svc.Start()
+		assert.Nil(t, svc.Stop(context.Background()))
+		assert.False(t, svc.Enqueue(model.Photo{ID: 3, URL: "https://example.com/a.txt"}))
+	})
+
+	t.Run("error stop deadline cancels running jobs", func(t *testing.T) {
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetPhotosWithStaleVariants", mock.Anything, 0, VARIANT_CATCH_UP_BATCH).Return([]model.Photo{}, nil)
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/slow.png").
+			Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+			Return(nil, context.Canceled).Once()
+
+		svc := NewVariantService(repoMock, nil, fetcherMock, storage)
+		svc.Start()
+		assert.True(t, svc.Enqueue(model.Photo{ID: 1, URL: "https://example.com/slow.png"}))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, svc.Stop(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("success catch-up queues stale photos once", func(t *testing.T) {
+		stale := []model.Photo{{ID: 4, URL: "https://example.com/a.png"}, {ID: 9, URL: "https://example.com/b.png"}}
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("GetPhotosWithStaleVariants", mock.Anything, 0, VARIANT_CATCH_UP_BATCH).Return(stale, nil).Twice()
+
+		svc := NewVariantService(repoMock, nil, nil, config.StorageConfig{Variants: config.VariantsConfig{QueueSize: 10}}).(*variantServiceImpl)
+		assert.Nil(t, svc.catchUp(context.Background()))
+		// the photos still waiting are not queued again
+		assert.Nil(t, svc.catchUp(context.Background()))
+		assert.Equal(t, 2, len(svc.jobs))
+		assert.Equal(t, 4, (<-svc.jobs).ID)
+		assert.Equal(t, 9, (<-svc.jobs).ID)
+	})
+
+	t.Run("success image without variants is not tried again", func(t *testing.T) {
+		blobMock := imocks.NewBlobStore(t)
+		blobMock.On("Delete", mock.Anything, "variants/13/old.jpeg").Return(nil)
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("UpdatePhotoVariants", mock.Anything, 13, "https://example.com/a.txt", model.PhotoVariants{}).Return(true, nil)
+		fetcherMock := imocks.NewFetcher(t)
+		fetcherMock.On("Fetch", mock.Anything, "https://example.com/a.txt").Return([]byte("text"), nil)
+
+		svc := NewVariantService(repoMock, blobMock, fetcherMock, storage).(*variantServiceImpl)
+		svc.process(model.Photo{ID: 13, URL: "https://example.com/a.txt", Variants: model.PhotoVariants{{Width: 40, Key: "variants/13/old.jpeg"}}})
+	})
+
+	t.Run("success webp upload gets variants", func(t *testing.T) {
+		src := image.NewNRGBA(image.Rect(0, 0, 100, 50))
+		for i := range src.Pix {
+			src.Pix[i] = 0xff
+		}
+		upload := &bytes.Buffer{}
+		assert.Nil(t, helper.EncodeWebP(upload, src))
+
+		storage := config.StorageConfig{
+			PublicURL:      "http://localhost:3000/blobs",
+			MaxUploadBytes: 1 << 20,
+			AllowedTypes:   []string{"image/webp"},
+			Variants: config.VariantsConfig{
+				Widths:          []int{40},
+				Formats:         []string{model.VARIANT_FORMAT_JPEG},
+				JPEGQuality:     80,
+				Workers:         1,
+				QueueSize:       1,
+				MaxPixels:       10_000,
+				CatchUpInterval: time.Hour,
+			},
+		}
+		blobs, err := infrastructure.NewLocalBlobStore(t.TempDir())
+		assert.Nil(t, err)
+		repoMock := mocks.NewPhotosQuery(t)
+		repoMock.On("CreatePhoto", mock.Anything, mock.Anything).Return(func(ctx context.Context, photo *model.Photo) (*model.Photo, error) {
+			photo.ID = 10
+			return photo, nil
+		})
+		repoMock.On("GetPhotosWithStaleVariants", mock.Anything, 0, VARIANT_CATCH_UP_BATCH).Return([]model.Photo{}, nil)
+		var stored model.PhotoVariants
+		repoMock.On("UpdatePhotoVariants", mock.Anything, 10, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(3).(model.PhotoVariants) }).
+			Return(true, nil)
+
+		variants := NewVariantService(repoMock, blobs, nil, storage)
+		variants.Start()
+		photos := NewPhotosService(repoMock, nil, blobs, storage, variants)
+		photo, err := photos.UploadPhoto(context.Background(), model.UploadPhoto{Title: "title"}, bytes.NewReader(upload.Bytes()), int64(upload.Len()), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 100, photo.Metadata.Width)
+		assert.Nil(t, variants.Stop(context.Background()))
+
+		assert.Equal(t, 1, len(stored))
+		assert.Equal(t, 40, stored[0].Width)
+		assert.Equal(t, 20, stored[0].Height)
+	})
+}